@@ -119,6 +119,7 @@ func main() {
 		// 数据库表操作
 		apiGroup.POST("/tables", api.GetTables)
 		apiGroup.POST("/columns", api.GetColumns)
+		apiGroup.POST("/indexes", api.GetIndexes)
 
 		// 代码生成配置
 		apiGroup.GET("/generator-configs", api.GetGeneratorConfigs)
@@ -131,6 +132,22 @@ func main() {
 
 		// 自定义片段预览
 		apiGroup.POST("/snippet/preview", api.PreviewSnippet)
+		apiGroup.POST("/snippet/parse-method", api.ParseSnippetMethod)
+		apiGroup.POST("/snippet/import-sql", api.ImportSnippetSQL)
+		apiGroup.POST("/snippet/explain", api.ExplainSnippet)
+		apiGroup.POST("/snippet/execute", api.ExecuteSnippet)
+		apiGroup.POST("/snippet/evaluate", api.EvaluateSnippetSQL)
+		apiGroup.POST("/snippet/merge-mapper", api.MergeMapperSnippets)
+		apiGroup.POST("/snippet/import-mapper-xml", api.ImportMapperXML)
+
+		// 片段库
+		apiGroup.GET("/snippets", api.GetSavedSnippets)
+		apiGroup.POST("/snippets", api.CreateSavedSnippet)
+		apiGroup.PUT("/snippets/:id", api.UpdateSavedSnippet)
+		apiGroup.DELETE("/snippets/:id", api.DeleteSavedSnippet)
+
+		// 命名规则预览
+		apiGroup.POST("/naming/preview", api.PreviewNaming)
 
 		// 账号管理
 		apiGroup.POST("/account/update", api.HandleUpdateAccountAPI)
@@ -6,12 +6,15 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/yourusername/mybatis-generator-gui-go/internal/config"
+	"github.com/yourusername/mybatis-generator-gui-go/internal/generator"
 )
 
 func init() {
@@ -261,6 +264,25 @@ func TestGetColumns_InvalidJSON(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 }
 
+// 测试获取索引信息
+func TestGetIndexes(t *testing.T) {
+	router := gin.Default()
+	router.POST("/api/indexes", GetIndexes)
+
+	requestData := map[string]interface{}{
+		"databaseId": 999, // 不存在的ID
+		"tableName":  "test_table",
+	}
+
+	jsonData, _ := json.Marshal(requestData)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/indexes", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
 // ==================== Config API Tests ====================
 
 // 测试获取代码生成配置列表
@@ -355,6 +377,30 @@ func TestGenerateCode_InvalidDatabase(t *testing.T) {
 	assert.Equal(t, http.StatusNotFound, w.Code)
 }
 
+// 测试片段预览（失败场景 - 数据库不存在时不回退到默认方言）
+func TestPreviewSnippet_InvalidDatabase(t *testing.T) {
+	router := gin.Default()
+	router.POST("/api/snippet/preview", PreviewSnippet)
+
+	requestData := map[string]interface{}{
+		"databaseId": 999, // 不存在的ID
+		"tableName":  "user",
+		"mapperName": "UserMapper",
+		"modelType":  "com.example.User",
+		"snippetConfigs": []map[string]interface{}{
+			{"operation": "select", "methodName": "selectAll"},
+		},
+	}
+
+	jsonData, _ := json.Marshal(requestData)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/snippet/preview", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
 // 测试代码生成 - 无效JSON
 func TestGenerateCode_InvalidJSON(t *testing.T) {
 	router := gin.Default()
@@ -368,6 +414,352 @@ func TestGenerateCode_InvalidJSON(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 }
 
+// 测试命名规则预览
+func TestPreviewNaming(t *testing.T) {
+	router := gin.Default()
+	router.POST("/api/naming/preview", PreviewNaming)
+
+	requestData := map[string]interface{}{
+		"tableNames": []string{"t_user_info", "sys_role"},
+		"config": map[string]interface{}{
+			"naming": map[string]interface{}{
+				"tablePrefixes": []string{"t_", "sys_"},
+				"modelSuffix":   "Entity",
+				"mapperSuffix":  "Dao",
+			},
+		},
+	}
+
+	jsonData, _ := json.Marshal(requestData)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/naming/preview", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp struct {
+		Results []NamingPreview `json:"results"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Len(t, resp.Results, 2)
+	assert.Equal(t, "UserInfoEntity", resp.Results[0].DomainObjectName)
+	assert.Equal(t, "UserInfoDao", resp.Results[0].MapperName)
+	assert.Equal(t, "RoleEntity", resp.Results[1].DomainObjectName)
+}
+
+// 测试命名规则预览 - 无效正则
+func TestPreviewNaming_InvalidPattern(t *testing.T) {
+	router := gin.Default()
+	router.POST("/api/naming/preview", PreviewNaming)
+
+	requestData := map[string]interface{}{
+		"tableNames": []string{"t_user"},
+		"config": map[string]interface{}{
+			"naming": map[string]interface{}{
+				"tableRenames": []map[string]string{{"pattern": "(", "replacement": ""}},
+			},
+		},
+	}
+
+	jsonData, _ := json.Marshal(requestData)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/naming/preview", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+// 测试多表片段配置的整理
+func TestResolveTableSnippets(t *testing.T) {
+	snippet := config.SnippetConfig{MethodName: "findByStatus"}
+
+	result, err := resolveTableSnippets([]string{"user", "order"}, nil,
+		map[string][]config.SnippetConfig{"USER": {snippet}, "order": {snippet, snippet}})
+	assert.NoError(t, err)
+	assert.Len(t, result["user"], 1)
+	assert.Len(t, result["order"], 2)
+
+	result, err = resolveTableSnippets([]string{"user"}, []config.SnippetConfig{snippet}, nil)
+	assert.NoError(t, err)
+	assert.Len(t, result["user"], 1)
+
+	_, err = resolveTableSnippets([]string{"user", "order"}, []config.SnippetConfig{snippet}, nil)
+	assert.Error(t, err)
+
+	_, err = resolveTableSnippets([]string{"user"}, nil, map[string][]config.SnippetConfig{"product": {snippet}})
+	assert.Error(t, err)
+}
+
+// ==================== Snippet Library API Tests ====================
+
+// 测试片段库的增删改查
+func TestSavedSnippetsCRUD(t *testing.T) {
+	router := gin.Default()
+	router.GET("/api/snippets", GetSavedSnippets)
+	router.POST("/api/snippets", CreateSavedSnippet)
+	router.PUT("/api/snippets/:id", UpdateSavedSnippet)
+	router.DELETE("/api/snippets/:id", DeleteSavedSnippet)
+
+	dbID := int(time.Now().UnixNano() % 1000000)
+	snippet := config.SavedSnippet{
+		DatabaseID:  dbID,
+		TableName:   "user",
+		Description: "按状态查询",
+		Config:      config.SnippetConfig{MethodName: "findByStatus", Operation: config.OperationSelect},
+	}
+	jsonData, _ := json.Marshal(snippet)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/snippets", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var created config.SavedSnippet
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	assert.NotZero(t, created.ID)
+	assert.Equal(t, "findByStatus", created.Name)
+
+	// 同一张表内名称重复（表名忽略大小写）
+	snippet.TableName = "USER"
+	jsonData, _ = json.Marshal(snippet)
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("POST", "/api/snippets", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	created.Description = "按状态查询（已修改）"
+	jsonData, _ = json.Marshal(created)
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("PUT", fmt.Sprintf("/api/snippets/%d", created.ID), bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", fmt.Sprintf("/api/snippets?databaseId=%d&tableName=User", dbID), nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var listed []config.SavedSnippet
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &listed))
+	assert.Len(t, listed, 1)
+	assert.Equal(t, "按状态查询（已修改）", listed[0].Description)
+	assert.Equal(t, config.OperationSelect, listed[0].Config.Operation)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("DELETE", fmt.Sprintf("/api/snippets/%d", created.ID), nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("PUT", fmt.Sprintf("/api/snippets/%d", created.ID), bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+// 测试片段库与请求片段的合并规则
+func TestHasSnippetMethod(t *testing.T) {
+	snippets := []config.SnippetConfig{{MethodName: "findByStatus"}, {}}
+	assert.True(t, hasSnippetMethod(snippets, "findByStatus"))
+	assert.False(t, hasSnippetMethod(snippets, "findByName"))
+	assert.False(t, hasSnippetMethod(snippets, ""))
+
+	// 未指定方法名的片段按推导出的方法名比较
+	derived := config.SnippetConfig{
+		Operation:   config.OperationSelect,
+		WhereFields: []config.SnippetField{{ColumnName: "status", FieldName: "status", JavaType: "Integer", JdbcType: "INTEGER", Operator: "="}},
+	}
+	saved := derived
+	assert.True(t, hasSnippetMethod([]config.SnippetConfig{derived}, generator.SnippetMethodName(&saved)))
+	assert.True(t, hasSnippetMethod([]config.SnippetConfig{derived}, "selectByStatus"))
+}
+
+// 测试片段与已有方法重名时不写入文件，并逐个返回检测结果
+func TestAppendSnippetsToFiles_Conflict(t *testing.T) {
+	dir := t.TempDir()
+	javaFile := filepath.Join(dir, "UserMapper.java")
+	xmlFile := filepath.Join(dir, "UserMapper.xml")
+	javaContent := "public interface UserMapper {\n    User selectByPrimaryKey(Long id);\n}\n"
+	xmlContent := "<mapper namespace=\"UserMapper\">\n    <select id=\"selectByPrimaryKey\">SELECT 1</select>\n</mapper>\n"
+	os.WriteFile(javaFile, []byte(javaContent), 0644)
+	os.WriteFile(xmlFile, []byte(xmlContent), 0644)
+
+	snippets := []config.SnippetConfig{
+		{MethodName: "listAll", Operation: config.OperationSelect},
+		{MethodName: "selectByPrimaryKey", Operation: config.OperationSelect},
+	}
+	files := []string{javaFile, xmlFile}
+	_, placed, err := appendSnippetsToFiles(files, "user", "UserMapper", "com.example.User", generator.NewDialect(nil), snippets, "", false)
+	var conflictErr *generator.SnippetConflictError
+	assert.ErrorAs(t, err, &conflictErr)
+	assert.Len(t, placed, 2)
+	assert.Empty(t, placed[0].Conflicts)
+	assert.Len(t, placed[1].Conflicts, 2)
+	written, _ := os.ReadFile(javaFile)
+	assert.Equal(t, javaContent, string(written))
+
+	_, placed, err = appendSnippetsToFiles(files, "user", "UserMapper", "com.example.User", generator.NewDialect(nil), snippets, config.SnippetConflictRename, false)
+	assert.NoError(t, err)
+	assert.Equal(t, "selectByPrimaryKey2", placed[1].MethodName)
+	assert.Equal(t, "selectByPrimaryKey", placed[1].OriginalName)
+	written, _ = os.ReadFile(xmlFile)
+	assert.Contains(t, string(written), `id="selectByPrimaryKey2"`)
+}
+
+// 测试参数对象模式的参数类写入实体类目录，风格跟随 Lombok 配置
+func TestAppendSnippetsToFiles_ParamObject(t *testing.T) {
+	dir := t.TempDir()
+	javaFile := filepath.Join(dir, "UserMapper.java")
+	xmlFile := filepath.Join(dir, "UserMapper.xml")
+	modelDir := filepath.Join(dir, "model")
+	os.MkdirAll(modelDir, 0755)
+	modelFile := filepath.Join(modelDir, "User.java")
+	os.WriteFile(javaFile, []byte("package com.example.mapper;\n\npublic interface UserMapper {\n}\n"), 0644)
+	os.WriteFile(xmlFile, []byte("<mapper namespace=\"com.example.mapper.UserMapper\">\n</mapper>\n"), 0644)
+	os.WriteFile(modelFile, []byte("package com.example.model;\n"), 0644)
+
+	snippets := []config.SnippetConfig{{
+		MethodName:  "search",
+		Operation:   config.OperationSelect,
+		ParamObject: true,
+		WhereFields: []config.SnippetField{{ColumnName: "status", FieldName: "status", JavaType: "Integer", JdbcType: "INTEGER"}},
+	}}
+	files, _, err := appendSnippetsToFiles([]string{javaFile, xmlFile, modelFile}, "user", "UserMapper", "com.example.model.User", generator.NewDialect(nil), snippets, "", true)
+	assert.NoError(t, err)
+	assert.Contains(t, files, filepath.Join(modelDir, "SearchQuery.java"))
+
+	paramCode, _ := os.ReadFile(filepath.Join(modelDir, "SearchQuery.java"))
+	assert.Contains(t, string(paramCode), "@Data\npublic class SearchQuery")
+	written, _ := os.ReadFile(javaFile)
+	assert.Contains(t, string(written), "import com.example.model.SearchQuery;\n")
+	assert.Contains(t, string(written), "List<User> search(SearchQuery query);")
+}
+
+// 测试将片段合并到上传的已有 Mapper
+func TestMergeMapperSnippets(t *testing.T) {
+	router := gin.Default()
+	router.POST("/api/snippet/merge-mapper", MergeMapperSnippets)
+
+	body := map[string]interface{}{
+		"tableName":   "user",
+		"javaContent": "package com.example.mapper;\n\nimport com.example.User;\n\npublic interface UserMapper {\n    User selectByPrimaryKey(Long id);\n}\n",
+		"xmlContent":  "<mapper namespace=\"com.example.mapper.UserMapper\">\n    <resultMap id=\"BaseResultMap\" type=\"com.example.User\"/>\n    <select id=\"selectByPrimaryKey\" resultMap=\"BaseResultMap\">SELECT * FROM user WHERE id = #{id}</select>\n</mapper>\n",
+		"snippetConfigs": []config.SnippetConfig{
+			{MethodName: "listAll", Operation: config.OperationSelect},
+			{MethodName: "selectByPrimaryKey", Operation: config.OperationSelect},
+		},
+	}
+	post := func() (*httptest.ResponseRecorder, map[string]interface{}) {
+		jsonData, _ := json.Marshal(body)
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/api/snippet/merge-mapper", bytes.NewBuffer(jsonData))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		var result map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &result)
+		return w, result
+	}
+
+	w, result := post()
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Len(t, result["snippets"], 2)
+
+	body["conflictStrategy"] = config.SnippetConflictRename
+	w, result = post()
+	assert.Equal(t, http.StatusOK, w.Code)
+	javaFile := result["javaFile"].(map[string]interface{})
+	assert.Equal(t, "UserMapper.java", javaFile["name"])
+	assert.Contains(t, javaFile["content"], "import java.util.List;\n")
+	assert.Contains(t, javaFile["content"], "List<User> selectByPrimaryKey2();")
+	xmlFile := result["xmlFile"].(map[string]interface{})
+	assert.Contains(t, xmlFile["content"], `<select id="listAll"`)
+
+	body["conflictStrategy"] = "overwrite"
+	w, _ = post()
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+// 测试导入 Mapper XML 的参数校验
+func TestImportMapperXML_Validation(t *testing.T) {
+	router := gin.Default()
+	router.POST("/api/snippet/import-mapper-xml", ImportMapperXML)
+
+	for _, body := range []map[string]interface{}{
+		{"databaseId": 1, "tableName": "user", "xml": " "},
+		{"databaseId": 1, "tableName": "user", "xml": `<mapper><select id="a">SELECT 1</select></mapper>`},
+	} {
+		jsonData, _ := json.Marshal(body)
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/api/snippet/import-mapper-xml", bytes.NewBuffer(jsonData))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	}
+}
+
+// 测试未开启试运行的连接拒绝执行片段
+func TestExecuteSnippet_RequiresAllowExecution(t *testing.T) {
+	router := gin.Default()
+	router.POST("/api/snippet/execute", ExecuteSnippet)
+
+	name := fmt.Sprintf("test_sandbox_%d", time.Now().UnixNano())
+	assert.NoError(t, config.SaveDatabaseConfig(&config.DatabaseConfig{Name: name, DbType: config.DbTypeMySQL}, false))
+	configs, err := config.LoadDatabaseConfigs()
+	assert.NoError(t, err)
+	var dbID int
+	for _, cfg := range configs {
+		if cfg.Name == name {
+			dbID = cfg.ID
+		}
+	}
+	defer config.DeleteDatabaseConfig(dbID)
+
+	body := map[string]interface{}{
+		"databaseId":    dbID,
+		"tableName":     "user",
+		"snippetConfig": config.SnippetConfig{Operation: config.OperationDelete},
+	}
+	jsonData, _ := json.Marshal(body)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/snippet/execute", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+// 测试动态SQL求值
+func TestEvaluateSnippetSQL(t *testing.T) {
+	router := gin.Default()
+	router.POST("/api/snippet/evaluate", EvaluateSnippetSQL)
+
+	body := map[string]interface{}{
+		"xml":         `<select id="findByName">SELECT * FROM user <where><if test="name != null">AND name = #{name}</if></where></select>`,
+		"statementId": "findByName",
+		"params":      map[string]interface{}{"name": "tom"},
+	}
+	jsonData, _ := json.Marshal(body)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/snippet/evaluate", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var result map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &result)
+	assert.Equal(t, "SELECT * FROM user WHERE name = ?", result["sql"])
+	assert.Len(t, result["params"], 1)
+
+	body["statementId"] = "missing"
+	jsonData, _ = json.Marshal(body)
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("POST", "/api/snippet/evaluate", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
 // 测试下载不存在的文件
 func TestDownloadCode_NotFound(t *testing.T) {
 	router := gin.Default()
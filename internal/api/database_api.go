@@ -102,21 +102,11 @@ func GetTables(c *gin.Context) {
 		return
 	}
 
-	// 加载数据库配置
-	configs, err := config.LoadDatabaseConfigs()
+	dbConfig, err := findDatabaseConfig(req.DatabaseID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
-
-	var dbConfig *config.DatabaseConfig
-	for _, cfg := range configs {
-		if cfg.ID == req.DatabaseID {
-			dbConfig = cfg
-			break
-		}
-	}
-
 	if dbConfig == nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "数据库配置不存在"})
 		return
@@ -151,21 +141,11 @@ func GetColumns(c *gin.Context) {
 		return
 	}
 
-	// 加载数据库配置
-	configs, err := config.LoadDatabaseConfigs()
+	dbConfig, err := findDatabaseConfig(req.DatabaseID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
-
-	var dbConfig *config.DatabaseConfig
-	for _, cfg := range configs {
-		if cfg.ID == req.DatabaseID {
-			dbConfig = cfg
-			break
-		}
-	}
-
 	if dbConfig == nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "数据库配置不存在"})
 		return
@@ -187,3 +167,56 @@ func GetColumns(c *gin.Context) {
 
 	c.JSON(http.StatusOK, columns)
 }
+
+// GetIndexes 获取表的索引信息
+func GetIndexes(c *gin.Context) {
+	var req struct {
+		DatabaseID int    `json:"databaseId"`
+		TableName  string `json:"tableName"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	dbConfig, err := findDatabaseConfig(req.DatabaseID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if dbConfig == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "数据库配置不存在"})
+		return
+	}
+
+	// 连接数据库获取索引信息
+	connector := database.NewConnector(dbConfig)
+	if err := connector.Connect(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	defer connector.Close()
+
+	indexes, err := connector.GetTableIndexes(req.TableName)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, indexes)
+}
+
+// findDatabaseConfig 根据ID查找数据库配置，不存在时返回 nil
+func findDatabaseConfig(id int) (*config.DatabaseConfig, error) {
+	configs, err := config.LoadDatabaseConfigs()
+	if err != nil {
+		return nil, err
+	}
+	for _, cfg := range configs {
+		if cfg.ID == id {
+			return cfg, nil
+		}
+	}
+	return nil, nil
+}
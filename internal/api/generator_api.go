@@ -1,6 +1,7 @@
 package api
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"math/rand"
@@ -14,9 +15,18 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/yourusername/mybatis-generator-gui-go/internal/config"
+	"github.com/yourusername/mybatis-generator-gui-go/internal/database"
 	"github.com/yourusername/mybatis-generator-gui-go/internal/generator"
 )
 
+// NamingPreview 单张表的命名预览结果
+type NamingPreview struct {
+	TableName        string            `json:"tableName"`
+	DomainObjectName string            `json:"domainObjectName"`
+	MapperName       string            `json:"mapperName"`
+	Fields           map[string]string `json:"fields,omitempty"` // 列名 -> 属性名（提供 databaseId 时返回）
+}
+
 // 存储生成的ZIP文件映射（线程安全）
 var (
 	generatedZips   = make(map[string]string)
@@ -26,10 +36,14 @@ var (
 // GenerateCode 生成代码（支持可选的自定义片段合并）
 func GenerateCode(c *gin.Context) {
 	var req struct {
-		DatabaseID     int                     `json:"databaseId"`
-		TableNames     []string                `json:"tableNames"`
-		Config         config.GeneratorConfig  `json:"config"`
-		SnippetConfigs []config.SnippetConfig  `json:"snippetConfigs"` // 可选，Tab2自定义片段
+		DatabaseID          int                               `json:"databaseId"`
+		TableNames          []string                          `json:"tableNames"`
+		Config              config.GeneratorConfig            `json:"config"`
+		TableOverrides      map[string]config.TableOverride   `json:"tableOverrides"`      // 可选，单表覆盖配置（优先于 config 中保存的）
+		SnippetConfigs      []config.SnippetConfig            `json:"snippetConfigs"`      // 可选，Tab2自定义片段（仅单表）
+		TableSnippetConfigs map[string][]config.SnippetConfig `json:"tableSnippetConfigs"` // 可选，按表名指定的自定义片段
+		SkipSavedSnippets   bool                              `json:"skipSavedSnippets"`   // 可选，不自动应用片段库中保存的片段
+		ConflictStrategy    string                            `json:"conflictStrategy"`    // 可选，片段重名处理策略：error（默认）/ rename
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -43,14 +57,43 @@ func GenerateCode(c *gin.Context) {
 		return
 	}
 
-	// 有片段配置时只允许单张表
-	if len(req.SnippetConfigs) > 0 && len(req.TableNames) > 1 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "使用自定义片段时仅支持单张表"})
+	if !isValidConflictStrategy(req.ConflictStrategy) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "未知的重名处理策略: " + req.ConflictStrategy})
+		return
+	}
+
+	// 整理各表的片段配置（snippetConfigs 仅用于单表，多表需按表名指定）
+	tableSnippets, err := resolveTableSnippets(req.TableNames, req.SnippetConfigs, req.TableSnippetConfigs)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
+	// 合并请求中的单表覆盖配置
+	if len(req.TableOverrides) > 0 {
+		merged := make(map[string]config.TableOverride, len(req.Config.TableOverrides)+len(req.TableOverrides))
+		for name, override := range req.Config.TableOverrides {
+			merged[name] = override
+		}
+		for name, override := range req.TableOverrides {
+			merged[name] = override
+		}
+		req.Config.TableOverrides = merged
+	}
+
+	// 校验命名规则
+	namer, err := generator.NewNamer(req.Config.Naming)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	snippetCount := 0
+	for _, snippets := range tableSnippets {
+		snippetCount += len(snippets)
+	}
 	log.Printf("INFO: 开始生成代码 - DatabaseID: %d, Tables: %v, Snippets: %d",
-		req.DatabaseID, req.TableNames, len(req.SnippetConfigs))
+		req.DatabaseID, req.TableNames, snippetCount)
 
 	// 获取当前工作目录
 	currentDir, err := os.Getwd()
@@ -94,14 +137,75 @@ func GenerateCode(c *gin.Context) {
 
 	log.Printf("INFO: 使用数据库配置: %s (%s)", dbConfig.Name, dbConfig.DbType)
 
+	// 加载片段库中保存的片段，重新生成时自动应用
+	savedSnippets := make(map[string][]*config.SavedSnippet)
+	if !req.SkipSavedSnippets {
+		for _, tableName := range req.TableNames {
+			saved, err := config.LoadSnippets(req.DatabaseID, tableName)
+			if err != nil {
+				log.Printf("ERROR: 加载片段库失败: %v", err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "加载片段库失败: " + err.Error()})
+				return
+			}
+			if len(saved) > 0 {
+				savedSnippets[tableName] = saved
+			}
+		}
+	}
+
+	// 加载片段中关联表的列信息，用于校验
+	joinColumns, err := loadJoinColumns(dbConfig, withSavedSnippets(tableSnippets, savedSnippets))
+	if err != nil {
+		log.Printf("ERROR: 加载关联表列信息失败: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "加载关联表列信息失败: " + err.Error()})
+		return
+	}
+
+	// 生成前按表结构校验全部片段，校验失败时不生成任何文件
+	var snippetTables []string
+	for _, tableName := range req.TableNames {
+		if len(tableSnippets[tableName]) > 0 || len(savedSnippets[tableName]) > 0 {
+			snippetTables = append(snippetTables, tableName)
+		}
+	}
+	tableColumns, err := loadTableColumns(dbConfig, snippetTables)
+	if err != nil {
+		log.Printf("ERROR: 加载表列信息失败: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "加载表列信息失败: " + err.Error()})
+		return
+	}
+	resolvedSnippets := make(map[string][]config.SnippetConfig)
+	skippedSnippets := []string{}
+	for _, tableName := range snippetTables {
+		columns := tableColumns[strings.ToLower(tableName)]
+		snippets := tableSnippets[tableName]
+		for i := range snippets {
+			if err := generator.ValidateSnippet(&snippets[i], columns, joinColumns); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("表 %s 的片段%d无效: %v", tableName, i+1, err)})
+				return
+			}
+		}
+		// 片段库中的片段：与请求中同名的以请求为准，表结构变化后失效的跳过
+		for _, saved := range savedSnippets[tableName] {
+			if hasSnippetMethod(snippets, generator.SnippetMethodName(&saved.Config)) {
+				continue
+			}
+			if err := generator.ValidateSnippet(&saved.Config, columns, joinColumns); err != nil {
+				log.Printf("INFO: 表 %s 的已保存片段 %s 已跳过: %v", tableName, saved.Name, err)
+				skippedSnippets = append(skippedSnippets, fmt.Sprintf("表 %s 的已保存片段 %s 已跳过: %v", tableName, saved.Name, err))
+				continue
+			}
+			snippets = append(snippets, saved.Config)
+		}
+		resolvedSnippets[tableName] = snippets
+	}
+
 	// 为每张表生成代码
 	var allFiles []string
+	placements := []SnippetPlacement{}
 	for _, tableName := range req.TableNames {
-		// 复制配置并设置当前表
-		tableConfig := req.Config
-		tableConfig.TableName = tableName
-		tableConfig.DomainObjectName = toPascalCase(tableName)
-		tableConfig.MapperName = toPascalCase(tableName) + "Mapper"
+		// 复制配置并设置当前表（含单表覆盖配置）
+		tableConfig := generator.ConfigForTable(&req.Config, tableName, namer)
 
 		log.Printf("INFO: 生成表 %s 的代码", tableName)
 
@@ -113,16 +217,26 @@ func GenerateCode(c *gin.Context) {
 			return
 		}
 
-		// 若有自定义片段配置，追加写入到生成的文件
-		if len(req.SnippetConfigs) > 0 {
+		// 追加校验通过的自定义片段
+		snippets := resolvedSnippets[tableName]
+		if len(snippets) > 0 {
 			mapperName := tableConfig.MapperName
 			modelType := tableConfig.ModelPackage + "." + tableConfig.DomainObjectName
 
-			if err := appendSnippetsToFiles(files, tableName, mapperName, modelType, req.SnippetConfigs); err != nil {
+			var placed []SnippetPlacement
+			files, placed, err = appendSnippetsToFiles(files, tableName, mapperName, modelType, generator.NewDialect(dbConfig), snippets, req.ConflictStrategy, req.Config.UseLombokPlugin)
+			var conflictErr *generator.SnippetConflictError
+			if errors.As(err, &conflictErr) {
+				log.Printf("ERROR: 表 %s 的片段存在重名: %v", tableName, err)
+				c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("表 %s 追加自定义片段失败: %v", tableName, err), "snippets": placed})
+				return
+			}
+			if err != nil {
 				log.Printf("ERROR: 追加自定义片段失败: %v", err)
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "追加自定义片段失败: " + err.Error()})
+				c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("表 %s 追加自定义片段失败: %v", tableName, err)})
 				return
 			}
+			placements = append(placements, placed...)
 		}
 
 		allFiles = append(allFiles, files...)
@@ -156,84 +270,270 @@ func GenerateCode(c *gin.Context) {
 		"downloadId": downloadID,
 		"files":      getFileNames(allFiles),
 		"tableCount": len(req.TableNames),
+		"snippets":   placements,
+		"skipped":    skippedSnippets,
 	})
 }
 
-// appendSnippetsToFiles 将自定义片段追加写入生成的文件
-func appendSnippetsToFiles(files []string, tableName, mapperName, modelType string, snippets []config.SnippetConfig) error {
-	// 找到 Mapper.java 和 Mapper.xml 文件路径
-	var javaFile, xmlFile string
+// SnippetPlacement 自定义片段的写入位置
+type SnippetPlacement struct {
+	TableName    string   `json:"tableName"`
+	MapperName   string   `json:"mapperName"`
+	MethodName   string   `json:"methodName"`
+	OriginalName string   `json:"originalName,omitempty"` // 因重名自动重命名前的方法名
+	Conflicts    []string `json:"conflicts,omitempty"`    // 重名冲突描述
+	JavaFile     string   `json:"javaFile"`
+	XMLFile      string   `json:"xmlFile"`
+}
+
+// resolveTableSnippets 将请求中的片段配置整理为 表名 -> 片段 的映射
+// 表名匹配忽略大小写；未出现在 tableNames 中的表名视为错误
+func resolveTableSnippets(tableNames []string, legacy []config.SnippetConfig, byTable map[string][]config.SnippetConfig) (map[string][]config.SnippetConfig, error) {
+	result := make(map[string][]config.SnippetConfig)
+	if len(legacy) > 0 {
+		if len(tableNames) > 1 {
+			return nil, fmt.Errorf("多表生成请使用 tableSnippetConfigs 按表名指定自定义片段")
+		}
+		result[tableNames[0]] = append(result[tableNames[0]], legacy...)
+	}
+
+	for key, snippets := range byTable {
+		matched := ""
+		for _, name := range tableNames {
+			if strings.EqualFold(name, key) {
+				matched = name
+				break
+			}
+		}
+		if matched == "" {
+			return nil, fmt.Errorf("片段配置中的表 %s 不在生成列表中", key)
+		}
+		result[matched] = append(result[matched], snippets...)
+	}
+	return result, nil
+}
+
+// withSavedSnippets 合并请求片段和片段库中的片段（仅用于收集关联表）
+func withSavedSnippets(tableSnippets map[string][]config.SnippetConfig, saved map[string][]*config.SavedSnippet) map[string][]config.SnippetConfig {
+	if len(saved) == 0 {
+		return tableSnippets
+	}
+	merged := make(map[string][]config.SnippetConfig, len(tableSnippets)+len(saved))
+	for table, snippets := range tableSnippets {
+		merged[table] = append(merged[table], snippets...)
+	}
+	for table, snippets := range saved {
+		for _, s := range snippets {
+			merged[table] = append(merged[table], s.Config)
+		}
+	}
+	return merged
+}
+
+// isValidConflictStrategy 判断片段重名处理策略是否有效（空值按 error 处理）
+func isValidConflictStrategy(strategy string) bool {
+	switch strategy {
+	case "", config.SnippetConflictError, config.SnippetConflictRename:
+		return true
+	}
+	return false
+}
+
+// hasSnippetMethod 判断片段列表中是否已有生成指定方法名的片段（按推导后的方法名比较，方法名为空时视为不冲突）
+func hasSnippetMethod(snippets []config.SnippetConfig, methodName string) bool {
+	if methodName == "" {
+		return false
+	}
+	for i := range snippets {
+		if generator.SnippetMethodName(&snippets[i]) == methodName {
+			return true
+		}
+	}
+	return false
+}
+
+// loadJoinColumns 加载片段中关联表的列信息（键为小写表名），没有关联表时不连接数据库
+func loadJoinColumns(dbConfig *config.DatabaseConfig, tableSnippets map[string][]config.SnippetConfig) (map[string][]*database.TableColumn, error) {
+	seen := make(map[string]bool)
+	var tables []string
+	for _, snippets := range tableSnippets {
+		for _, snippet := range snippets {
+			for _, join := range snippet.Joins {
+				key := strings.ToLower(join.TableName)
+				if !seen[key] && join.TableName != "" {
+					seen[key] = true
+					tables = append(tables, join.TableName)
+				}
+			}
+		}
+	}
+	return loadTableColumns(dbConfig, tables)
+}
+
+// loadTableColumns 加载多张表的列信息（键为小写表名），表列表为空时不连接数据库
+func loadTableColumns(dbConfig *config.DatabaseConfig, tables []string) (map[string][]*database.TableColumn, error) {
+	result := make(map[string][]*database.TableColumn)
+	if len(tables) == 0 {
+		return result, nil
+	}
+
+	connector := database.NewConnector(dbConfig)
+	if err := connector.Connect(); err != nil {
+		return nil, err
+	}
+	defer connector.Close()
+
+	for _, table := range tables {
+		columns, err := connector.GetTableColumns(table)
+		if err != nil {
+			return nil, fmt.Errorf("获取表 %s 列信息失败: %v", table, err)
+		}
+		result[strings.ToLower(table)] = columns
+	}
+	return result, nil
+}
+
+// validateSnippetOnDatabase 校验片段引用的主表、关联表及列均存在于数据库中，返回主表列信息
+// 执行计划与试运行会将表名、列名直接拼入 SQL，在数据库上执行前必须校验，避免拼入任意语句
+// 校验未通过时 invalid 为 true；获取列信息失败时 invalid 为 false
+func validateSnippetOnDatabase(connector *database.Connector, tableName string, cfg *config.SnippetConfig) (columns []*database.TableColumn, invalid bool, err error) {
+	columns, err = connector.GetTableColumns(tableName)
+	if err != nil {
+		return nil, false, fmt.Errorf("获取表 %s 列信息失败: %v", tableName, err)
+	}
+	if len(columns) == 0 {
+		return nil, true, fmt.Errorf("表 %s 不存在", tableName)
+	}
+
+	joinColumns := make(map[string][]*database.TableColumn)
+	for _, join := range cfg.Joins {
+		cols, err := connector.GetTableColumns(join.TableName)
+		if err != nil {
+			return nil, false, fmt.Errorf("获取表 %s 列信息失败: %v", join.TableName, err)
+		}
+		if len(cols) == 0 {
+			return nil, true, fmt.Errorf("关联表 %s 不存在", join.TableName)
+		}
+		joinColumns[strings.ToLower(join.TableName)] = cols
+	}
+	if err := generator.ValidateSnippet(cfg, columns, joinColumns); err != nil {
+		return nil, true, err
+	}
+	return columns, false, nil
+}
+
+// appendSnippetsToFiles 将自定义片段追加写入生成的文件，关联查询的 DTO 写入实体类目录
+// 返回追加 DTO 后的文件列表及各片段的写入位置
+// 片段与已有方法/语句重名时按 conflictStrategy 处理：rename 自动追加序号，否则返回错误及各片段的检测结果（不写入任何文件）
+func appendSnippetsToFiles(files []string, tableName, mapperName, modelType string, dialect generator.Dialect, snippets []config.SnippetConfig, conflictStrategy string, useLombok bool) ([]string, []SnippetPlacement, error) {
+	// 找到 Mapper 接口和 Mapper.xml 文件路径（按 Mapper 名称匹配，兼容自定义后缀）
+	modelName := modelType[strings.LastIndex(modelType, ".")+1:]
+	var javaFile, xmlFile, modelFile string
 	for _, f := range files {
 		ext := strings.ToLower(filepath.Ext(f))
-		if ext == ".java" && strings.Contains(filepath.Base(f), "Mapper") {
+		if ext == ".java" && filepath.Base(f) == mapperName+".java" {
 			javaFile = f
+		} else if ext == ".java" && filepath.Base(f) == modelName+".java" {
+			modelFile = f
 		} else if ext == ".xml" {
 			xmlFile = f
 		}
 	}
 
-	// 收集所有片段的Java代码和XML代码
-	var allJavaCodes, allXMLCodes []string
-	importsSet := make(map[string]bool)
-	for i, snippet := range snippets {
-		result, err := generator.GenerateSnippet(&snippet, mapperName, modelType, tableName)
-		if err != nil {
-			return fmt.Errorf("片段%d生成失败: %v", i+1, err)
-		}
-		allJavaCodes = append(allJavaCodes, result.JavaCode)
-		allXMLCodes = append(allXMLCodes, result.XMLCode)
-		for _, imp := range result.Imports {
-			importsSet[imp] = true
-		}
+	if javaFile == "" || xmlFile == "" {
+		return nil, nil, fmt.Errorf("未找到 %s 的接口或XML文件", mapperName)
 	}
 
-	// 收集所有需要的 import
-	var allImports []string
-	for imp := range importsSet {
-		allImports = append(allImports, imp)
+	javaContent, err := os.ReadFile(javaFile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("读取Mapper.java失败: %v", err)
+	}
+	xmlContent, err := os.ReadFile(xmlFile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("读取Mapper.xml失败: %v", err)
 	}
-	sort.Strings(allImports)
 
-	// 追加到 Mapper.java
-	if javaFile != "" && len(allJavaCodes) > 0 {
-		content, err := os.ReadFile(javaFile)
-		if err != nil {
-			return fmt.Errorf("读取Mapper.java失败: %v", err)
+	// 生成片段并检测重名
+	merges, err := generator.ResolveSnippetConflicts(snippets, string(javaContent), string(xmlContent), mapperName, modelType, tableName, dialect, conflictStrategy)
+	var placements []SnippetPlacement
+	for _, merge := range merges {
+		placements = append(placements, SnippetPlacement{
+			TableName:    tableName,
+			MapperName:   mapperName,
+			MethodName:   merge.Result.MethodName,
+			OriginalName: merge.OriginalName,
+			Conflicts:    merge.Conflicts,
+			JavaFile:     filepath.Base(javaFile),
+			XMLFile:      filepath.Base(xmlFile),
+		})
+	}
+	if err != nil {
+		return nil, placements, err
+	}
+
+	// 关联/聚合查询的 DTO 及参数对象类写入实体类目录
+	for _, merge := range merges {
+		result := merge.Result
+		if merge.OriginalName != "" {
+			log.Printf("INFO: 片段 %s 与已有方法重名，已重命名为 %s", merge.OriginalName, result.MethodName)
+		}
+		if result.DTO != nil {
+			if modelFile == "" {
+				return nil, nil, fmt.Errorf("未找到实体类文件，无法写入 %s", result.DTO.Name)
+			}
+			dtoCode, err := result.DTO.Render(useLombok)
+			if err != nil {
+				return nil, nil, err
+			}
+			dtoFile := filepath.Join(filepath.Dir(modelFile), result.DTO.Name+".java")
+			if err := os.WriteFile(dtoFile, []byte(dtoCode), 0644); err != nil {
+				return nil, nil, fmt.Errorf("写入%s失败: %v", filepath.Base(dtoFile), err)
+			}
+			files = append(files, dtoFile)
+			log.Printf("INFO: 已生成关联查询DTO %s", filepath.Base(dtoFile))
 		}
-		newContent := string(content)
-		// 先注入缺失的 import
-		newContent = generator.AppendImportsToJava(newContent, allImports)
-		// 再追加方法声明
-		newContent = generator.AppendSnippetToJava(newContent, strings.Join(allJavaCodes, "\n"))
-		if err := os.WriteFile(javaFile, []byte(newContent), 0644); err != nil {
-			return fmt.Errorf("写入Mapper.java失败: %v", err)
+		if result.ParamClass != nil {
+			if modelFile == "" {
+				return nil, nil, fmt.Errorf("未找到实体类文件，无法写入 %s", result.ParamClass.Name)
+			}
+			paramCode, err := result.ParamClass.Render(useLombok)
+			if err != nil {
+				return nil, nil, err
+			}
+			paramFile := filepath.Join(filepath.Dir(modelFile), result.ParamClass.Name+".java")
+			if err := os.WriteFile(paramFile, []byte(paramCode), 0644); err != nil {
+				return nil, nil, fmt.Errorf("写入%s失败: %v", filepath.Base(paramFile), err)
+			}
+			files = append(files, paramFile)
+			log.Printf("INFO: 已生成查询参数类 %s", filepath.Base(paramFile))
 		}
-		log.Printf("INFO: 已追加 %d 个片段到 %s", len(allJavaCodes), filepath.Base(javaFile))
 	}
 
-	// 追加到 Mapper.xml
-	if xmlFile != "" && len(allXMLCodes) > 0 {
-		content, err := os.ReadFile(xmlFile)
-		if err != nil {
-			return fmt.Errorf("读取Mapper.xml失败: %v", err)
+	// 追加到 Mapper.java 和 Mapper.xml
+	if len(merges) > 0 {
+		newJava, newXML := generator.MergeSnippetsIntoMapper(string(javaContent), string(xmlContent), merges)
+		if err := os.WriteFile(javaFile, []byte(newJava), 0644); err != nil {
+			return nil, nil, fmt.Errorf("写入Mapper.java失败: %v", err)
 		}
-		newContent := generator.AppendSnippetToXML(string(content), strings.Join(allXMLCodes, "\n\n"))
-		if err := os.WriteFile(xmlFile, []byte(newContent), 0644); err != nil {
-			return fmt.Errorf("写入Mapper.xml失败: %v", err)
+		log.Printf("INFO: 已追加 %d 个片段到 %s", len(merges), filepath.Base(javaFile))
+		if err := os.WriteFile(xmlFile, []byte(newXML), 0644); err != nil {
+			return nil, nil, fmt.Errorf("写入Mapper.xml失败: %v", err)
 		}
-		log.Printf("INFO: 已追加 %d 个片段到 %s", len(allXMLCodes), filepath.Base(xmlFile))
+		log.Printf("INFO: 已追加 %d 个片段到 %s", len(merges), filepath.Base(xmlFile))
 	}
 
-	return nil
+	return files, placements, nil
 }
 
 // PreviewSnippet 预览自定义片段代码（不生成文件，直接返回代码字符串）
 func PreviewSnippet(c *gin.Context) {
 	var req struct {
-		TableName      string               `json:"tableName"`
-		MapperName     string               `json:"mapperName"`
-		ModelType      string               `json:"modelType"`
+		DatabaseID     int                    `json:"databaseId"` // 可选，用于确定SQL方言（默认MySQL）
+		TableName      string                 `json:"tableName"`
+		MapperName     string                 `json:"mapperName"`
+		ModelType      string                 `json:"modelType"`
 		SnippetConfigs []config.SnippetConfig `json:"snippetConfigs"`
+		UseLombok      bool                   `json:"useLombok"` // 结果DTO及参数对象类是否使用 Lombok（与实体类一致）
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -247,6 +547,21 @@ func PreviewSnippet(c *gin.Context) {
 		return
 	}
 
+	// 按连接的数据库类型确定方言
+	dialect := generator.NewDialect(nil)
+	if req.DatabaseID != 0 {
+		dbConfig, err := findDatabaseConfig(req.DatabaseID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if dbConfig == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "数据库配置不存在"})
+			return
+		}
+		dialect = generator.NewDialect(dbConfig)
+	}
+
 	var javaBuilder, xmlBuilder strings.Builder
 	xmlBuilder.WriteString("<!-- 以下片段追加至 Mapper.xml 的 </mapper> 前 -->\n\n")
 
@@ -254,7 +569,7 @@ func PreviewSnippet(c *gin.Context) {
 	importsSet := make(map[string]bool)
 	var results []*generator.SnippetResult
 	for i, snippet := range req.SnippetConfigs {
-		result, err := generator.GenerateSnippet(&snippet, req.MapperName, req.ModelType, req.TableName)
+		result, err := generator.GenerateSnippet(&snippet, req.MapperName, req.ModelType, req.TableName, dialect)
 		if err != nil {
 			log.Printf("ERROR: 片段%d预览失败: %v", i+1, err)
 			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("片段%d生成失败: %v", i+1, err)})
@@ -282,11 +597,32 @@ func PreviewSnippet(c *gin.Context) {
 	}
 	javaBuilder.WriteString("// 以下方法声明追加至 Mapper 接口最后一个 } 前\n\n")
 
+	var dtoBuilder strings.Builder
 	for _, result := range results {
 		javaBuilder.WriteString(result.JavaCode)
 		javaBuilder.WriteString("\n")
 		xmlBuilder.WriteString(result.XMLCode)
 		xmlBuilder.WriteString("\n\n")
+		if result.DTO != nil {
+			dtoCode, err := result.DTO.Render(req.UseLombok)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+			dtoBuilder.WriteString("// " + result.DTO.Name + ".java（与实体类同目录）\n")
+			dtoBuilder.WriteString(dtoCode)
+			dtoBuilder.WriteString("\n")
+		}
+		if result.ParamClass != nil {
+			paramCode, err := result.ParamClass.Render(req.UseLombok)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+			dtoBuilder.WriteString("// " + result.ParamClass.Name + ".java（与实体类同目录）\n")
+			dtoBuilder.WriteString(paramCode)
+			dtoBuilder.WriteString("\n")
+		}
 	}
 
 	log.Printf("INFO: 片段预览成功 - Table: %s, Snippets: %d", req.TableName, len(req.SnippetConfigs))
@@ -295,18 +631,594 @@ func PreviewSnippet(c *gin.Context) {
 		"success":  true,
 		"javaCode": javaBuilder.String(),
 		"xmlCode":  xmlBuilder.String(),
+		"dtoCode":  dtoBuilder.String(),
+	})
+}
+
+// ParseSnippetMethod 按 Spring Data 命名规则解析方法名生成片段配置，并返回生成的代码
+func ParseSnippetMethod(c *gin.Context) {
+	var req struct {
+		DatabaseID int    `json:"databaseId"`
+		TableName  string `json:"tableName"`
+		MapperName string `json:"mapperName"`
+		ModelType  string `json:"modelType"`
+		MethodName string `json:"methodName"`
+		UseLombok  bool   `json:"useLombok"` // 结果DTO是否使用 Lombok（与实体类一致）
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Printf("ERROR: 解析方法名请求失败: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	dbConfig, err := findDatabaseConfig(req.DatabaseID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if dbConfig == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "数据库配置不存在"})
+		return
+	}
+
+	connector := database.NewConnector(dbConfig)
+	if err := connector.Connect(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	defer connector.Close()
+
+	columns, err := connector.GetTableColumns(req.TableName)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("获取表 %s 列信息失败: %v", req.TableName, err)})
+		return
+	}
+
+	snippet, err := generator.ParseSnippetMethodName(req.MethodName, columns)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := generator.GenerateSnippet(snippet, req.MapperName, req.ModelType, req.TableName, generator.NewDialect(dbConfig))
+	if err != nil {
+		log.Printf("ERROR: 方法名 %s 生成片段失败: %v", req.MethodName, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	dtoCode := ""
+	if result.DTO != nil {
+		if dtoCode, err = result.DTO.Render(req.UseLombok); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	log.Printf("INFO: 方法名解析成功 - Table: %s, Method: %s", req.TableName, req.MethodName)
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":       true,
+		"snippetConfig": snippet,
+		"javaCode":      result.JavaCode,
+		"xmlCode":       result.XMLCode,
+		"dtoCode":       dtoCode,
+	})
+}
+
+// ImportSnippetSQL 将手写 SQL 转换为 Mapper 方法和 XML 语句（参数类型按引用的列推断）
+func ImportSnippetSQL(c *gin.Context) {
+	var req struct {
+		DatabaseID int    `json:"databaseId"`
+		ModelType  string `json:"modelType"`
+		MethodName string `json:"methodName"`
+		SQL        string `json:"sql"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Printf("ERROR: 解析SQL导入请求失败: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	dbConfig, err := findDatabaseConfig(req.DatabaseID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if dbConfig == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "数据库配置不存在"})
+		return
+	}
+
+	connector := database.NewConnector(dbConfig)
+	if err := connector.Connect(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	defer connector.Close()
+
+	imported, err := generator.ImportSnippetSQL(req.SQL, req.MethodName, req.ModelType, connector.GetTableColumns)
+	if err != nil {
+		log.Printf("ERROR: SQL导入失败 - Method: %s: %v", req.MethodName, err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	log.Printf("INFO: SQL导入成功 - Method: %s, Params: %d", req.MethodName, len(imported.Params))
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":    true,
+		"methodName": imported.Result.MethodName,
+		"operation":  imported.Operation,
+		"params":     imported.Params,
+		"warnings":   imported.Warnings,
+		"imports":    imported.Result.Imports,
+		"javaCode":   imported.Result.JavaCode,
+		"xmlCode":    imported.Result.XMLCode,
+	})
+}
+
+// ImportMapperXML 解析上传的 Mapper XML，将可用片段配置表达的语句转换为片段配置，其余语句原样返回
+func ImportMapperXML(c *gin.Context) {
+	var req struct {
+		DatabaseID int    `json:"databaseId"`
+		TableName  string `json:"tableName"`
+		ModelType  string `json:"modelType"` // 可选，默认取 XML 中第一个 resultMap 的 type
+		XML        string `json:"xml"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Printf("ERROR: 解析Mapper XML导入请求失败: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if strings.TrimSpace(req.XML) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "XML 不能为空"})
+		return
+	}
+	if req.ModelType == "" {
+		req.ModelType = generator.MapperModelType(req.XML)
+		if req.ModelType == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "无法从 XML 推断实体类型，请指定 modelType"})
+			return
+		}
+	}
+
+	dbConfig, err := findDatabaseConfig(req.DatabaseID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if dbConfig == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "数据库配置不存在"})
+		return
+	}
+
+	connector := database.NewConnector(dbConfig)
+	if err := connector.Connect(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	defer connector.Close()
+
+	columns, err := connector.GetTableColumns(req.TableName)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("获取表 %s 列信息失败: %v", req.TableName, err)})
+		return
+	}
+
+	imported, err := generator.ImportMapperXML(req.XML, req.TableName, req.ModelType, columns)
+	if err != nil {
+		log.Printf("ERROR: Mapper XML导入失败 - Table: %s: %v", req.TableName, err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	log.Printf("INFO: Mapper XML导入成功 - Table: %s, Snippets: %d, Raw: %d", req.TableName, len(imported.Snippets), len(imported.Raw))
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":  true,
+		"snippets": imported.Snippets,
+		"raw":      imported.Raw,
 	})
 }
 
-// toPascalCase 将下划线命名转为帕斯卡命名
-func toPascalCase(s string) string {
-	parts := strings.Split(s, "_")
-	for i, part := range parts {
-		if len(part) > 0 {
-			parts[i] = strings.ToUpper(part[:1]) + strings.ToLower(part[1:])
+// MergedFile 合并片段后返回的文件
+type MergedFile struct {
+	Name    string `json:"name"`
+	Content string `json:"content"`
+}
+
+// MergeMapperSnippets 将自定义片段合并到上传的已有 Mapper 接口和 XML 中，返回修改后的文件（不写入磁盘）
+// 重名检测和 import 去重与生成代码时的片段合并一致
+func MergeMapperSnippets(c *gin.Context) {
+	var req struct {
+		DatabaseID       int                    `json:"databaseId"` // 可选，用于确定SQL方言并校验片段的列
+		TableName        string                 `json:"tableName"`
+		MapperName       string                 `json:"mapperName"` // 可选，默认取 Java 文件中的接口名
+		ModelType        string                 `json:"modelType"`  // 可选，默认取 XML 中第一个 resultMap 的 type
+		JavaFileName     string                 `json:"javaFileName"`
+		JavaContent      string                 `json:"javaContent"`
+		XMLFileName      string                 `json:"xmlFileName"`
+		XMLContent       string                 `json:"xmlContent"`
+		SnippetConfigs   []config.SnippetConfig `json:"snippetConfigs"`
+		ConflictStrategy string                 `json:"conflictStrategy"` // 可选，error（默认）/ rename
+		UseLombok        bool                   `json:"useLombok"`        // 结果DTO及参数对象类是否使用 Lombok（与实体类一致）
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Printf("ERROR: 解析Mapper合并请求失败: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if strings.TrimSpace(req.JavaContent) == "" || strings.TrimSpace(req.XMLContent) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "请上传 Mapper 接口和 Mapper.xml 文件"})
+		return
+	}
+	if req.TableName == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "表名不能为空"})
+		return
+	}
+	if len(req.SnippetConfigs) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "片段配置不能为空"})
+		return
+	}
+	if !isValidConflictStrategy(req.ConflictStrategy) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "未知的重名处理策略: " + req.ConflictStrategy})
+		return
+	}
+	if req.MapperName == "" {
+		req.MapperName = generator.JavaTypeName(req.JavaContent)
+		if req.MapperName == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "未在 Java 文件中找到 Mapper 接口定义"})
+			return
+		}
+	}
+	if req.ModelType == "" {
+		req.ModelType = generator.MapperModelType(req.XMLContent)
+		if req.ModelType == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "无法从 XML 推断实体类型，请指定 modelType"})
+			return
+		}
+	}
+	if req.JavaFileName == "" {
+		req.JavaFileName = req.MapperName + ".java"
+	}
+	if req.XMLFileName == "" {
+		req.XMLFileName = req.MapperName + ".xml"
+	}
+
+	// 指定连接时按数据库类型确定方言，并按表结构校验片段
+	dialect := generator.NewDialect(nil)
+	if req.DatabaseID != 0 {
+		dbConfig, err := findDatabaseConfig(req.DatabaseID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if dbConfig == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "数据库配置不存在"})
+			return
+		}
+		dialect = generator.NewDialect(dbConfig)
+
+		connector := database.NewConnector(dbConfig)
+		if err := connector.Connect(); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		columns, err := connector.GetTableColumns(req.TableName)
+		connector.Close()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("获取表 %s 列信息失败: %v", req.TableName, err)})
+			return
+		}
+		joinColumns, err := loadJoinColumns(dbConfig, map[string][]config.SnippetConfig{req.TableName: req.SnippetConfigs})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "加载关联表列信息失败: " + err.Error()})
+			return
+		}
+		for i := range req.SnippetConfigs {
+			if err := generator.ValidateSnippet(&req.SnippetConfigs[i], columns, joinColumns); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("片段%d无效: %v", i+1, err)})
+				return
+			}
+		}
+	}
+
+	merges, err := generator.ResolveSnippetConflicts(req.SnippetConfigs, req.JavaContent, req.XMLContent, req.MapperName, req.ModelType, req.TableName, dialect, req.ConflictStrategy)
+	placements := []SnippetPlacement{}
+	for _, merge := range merges {
+		placements = append(placements, SnippetPlacement{
+			TableName:    req.TableName,
+			MapperName:   req.MapperName,
+			MethodName:   merge.Result.MethodName,
+			OriginalName: merge.OriginalName,
+			Conflicts:    merge.Conflicts,
+			JavaFile:     req.JavaFileName,
+			XMLFile:      req.XMLFileName,
+		})
+	}
+	var conflictErr *generator.SnippetConflictError
+	if errors.As(err, &conflictErr) {
+		log.Printf("ERROR: %s 的片段存在重名: %v", req.MapperName, err)
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "snippets": placements})
+		return
+	}
+	if err != nil {
+		log.Printf("ERROR: 合并片段到 %s 失败: %v", req.MapperName, err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	javaContent, xmlContent := generator.MergeSnippetsIntoMapper(req.JavaContent, req.XMLContent, merges)
+	dtoFiles := []MergedFile{}
+	for _, merge := range merges {
+		if merge.Result.DTO != nil {
+			dtoCode, err := merge.Result.DTO.Render(req.UseLombok)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+			dtoFiles = append(dtoFiles, MergedFile{Name: merge.Result.DTO.Name + ".java", Content: dtoCode})
+		}
+		if merge.Result.ParamClass != nil {
+			paramCode, err := merge.Result.ParamClass.Render(req.UseLombok)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+			dtoFiles = append(dtoFiles, MergedFile{Name: merge.Result.ParamClass.Name + ".java", Content: paramCode})
 		}
 	}
-	return strings.Join(parts, "")
+
+	log.Printf("INFO: 已合并 %d 个片段到上传的 %s", len(merges), req.MapperName)
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":  true,
+		"javaFile": MergedFile{Name: req.JavaFileName, Content: javaContent},
+		"xmlFile":  MergedFile{Name: req.XMLFileName, Content: xmlContent},
+		"dtoFiles": dtoFiles,
+		"snippets": placements,
+	})
+}
+
+// ExplainSnippet 将片段主语句以示例参数渲染为具体 SQL，在目标数据库上获取执行计划
+// 片段引用的表或列不存在、或数据库拒绝语句（语法错误等）时返回 valid=false 及错误信息；fullScans 列出全表扫描的表
+func ExplainSnippet(c *gin.Context) {
+	var req struct {
+		DatabaseID    int                    `json:"databaseId"`
+		TableName     string                 `json:"tableName"`
+		MapperName    string                 `json:"mapperName"`
+		ModelType     string                 `json:"modelType"`
+		SnippetConfig config.SnippetConfig   `json:"snippetConfig"`
+		Params        map[string]interface{} `json:"params"` // 可选，空则按字段类型生成示例参数
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Printf("ERROR: 解析执行计划请求失败: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	dbConfig, err := findDatabaseConfig(req.DatabaseID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if dbConfig == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "数据库配置不存在"})
+		return
+	}
+
+	dialect := generator.NewDialect(dbConfig)
+	result, err := generator.GenerateSnippet(&req.SnippetConfig, req.MapperName, req.ModelType, req.TableName, dialect)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	sqlText, bound, err := generator.RenderSnippetSQL(&req.SnippetConfig, result, dialect, req.Params)
+	if err != nil {
+		log.Printf("ERROR: 片段 %s 渲染SQL失败: %v", result.MethodName, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	connector := database.NewConnector(dbConfig)
+	if err := connector.Connect(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	defer connector.Close()
+
+	// 表或列不存在时不在数据库上执行，直接按校验未通过返回
+	if _, invalid, err := validateSnippetOnDatabase(connector, req.TableName, &req.SnippetConfig); err != nil {
+		if !invalid {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		log.Printf("INFO: 片段执行计划校验未通过 - Method: %s: %v", result.MethodName, err)
+		c.JSON(http.StatusOK, gin.H{
+			"success":    true,
+			"valid":      false,
+			"methodName": result.MethodName,
+			"sql":        sqlText,
+			"params":     bound.Params,
+			"sqlError":   err.Error(),
+		})
+		return
+	}
+
+	query, args := generator.BindBoundSQL(bound, dialect)
+	plan, err := connector.ExplainSQL(query, args)
+	if err != nil {
+		log.Printf("INFO: 片段执行计划校验未通过 - Method: %s: %v", result.MethodName, err)
+		c.JSON(http.StatusOK, gin.H{
+			"success":    true,
+			"valid":      false,
+			"methodName": result.MethodName,
+			"sql":        sqlText,
+			"params":     bound.Params,
+			"sqlError":   err.Error(),
+		})
+		return
+	}
+
+	log.Printf("INFO: 片段执行计划获取成功 - Method: %s, FullScans: %v", result.MethodName, plan.FullScans)
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":    true,
+		"valid":      true,
+		"methodName": result.MethodName,
+		"sql":        sqlText,
+		"params":     bound.Params,
+		"columns":    plan.Columns,
+		"rows":       plan.Rows,
+		"fullScans":  plan.FullScans,
+	})
+}
+
+// ExecuteSnippet 在沙箱中试运行片段主语句：查询返回前 N 行并与结果映射对照，DML 返回影响行数
+// 语句在事务中执行且总是回滚；连接需开启「允许试运行」，执行前校验片段引用的表和列
+func ExecuteSnippet(c *gin.Context) {
+	var req struct {
+		DatabaseID    int                    `json:"databaseId"`
+		TableName     string                 `json:"tableName"`
+		MapperName    string                 `json:"mapperName"`
+		ModelType     string                 `json:"modelType"`
+		SnippetConfig config.SnippetConfig   `json:"snippetConfig"`
+		Params        map[string]interface{} `json:"params"`  // 可选，空则按字段类型生成示例参数
+		MaxRows       int                    `json:"maxRows"` // 查询最多返回行数（默认20，最大200）
+		Config        config.GeneratorConfig `json:"config"`  // 可选，命名规则及列覆盖，用于推导实体属性
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Printf("ERROR: 解析试运行请求失败: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	dbConfig, err := findDatabaseConfig(req.DatabaseID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if dbConfig == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "数据库配置不存在"})
+		return
+	}
+	if !dbConfig.AllowExecution {
+		c.JSON(http.StatusForbidden, gin.H{"error": "该连接未开启「允许试运行」，请在连接配置中开启"})
+		return
+	}
+
+	maxRows := req.MaxRows
+	if maxRows <= 0 {
+		maxRows = 20
+	} else if maxRows > 200 {
+		maxRows = 200
+	}
+
+	dialect := generator.NewDialect(dbConfig)
+	result, err := generator.GenerateSnippet(&req.SnippetConfig, req.MapperName, req.ModelType, req.TableName, dialect)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	sqlText, bound, err := generator.RenderSnippetSQL(&req.SnippetConfig, result, dialect, req.Params)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	connector := database.NewConnector(dbConfig)
+	if err := connector.Connect(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	defer connector.Close()
+
+	tableColumns, invalid, err := validateSnippetOnDatabase(connector, req.TableName, &req.SnippetConfig)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if invalid {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	isQuery := req.SnippetConfig.Operation == config.OperationSelect
+	query, args := generator.BindBoundSQL(bound, dialect)
+	executed, err := connector.ExecuteInSandbox(query, args, isQuery, maxRows)
+	if err != nil {
+		log.Printf("INFO: 片段试运行失败 - Method: %s: %v", result.MethodName, err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("执行失败: %v", err), "sql": sqlText})
+		return
+	}
+
+	response := gin.H{
+		"success":      true,
+		"methodName":   result.MethodName,
+		"sql":          sqlText,
+		"params":       bound.Params,
+		"columns":      executed.Columns,
+		"rows":         executed.Rows,
+		"truncated":    executed.Truncated,
+		"rowsAffected": executed.RowsAffected,
+		"rolledBack":   true,
+	}
+	if isQuery {
+		mapping, err := generator.MatchResultColumns(result.XMLCode, result.MethodName, req.ModelType, tableColumns, executed.Columns, generator.PropertyNamer(&req.Config))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		response["mapping"] = mapping
+	}
+
+	log.Printf("INFO: 片段试运行成功（已回滚）- Method: %s, Rows: %d, Affected: %d",
+		result.MethodName, len(executed.Rows), executed.RowsAffected)
+	c.JSON(http.StatusOK, response)
+}
+
+// EvaluateSnippetSQL 按示例参数对 Mapper XML（生成的 Mapper 或片段）中的语句求值，返回最终 SQL 与绑定参数
+func EvaluateSnippetSQL(c *gin.Context) {
+	var req struct {
+		XML         string                 `json:"xml"`
+		StatementID string                 `json:"statementId"` // 可选，空则取第一条语句
+		Params      map[string]interface{} `json:"params"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Printf("ERROR: 解析SQL求值请求失败: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if strings.TrimSpace(req.XML) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "XML不能为空"})
+		return
+	}
+
+	bound, err := generator.EvaluateStatement(req.XML, req.StatementID, req.Params)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	log.Printf("INFO: SQL求值成功 - Statement: %s, Params: %d", bound.StatementID, len(bound.Params))
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":     true,
+		"statementId": bound.StatementID,
+		"sql":         bound.SQL,
+		"params":      bound.Params,
+	})
 }
 
 // DownloadCode 下载生成的代码ZIP
@@ -368,3 +1280,66 @@ func generateRandomString(length int) string {
 	}
 	return string(result)
 }
+
+// PreviewNaming 按命名规则预览所选表的类名、Mapper名及属性名
+func PreviewNaming(c *gin.Context) {
+	var req struct {
+		DatabaseID int                    `json:"databaseId"` // 可选，提供时同时预览列属性名
+		TableNames []string               `json:"tableNames"`
+		Config     config.GeneratorConfig `json:"config"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	namer, err := generator.NewNamer(req.Config.Naming)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var connector *database.Connector
+	if req.DatabaseID != 0 {
+		dbConfig, err := findDatabaseConfig(req.DatabaseID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if dbConfig == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "数据库配置不存在"})
+			return
+		}
+		connector = database.NewConnector(dbConfig)
+		if err := connector.Connect(); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		defer connector.Close()
+	}
+
+	previews := make([]NamingPreview, 0, len(req.TableNames))
+	for _, tableName := range req.TableNames {
+		tableConfig := generator.ConfigForTable(&req.Config, tableName, namer)
+		preview := NamingPreview{
+			TableName:        tableName,
+			DomainObjectName: tableConfig.DomainObjectName,
+			MapperName:       tableConfig.MapperName,
+		}
+		if connector != nil {
+			columns, err := connector.GetTableColumns(tableName)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("获取表 %s 列信息失败: %v", tableName, err)})
+				return
+			}
+			preview.Fields = make(map[string]string, len(columns))
+			for _, col := range columns {
+				preview.Fields[col.ColumnName] = namer.FieldName(col.ColumnName, req.Config.UseActualColumnNames)
+			}
+		}
+		previews = append(previews, preview)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"results": previews})
+}
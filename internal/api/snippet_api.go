@@ -0,0 +1,100 @@
+package api
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/mybatis-generator-gui-go/internal/config"
+)
+
+// GetSavedSnippets 获取片段库（按连接，可选按表名过滤）
+func GetSavedSnippets(c *gin.Context) {
+	dbID, err := strconv.Atoi(c.Query("databaseId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的数据库ID"})
+		return
+	}
+
+	snippets, err := config.LoadSnippets(dbID, c.Query("tableName"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, snippets)
+}
+
+// CreateSavedSnippet 保存片段到片段库
+func CreateSavedSnippet(c *gin.Context) {
+	var snippet config.SavedSnippet
+	if err := c.ShouldBindJSON(&snippet); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	snippet.ID = 0
+	saveSnippet(c, &snippet)
+}
+
+// UpdateSavedSnippet 更新片段库中的片段
+func UpdateSavedSnippet(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的片段ID"})
+		return
+	}
+
+	var snippet config.SavedSnippet
+	if err := c.ShouldBindJSON(&snippet); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	existing, err := config.LoadSnippetByID(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if existing == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "片段不存在"})
+		return
+	}
+	snippet.ID = id
+	saveSnippet(c, &snippet)
+}
+
+// saveSnippet 校验并保存片段
+func saveSnippet(c *gin.Context, snippet *config.SavedSnippet) {
+	if snippet.DatabaseID == 0 || snippet.TableName == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "片段必须指定数据库连接和表名"})
+		return
+	}
+	switch snippet.Config.Operation {
+	case config.OperationSelect, config.OperationInsert, config.OperationDelete, config.OperationUpdate:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "未知操作类型: " + string(snippet.Config.Operation)})
+		return
+	}
+
+	if err := config.SaveSnippet(snippet); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	log.Printf("INFO: 片段已保存 - DatabaseID: %d, Table: %s, Name: %s", snippet.DatabaseID, snippet.TableName, snippet.Name)
+	c.JSON(http.StatusOK, snippet)
+}
+
+// DeleteSavedSnippet 从片段库删除片段
+func DeleteSavedSnippet(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的片段ID"})
+		return
+	}
+
+	if err := config.DeleteSnippet(id); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "删除成功"})
+}
@@ -11,6 +11,9 @@ type DatabaseConfig struct {
 	Username string `json:"username"` // 用户名
 	Password string `json:"password"` // 密码
 	Encoding string `json:"encoding"` // 编码格式,默认UTF-8
+
+	OracleLegacyPaging bool `json:"oracleLegacyPaging"` // Oracle 12c 之前的版本使用 ROWNUM 分页
+	AllowExecution     bool `json:"allowExecution"`     // 允许在该连接上试运行片段（DML 总是回滚）
 }
 
 // DbType 数据库类型常量
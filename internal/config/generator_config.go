@@ -1,5 +1,7 @@
 package config
 
+import "strings"
+
 // GeneratorConfig 代码生成配置
 type GeneratorConfig struct {
 	Name                     string `json:"name"`                     // 配置名称
@@ -37,12 +39,88 @@ type GeneratorConfig struct {
 	UseBatchInsert             bool `json:"useBatchInsert"`             // 是否生成批量插入
 	UseBatchUpdate             bool `json:"useBatchUpdate"`             // 是否生成批量更新
 	IgnorePKOnInsert           bool `json:"ignorePKOnInsert"`           // 插入时是否忽略主键
+	UseUpsert                  bool `json:"useUpsert"`                  // 是否生成插入或更新（upsert）
+	UseBatchUpsert             bool `json:"useBatchUpsert"`             // 是否生成批量插入或更新
+	UseAssociations            bool `json:"useAssociations"`            // 是否根据外键生成关联属性与联表查询
+	UseIndexFinders            bool `json:"useIndexFinders"`            // 是否为普通索引生成列表查询（唯一索引总是生成）
+	UseBeanValidation          bool `json:"useBeanValidation"`          // 是否根据列元数据生成Bean Validation注解
+	UseJakartaValidation       bool `json:"useJakartaValidation"`       // 校验注解使用jakarta包（否则使用javax）
+
+	// upsert 冲突判定使用的唯一索引名，空则使用主键
+	// MySQL 的 ON DUPLICATE KEY UPDATE 不能指定索引，任一唯一键冲突都会更新，此项仅决定不更新的列
+	UpsertConflictIndex string `json:"upsertConflictIndex"`
+
+	// 主键生成策略: 空(按 GenerateKeys 使用 useGeneratedKeys), identity, sequence, returning
+	KeyStrategy  string `json:"keyStrategy"`
+	SequenceName string `json:"sequenceName"` // 序列名（sequence 策略），支持 {table} 占位符，空则自动查找
+
+	// 接口文档注解风格: 空(不生成), swagger2, openapi3
+	APIDocStyle string `json:"apiDocStyle"`
+
+	// 命名规则
+	Naming NamingRules `json:"naming"`
 
 	// 列定制
 	IgnoredColumns  []string         `json:"ignoredColumns"`  // 忽略的列名列表
 	ColumnOverrides []ColumnOverride `json:"columnOverrides"` // 列覆盖配置
+
+	// 单表覆盖配置（表名 -> 覆盖项），多表生成时按表生效
+	TableOverrides map[string]TableOverride `json:"tableOverrides,omitempty"`
+}
+
+// TableOverride 单表覆盖配置，空值表示沿用全局配置
+type TableOverride struct {
+	DomainObjectName string           `json:"domainObjectName"` // 实体类名
+	MapperName       string           `json:"mapperName"`       // Mapper名称
+	GenerateKeys     string           `json:"generateKeys"`     // 主键字段名
+	IgnoredColumns   []string         `json:"ignoredColumns"`   // 忽略的列（非 null 时替换全局列表）
+	ColumnOverrides  []ColumnOverride `json:"columnOverrides"`  // 列覆盖（非 null 时替换全局列表）
+	KeyStrategy      string           `json:"keyStrategy"`      // 主键生成策略（非空时替换全局配置）
+	SequenceName     string           `json:"sequenceName"`     // 序列名（非空时替换全局配置）
 }
 
+// FindTableOverride 查找表的覆盖配置（表名忽略大小写）
+func (c *GeneratorConfig) FindTableOverride(tableName string) (TableOverride, bool) {
+	if override, ok := c.TableOverrides[tableName]; ok {
+		return override, true
+	}
+	for name, override := range c.TableOverrides {
+		if strings.EqualFold(name, tableName) {
+			return override, true
+		}
+	}
+	return TableOverride{}, false
+}
+
+// APIDocStyle 接口文档注解风格常量
+const (
+	APIDocSwagger2 = "swagger2" // Swagger 2: @ApiModel / @ApiModelProperty
+	APIDocOpenAPI3 = "openapi3" // OpenAPI 3 (springdoc): @Schema
+)
+
+// NamingRules 表名/列名到类名/属性名的命名规则
+type NamingRules struct {
+	TablePrefixes  []string     `json:"tablePrefixes"`  // 需去除的表名前缀，如 t_、tb_、sys_
+	ColumnPrefixes []string     `json:"columnPrefixes"` // 需去除的列名前缀，如 f_
+	TableRenames   []RenameRule `json:"tableRenames"`   // 表名正则重命名（去除前缀后、转驼峰前执行）
+	ColumnRenames  []RenameRule `json:"columnRenames"`  // 列名正则重命名（去除前缀后、转驼峰前执行）
+	ModelSuffix    string       `json:"modelSuffix"`    // 实体类后缀，如 Entity、DO
+	MapperSuffix   string       `json:"mapperSuffix"`   // Mapper后缀，空则为 Mapper，可设为 Dao、Repository
+}
+
+// RenameRule 正则重命名规则
+type RenameRule struct {
+	Pattern     string `json:"pattern"`     // 正则表达式
+	Replacement string `json:"replacement"` // 替换内容，支持 $1 等分组引用
+}
+
+// KeyStrategy 主键生成策略常量
+const (
+	KeyStrategyIdentity  = "identity"  // 自增/identity 列：useGeneratedKeys
+	KeyStrategySequence  = "sequence"  // 序列：<selectKey order="BEFORE">
+	KeyStrategyReturning = "returning" // PostgreSQL：INSERT ... RETURNING
+)
+
 // ColumnOverride 列覆盖配置
 type ColumnOverride struct {
 	ColumnName   string `json:"columnName"`   // 数据库列名
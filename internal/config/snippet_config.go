@@ -10,6 +10,25 @@ const (
 	OperationUpdate SnippetOperation = "update"
 )
 
+// SnippetReturnShape 查询片段的返回形式
+type SnippetReturnShape string
+
+const (
+	ReturnShapeList     SnippetReturnShape = "list"     // List<Model>（默认）
+	ReturnShapeOne      SnippetReturnShape = "one"      // 单个 Model（唯一查询）
+	ReturnShapeOptional SnippetReturnShape = "optional" // Optional<Model>
+	ReturnShapeCount    SnippetReturnShape = "count"    // long 计数
+	ReturnShapeExists   SnippetReturnShape = "exists"   // boolean 是否存在（SELECT 1 ... LIMIT 1）
+	ReturnShapeMap      SnippetReturnShape = "map"      // Map<String, Object>（单行，列名为键）
+	ReturnShapeMapKey   SnippetReturnShape = "mapKey"   // @MapKey 以指定列为键的 Map<K, Model>
+)
+
+// 片段与Mapper中已有方法/语句重名时的处理策略
+const (
+	SnippetConflictError  = "error"  // 报错，不写入（默认）
+	SnippetConflictRename = "rename" // 自动在方法名后追加序号
+)
+
 // SnippetField 片段字段配置
 type SnippetField struct {
 	ColumnName string `json:"columnName"` // 数据库列名
@@ -26,6 +45,10 @@ type SnippetField struct {
 	// SELECT 字段的聚合和别名（需求 #3.2）
 	Aggregate  string `json:"aggregate"`  // 聚合函数 (COUNT, SUM, MAX, MIN, AVG)
 	Alias      string `json:"alias"`      // AS 别名
+	// 关联查询时列所属表的别名，空则为主表
+	TableAlias string `json:"tableAlias"`
+	// 可选条件，仅用于 WhereFields：参数为空时忽略该条件（<where> + <if>）
+	Optional bool `json:"optional"`
 }
 
 // OrderByField 排序字段配置
@@ -34,6 +57,22 @@ type OrderByField struct {
 	FieldName  string `json:"fieldName"`  // Java字段名
 	JdbcType   string `json:"jdbcType"`   // JDBC类型
 	Direction  string `json:"direction"`  // ASC / DESC
+	TableAlias string `json:"tableAlias"` // 关联查询时列所属表的别名，空则为主表
+}
+
+// SnippetJoinOn 关联条件（左侧表的列 = 关联表的列）
+type SnippetJoinOn struct {
+	LeftAlias   string `json:"leftAlias"`   // 左侧表别名（空则为主表）
+	LeftColumn  string `json:"leftColumn"`  // 左侧列名
+	RightColumn string `json:"rightColumn"` // 关联表列名
+}
+
+// SnippetJoin 关联表配置
+type SnippetJoin struct {
+	JoinType  string          `json:"joinType"`  // INNER / LEFT / RIGHT（默认 LEFT）
+	TableName string          `json:"tableName"` // 关联表名
+	Alias     string          `json:"alias"`     // 关联表别名（用于限定列）
+	On        []SnippetJoinOn `json:"on"`        // 关联条件（至少一个）
 }
 
 // SnippetConfig 单个自定义片段配置
@@ -50,5 +89,28 @@ type SnippetConfig struct {
 	HasLimit      bool             `json:"hasLimit"`      // 查询：是否包含 LIMIT
 	IsLimitFixed  bool             `json:"isLimitFixed"`  // LIMIT：true=固定值，false=变量参数
 	LimitValue    string           `json:"limitValue"`    // LIMIT：固定值内容，或变量名称（如果是变量，则Java参数名会使用此名称，如果不填默认为limit）
+	// 关联查询（仅 select）
+	TableAlias string        `json:"tableAlias"` // 主表别名（有关联表时使用，默认 t）
+	Joins      []SnippetJoin `json:"joins"`      // 关联表
+	ResultType string        `json:"resultType"` // 结果DTO类名（关联/聚合查询时使用，空则按方法名生成）
+	// 分组查询（仅 select）
+	GroupByFields []SnippetField `json:"groupByFields"` // GROUP BY 列（顺序有效）
+	HavingFields  []SnippetField `json:"havingFields"`  // HAVING 条件（Aggregate 为聚合函数，含运算符，条件之间为 AND）
+	// 返回形式（仅 select，空则为 List）
+	ReturnShape SnippetReturnShape `json:"returnShape"`
+	MapKey      *SnippetField      `json:"mapKey,omitempty"` // @MapKey 的键列（ReturnShape=mapKey 时使用）
+	// 参数对象模式（仅非批量的 select / delete，其他片段启用时报错）：生成 XxxQuery 参数类，方法只接收该对象
+	ParamObject    bool   `json:"paramObject"`
+	ParamClassName string `json:"paramClassName"` // 参数类名（空则为 方法名首字母大写 + Query）
 }
 
+// SavedSnippet 片段库中保存的片段（按连接和表名归档，重新生成该表时自动应用）
+type SavedSnippet struct {
+	ID          int           `json:"id"`          // 主键ID
+	DatabaseID  int           `json:"databaseId"`  // 所属数据库连接
+	TableName   string        `json:"tableName"`   // 所属表名（忽略大小写）
+	Name        string        `json:"name"`        // 片段名称（同一张表内唯一，默认为方法名）
+	Description string        `json:"description"` // 描述
+	Config      SnippetConfig `json:"config"`      // 片段配置
+	UpdatedAt   string        `json:"updatedAt"`   // 最后修改时间
+}
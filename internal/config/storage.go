@@ -7,6 +7,7 @@ import (
 	_ "modernc.org/sqlite" // SQLite驱动
 	"os"
 	"path/filepath"
+	"time"
 
 	"golang.org/x/crypto/bcrypt"
 )
@@ -67,6 +68,19 @@ func createTables() error {
 		password_hash TEXT NOT NULL
 	);`
 
+	// 创建自定义片段库表（按连接和表名归档）
+	snippetsTable := `
+	CREATE TABLE IF NOT EXISTS snippets (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		db_id INTEGER NOT NULL,
+		table_name TEXT NOT NULL COLLATE NOCASE,
+		name TEXT NOT NULL,
+		description TEXT NOT NULL DEFAULT '',
+		value TEXT NOT NULL,
+		updated_at TEXT NOT NULL,
+		UNIQUE (db_id, table_name, name)
+	);`
+
 	_, err := db.Exec(dbsTable)
 	if err != nil {
 		return fmt.Errorf("创建dbs表失败: %v", err)
@@ -82,6 +96,11 @@ func createTables() error {
 		return fmt.Errorf("创建users表失败: %v", err)
 	}
 
+	_, err = db.Exec(snippetsTable)
+	if err != nil {
+		return fmt.Errorf("创建snippets表失败: %v", err)
+	}
+
 	return nil
 }
 
@@ -166,8 +185,11 @@ func LoadDatabaseConfigs() ([]*DatabaseConfig, error) {
 	return configs, nil
 }
 
-// DeleteDatabaseConfig 删除数据库配置
+// DeleteDatabaseConfig 删除数据库配置（同时删除该连接的片段库）
 func DeleteDatabaseConfig(id int) error {
+	if _, err := db.Exec("DELETE FROM snippets WHERE db_id = ?", id); err != nil {
+		return fmt.Errorf("删除片段库失败: %v", err)
+	}
 	_, err := db.Exec("DELETE FROM dbs WHERE id = ?", id)
 	return err
 }
@@ -314,3 +336,114 @@ func UpdateUser(oldUsername, newUsername, password string) error {
 
 	return nil
 }
+
+// SaveSnippet 保存片段到片段库（ID 为 0 时新增，否则更新）
+func SaveSnippet(snippet *SavedSnippet) error {
+	if snippet.Name == "" {
+		snippet.Name = snippet.Config.MethodName
+	}
+	if snippet.Name == "" {
+		return fmt.Errorf("片段名称不能为空")
+	}
+
+	jsonData, err := json.Marshal(snippet.Config)
+	if err != nil {
+		return fmt.Errorf("序列化片段失败: %v", err)
+	}
+
+	// 同一张表内名称唯一
+	var count int
+	err = db.QueryRow("SELECT COUNT(*) FROM snippets WHERE db_id = ? AND table_name = ? AND name = ? AND id != ?",
+		snippet.DatabaseID, snippet.TableName, snippet.Name, snippet.ID).Scan(&count)
+	if err != nil {
+		return fmt.Errorf("检查片段名称失败: %v", err)
+	}
+	if count > 0 {
+		return fmt.Errorf("表 %s 已存在同名片段: %s", snippet.TableName, snippet.Name)
+	}
+
+	snippet.UpdatedAt = time.Now().Format("2006-01-02 15:04:05")
+	if snippet.ID != 0 {
+		res, err := db.Exec("UPDATE snippets SET db_id = ?, table_name = ?, name = ?, description = ?, value = ?, updated_at = ? WHERE id = ?",
+			snippet.DatabaseID, snippet.TableName, snippet.Name, snippet.Description, string(jsonData), snippet.UpdatedAt, snippet.ID)
+		if err != nil {
+			return fmt.Errorf("更新片段失败: %v", err)
+		}
+		if rowsAffected, _ := res.RowsAffected(); rowsAffected == 0 {
+			return fmt.Errorf("片段不存在: %d", snippet.ID)
+		}
+		return nil
+	}
+
+	res, err := db.Exec("INSERT INTO snippets (db_id, table_name, name, description, value, updated_at) VALUES (?, ?, ?, ?, ?, ?)",
+		snippet.DatabaseID, snippet.TableName, snippet.Name, snippet.Description, string(jsonData), snippet.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("保存片段失败: %v", err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("获取片段ID失败: %v", err)
+	}
+	snippet.ID = int(id)
+	return nil
+}
+
+// LoadSnippets 加载连接下的片段（tableName 为空时返回该连接的全部片段）
+func LoadSnippets(dbID int, tableName string) ([]*SavedSnippet, error) {
+	query := "SELECT id, db_id, table_name, name, description, value, updated_at FROM snippets WHERE db_id = ?"
+	args := []interface{}{dbID}
+	if tableName != "" {
+		query += " AND table_name = ?"
+		args = append(args, tableName)
+	}
+	query += " ORDER BY table_name, id"
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("查询片段失败: %v", err)
+	}
+	defer rows.Close()
+
+	snippets := []*SavedSnippet{}
+	for rows.Next() {
+		snippet, err := scanSnippet(rows)
+		if err != nil {
+			return nil, err
+		}
+		snippets = append(snippets, snippet)
+	}
+	return snippets, rows.Err()
+}
+
+// LoadSnippetByID 根据ID加载片段（不存在时返回 nil）
+func LoadSnippetByID(id int) (*SavedSnippet, error) {
+	row := db.QueryRow("SELECT id, db_id, table_name, name, description, value, updated_at FROM snippets WHERE id = ?", id)
+	snippet, err := scanSnippet(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return snippet, err
+}
+
+// DeleteSnippet 从片段库删除片段
+func DeleteSnippet(id int) error {
+	_, err := db.Exec("DELETE FROM snippets WHERE id = ?", id)
+	return err
+}
+
+// scanSnippet 读取一行片段数据
+func scanSnippet(row interface{ Scan(...interface{}) error }) (*SavedSnippet, error) {
+	var snippet SavedSnippet
+	var value string
+	if err := row.Scan(&snippet.ID, &snippet.DatabaseID, &snippet.TableName, &snippet.Name,
+		&snippet.Description, &value, &snippet.UpdatedAt); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, err
+		}
+		return nil, fmt.Errorf("读取片段数据失败: %v", err)
+	}
+	if err := json.Unmarshal([]byte(value), &snippet.Config); err != nil {
+		return nil, fmt.Errorf("反序列化片段失败: %v", err)
+	}
+	return &snippet, nil
+}
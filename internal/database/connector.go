@@ -178,7 +178,11 @@ func (c *Connector) GetTableColumns(tableName string) ([]*TableColumn, error) {
 				IFNULL(COLUMN_COMMENT, '') as COLUMN_COMMENT,
 				IS_NULLABLE,
 				IFNULL(COLUMN_KEY, '') as COLUMN_KEY,
-				IFNULL(EXTRA, '') as EXTRA
+				IFNULL(EXTRA, '') as EXTRA,
+				CHARACTER_MAXIMUM_LENGTH,
+				NUMERIC_PRECISION,
+				NUMERIC_SCALE,
+				COLUMN_DEFAULT
 			FROM information_schema.COLUMNS
 			WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ?
 			ORDER BY ORDINAL_POSITION
@@ -193,11 +197,16 @@ func (c *Connector) GetTableColumns(tableName string) ([]*TableColumn, error) {
 				COALESCE(pg_catalog.col_description(a.attrelid, a.attnum), '') as column_comment,
 				NOT a.attnotnull as is_nullable,
 				CASE WHEN pk.conname IS NOT NULL THEN 'PRI' ELSE '' END as column_key,
-				'' as extra
+				'' as extra,
+				ic.character_maximum_length,
+				ic.numeric_precision,
+				ic.numeric_scale,
+				ic.column_default
 			FROM pg_catalog.pg_attribute a
 			LEFT JOIN pg_catalog.pg_class c ON a.attrelid = c.oid
 			LEFT JOIN pg_catalog.pg_namespace n ON c.relnamespace = n.oid
 			LEFT JOIN pg_catalog.pg_constraint pk ON pk.conrelid = c.oid AND a.attnum = ANY(pk.conkey) AND pk.contype = 'p'
+			LEFT JOIN information_schema.columns ic ON ic.table_schema = n.nspname AND ic.table_name = c.relname AND ic.column_name = a.attname
 			WHERE c.relname = $1
 				AND n.nspname = 'public'
 				AND a.attnum > 0
@@ -214,7 +223,11 @@ func (c *Connector) GetTableColumns(tableName string) ([]*TableColumn, error) {
 				NVL(cc.COMMENTS, '') as COLUMN_COMMENT,
 				c.NULLABLE,
 				CASE WHEN pk.COLUMN_NAME IS NOT NULL THEN 'PRI' ELSE '' END as COLUMN_KEY,
-				'' as EXTRA
+				'' as EXTRA,
+				CASE WHEN c.CHAR_LENGTH > 0 THEN c.CHAR_LENGTH END as CHAR_MAX_LENGTH,
+				c.DATA_PRECISION,
+				c.DATA_SCALE,
+				c.DATA_DEFAULT
 			FROM USER_TAB_COLUMNS c
 			LEFT JOIN USER_COL_COMMENTS cc ON c.TABLE_NAME = cc.TABLE_NAME AND c.COLUMN_NAME = cc.COLUMN_NAME
 			LEFT JOIN (
@@ -242,6 +255,8 @@ func (c *Connector) GetTableColumns(tableName string) ([]*TableColumn, error) {
 	for rows.Next() {
 		var column TableColumn
 		var isNullableStr string
+		var charMaxLength, numericPrecision, numericScale sql.NullInt64
+		var columnDefault sql.NullString
 
 		if err := rows.Scan(
 			&column.ColumnName,
@@ -250,13 +265,25 @@ func (c *Connector) GetTableColumns(tableName string) ([]*TableColumn, error) {
 			&isNullableStr,
 			&column.ColumnKey,
 			&column.Extra,
+			&charMaxLength,
+			&numericPrecision,
+			&numericScale,
+			&columnDefault,
 		); err != nil {
 			return nil, fmt.Errorf("读取列信息失败: %v", err)
 		}
 
-		// 处理 IS_NULLABLE
-		column.IsNullable = strings.ToUpper(isNullableStr) == "YES" || isNullableStr == "true" || isNullableStr == "t"
-		
+		// 处理 IS_NULLABLE（Oracle 使用 Y/N）
+		upperNullable := strings.ToUpper(isNullableStr)
+		column.IsNullable = upperNullable == "YES" || upperNullable == "Y" || isNullableStr == "true" || isNullableStr == "t"
+
+		// 长度、精度与默认值
+		column.CharMaxLength = charMaxLength.Int64
+		column.NumericPrecision = numericPrecision.Int64
+		column.NumericScale = numericScale.Int64
+		column.ColumnDefault = strings.TrimSpace(columnDefault.String)
+		column.HasDefault = columnDefault.Valid && column.ColumnDefault != "" && strings.ToUpper(column.ColumnDefault) != "NULL"
+
 		// 映射 JavaType 和 JdbcType
 		column.JavaType = GetJavaType(c.config.DbType, column.DataType, false) // 默认不使用 JSR310，由前端覆盖
 		column.JdbcType = GetJdbcType(c.config.DbType, column.DataType)
@@ -317,3 +344,282 @@ func (c *Connector) GetTableComment(tableName string) (string, error) {
 
 	return comment, nil
 }
+
+// GetForeignKeys 获取表上定义的外键（本表引用其他表）
+func (c *Connector) GetForeignKeys(tableName string) ([]*ForeignKey, error) {
+	return c.queryForeignKeys(tableName, false)
+}
+
+// GetReferencingForeignKeys 获取引用该表的外键（其他表引用本表）
+func (c *Connector) GetReferencingForeignKeys(tableName string) ([]*ForeignKey, error) {
+	return c.queryForeignKeys(tableName, true)
+}
+
+// queryForeignKeys 查询外键信息，referenced 为 true 时按被引用表过滤
+func (c *Connector) queryForeignKeys(tableName string, referenced bool) ([]*ForeignKey, error) {
+	if c.db == nil {
+		return nil, fmt.Errorf("数据库未连接")
+	}
+
+	var query string
+	var args []interface{}
+
+	switch c.config.DbType {
+	case config.DbTypeMySQL:
+		filter := "k.TABLE_SCHEMA = ? AND k.TABLE_NAME = ?"
+		if referenced {
+			filter = "k.REFERENCED_TABLE_SCHEMA = ? AND k.REFERENCED_TABLE_NAME = ?"
+		}
+		query = `
+			SELECT
+				k.CONSTRAINT_NAME,
+				k.TABLE_NAME,
+				k.COLUMN_NAME,
+				k.REFERENCED_TABLE_NAME,
+				k.REFERENCED_COLUMN_NAME
+			FROM information_schema.KEY_COLUMN_USAGE k
+			WHERE ` + filter + ` AND k.REFERENCED_TABLE_NAME IS NOT NULL
+			ORDER BY k.TABLE_NAME, k.CONSTRAINT_NAME, k.ORDINAL_POSITION
+		`
+		args = []interface{}{c.config.Schema, tableName}
+
+	case config.DbTypePostgreSQL:
+		filter := "cl.relname = $1"
+		if referenced {
+			filter = "rcl.relname = $1"
+		}
+		query = `
+			SELECT
+				con.conname,
+				cl.relname,
+				att.attname,
+				rcl.relname,
+				ratt.attname
+			FROM pg_catalog.pg_constraint con
+			JOIN pg_catalog.pg_class cl ON cl.oid = con.conrelid
+			JOIN pg_catalog.pg_namespace n ON n.oid = cl.relnamespace
+			JOIN pg_catalog.pg_class rcl ON rcl.oid = con.confrelid
+			CROSS JOIN LATERAL unnest(con.conkey, con.confkey) WITH ORDINALITY AS k(attnum, refattnum, ord)
+			JOIN pg_catalog.pg_attribute att ON att.attrelid = con.conrelid AND att.attnum = k.attnum
+			JOIN pg_catalog.pg_attribute ratt ON ratt.attrelid = con.confrelid AND ratt.attnum = k.refattnum
+			WHERE con.contype = 'f' AND n.nspname = 'public' AND ` + filter + `
+			ORDER BY cl.relname, con.conname, k.ord
+		`
+		args = []interface{}{tableName}
+
+	case config.DbTypeOracle:
+		filter := "c.TABLE_NAME = :1"
+		if referenced {
+			filter = "rc.TABLE_NAME = :1"
+		}
+		query = `
+			SELECT
+				c.CONSTRAINT_NAME,
+				c.TABLE_NAME,
+				cc.COLUMN_NAME,
+				rc.TABLE_NAME,
+				rcc.COLUMN_NAME
+			FROM USER_CONSTRAINTS c
+			JOIN USER_CONS_COLUMNS cc ON cc.CONSTRAINT_NAME = c.CONSTRAINT_NAME
+			JOIN USER_CONSTRAINTS rc ON rc.CONSTRAINT_NAME = c.R_CONSTRAINT_NAME
+			JOIN USER_CONS_COLUMNS rcc ON rcc.CONSTRAINT_NAME = rc.CONSTRAINT_NAME AND rcc.POSITION = cc.POSITION
+			WHERE c.CONSTRAINT_TYPE = 'R' AND ` + filter + `
+			ORDER BY c.TABLE_NAME, c.CONSTRAINT_NAME, cc.POSITION
+		`
+		args = []interface{}{strings.ToUpper(tableName)}
+
+	default:
+		return nil, fmt.Errorf("不支持的数据库类型: %s", c.config.DbType)
+	}
+
+	rows, err := c.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("查询外键信息失败: %v", err)
+	}
+	defer rows.Close()
+
+	// 按 表名+约束名 合并多列外键
+	var foreignKeys []*ForeignKey
+	fkMap := make(map[string]*ForeignKey)
+	for rows.Next() {
+		var constraintName, table, column, refTable, refColumn string
+		if err := rows.Scan(&constraintName, &table, &column, &refTable, &refColumn); err != nil {
+			return nil, fmt.Errorf("读取外键信息失败: %v", err)
+		}
+
+		key := table + "." + constraintName
+		fk, ok := fkMap[key]
+		if !ok {
+			fk = &ForeignKey{
+				ConstraintName:  constraintName,
+				TableName:       table,
+				ReferencedTable: refTable,
+			}
+			fkMap[key] = fk
+			foreignKeys = append(foreignKeys, fk)
+		}
+		fk.Columns = append(fk.Columns, column)
+		fk.ReferencedColumns = append(fk.ReferencedColumns, refColumn)
+	}
+
+	return foreignKeys, nil
+}
+
+// GetTableIndexes 获取表的索引信息（含主键、唯一及普通索引，忽略表达式/函数索引和部分索引）
+func (c *Connector) GetTableIndexes(tableName string) ([]*TableIndex, error) {
+	if c.db == nil {
+		return nil, fmt.Errorf("数据库未连接")
+	}
+
+	var query string
+	var args []interface{}
+
+	switch c.config.DbType {
+	case config.DbTypeMySQL:
+		query = `
+			SELECT
+				INDEX_NAME,
+				CASE WHEN NON_UNIQUE = 0 THEN 1 ELSE 0 END as IS_UNIQUE,
+				CASE WHEN INDEX_NAME = 'PRIMARY' THEN 1 ELSE 0 END as IS_PRIMARY,
+				COLUMN_NAME
+			FROM information_schema.STATISTICS
+			WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ?
+			ORDER BY INDEX_NAME, SEQ_IN_INDEX
+		`
+		args = []interface{}{c.config.Schema, tableName}
+
+	case config.DbTypePostgreSQL:
+		query = `
+			SELECT
+				i.relname as index_name,
+				CASE WHEN ix.indisunique THEN 1 ELSE 0 END as is_unique,
+				CASE WHEN ix.indisprimary THEN 1 ELSE 0 END as is_primary,
+				a.attname as column_name
+			FROM pg_catalog.pg_index ix
+			JOIN pg_catalog.pg_class t ON t.oid = ix.indrelid
+			JOIN pg_catalog.pg_class i ON i.oid = ix.indexrelid
+			JOIN pg_catalog.pg_namespace n ON n.oid = t.relnamespace
+			CROSS JOIN LATERAL unnest(ix.indkey::int2[]) WITH ORDINALITY AS k(attnum, ord)
+			LEFT JOIN pg_catalog.pg_attribute a ON a.attrelid = t.oid AND a.attnum = k.attnum
+			WHERE t.relname = $1
+				AND n.nspname = 'public'
+				AND ix.indpred IS NULL
+				AND k.ord <= ix.indnkeyatts
+			ORDER BY i.relname, k.ord
+		`
+		args = []interface{}{tableName}
+
+	case config.DbTypeOracle:
+		query = `
+			SELECT
+				i.INDEX_NAME,
+				CASE WHEN i.UNIQUENESS = 'UNIQUE' THEN 1 ELSE 0 END as IS_UNIQUE,
+				CASE WHEN pk.CONSTRAINT_NAME IS NOT NULL THEN 1 ELSE 0 END as IS_PRIMARY,
+				ic.COLUMN_NAME
+			FROM USER_INDEXES i
+			JOIN USER_IND_COLUMNS ic ON ic.INDEX_NAME = i.INDEX_NAME
+			LEFT JOIN USER_CONSTRAINTS pk ON pk.INDEX_NAME = i.INDEX_NAME AND pk.CONSTRAINT_TYPE = 'P'
+			WHERE i.TABLE_NAME = :1 AND i.INDEX_TYPE NOT LIKE 'FUNCTION-BASED%'
+			ORDER BY i.INDEX_NAME, ic.COLUMN_POSITION
+		`
+		args = []interface{}{strings.ToUpper(tableName)}
+
+	default:
+		return nil, fmt.Errorf("不支持的数据库类型: %s", c.config.DbType)
+	}
+
+	rows, err := c.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("查询索引信息失败: %v", err)
+	}
+	defer rows.Close()
+
+	var indexes []*TableIndex
+	indexMap := make(map[string]*TableIndex)
+	skipped := make(map[string]bool) // 含表达式列的索引
+	for rows.Next() {
+		var indexName string
+		var isUnique, isPrimary int
+		var columnName sql.NullString
+		if err := rows.Scan(&indexName, &isUnique, &isPrimary, &columnName); err != nil {
+			return nil, fmt.Errorf("读取索引信息失败: %v", err)
+		}
+
+		if !columnName.Valid || columnName.String == "" {
+			skipped[indexName] = true
+			continue
+		}
+
+		index, ok := indexMap[indexName]
+		if !ok {
+			index = &TableIndex{
+				IndexName: indexName,
+				IsUnique:  isUnique == 1,
+				IsPrimary: isPrimary == 1,
+			}
+			indexMap[indexName] = index
+			indexes = append(indexes, index)
+		}
+		index.Columns = append(index.Columns, columnName.String)
+	}
+
+	// 过滤含表达式列的索引
+	result := make([]*TableIndex, 0, len(indexes))
+	for _, index := range indexes {
+		if !skipped[index.IndexName] {
+			result = append(result, index)
+		}
+	}
+
+	return result, nil
+}
+
+// GetSequenceName 查找主键列对应的序列名，未找到时返回空字符串
+// PostgreSQL 使用 pg_get_serial_sequence；Oracle 按常见命名（表名_SEQ、SEQ_表名、表名_列名_SEQ）在 USER_SEQUENCES 中查找；MySQL 无序列
+func (c *Connector) GetSequenceName(tableName, columnName string) (string, error) {
+	if c.db == nil {
+		return "", fmt.Errorf("数据库未连接")
+	}
+
+	var query string
+	var args []interface{}
+
+	switch c.config.DbType {
+	case config.DbTypeMySQL:
+		return "", nil
+
+	case config.DbTypePostgreSQL:
+		query = `SELECT COALESCE(pg_get_serial_sequence($1, $2), '')`
+		args = []interface{}{tableName, columnName}
+
+	case config.DbTypeOracle:
+		table := strings.ToUpper(tableName)
+		column := strings.ToUpper(columnName)
+		query = `
+			SELECT SEQUENCE_NAME FROM (
+				SELECT SEQUENCE_NAME,
+					CASE SEQUENCE_NAME WHEN :1 THEN 1 WHEN :2 THEN 2 ELSE 3 END AS PRIORITY
+				FROM USER_SEQUENCES
+				WHERE SEQUENCE_NAME IN (:3, :4, :5)
+				ORDER BY PRIORITY
+			) WHERE ROWNUM = 1
+		`
+		args = []interface{}{
+			table + "_SEQ", "SEQ_" + table,
+			table + "_SEQ", "SEQ_" + table, table + "_" + column + "_SEQ",
+		}
+
+	default:
+		return "", fmt.Errorf("不支持的数据库类型: %s", c.config.DbType)
+	}
+
+	var sequenceName string
+	if err := c.db.QueryRow(query, args...).Scan(&sequenceName); err != nil {
+		if err == sql.ErrNoRows {
+			return "", nil
+		}
+		return "", fmt.Errorf("查询序列失败: %v", err)
+	}
+
+	return sequenceName, nil
+}
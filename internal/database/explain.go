@@ -0,0 +1,199 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/yourusername/mybatis-generator-gui-go/internal/config"
+)
+
+// ExplainResult 语句执行计划
+type ExplainResult struct {
+	Columns   []string   `json:"columns"`   // 计划列名
+	Rows      [][]string `json:"rows"`      // 计划行（值均转为字符串，NULL 为空串）
+	FullScans []string   `json:"fullScans"` // 存在全表扫描的表（去重，按出现顺序）
+}
+
+// oracleExplainStatementID Oracle 写入 PLAN_TABLE 时使用的语句标识
+const oracleExplainStatementID = "MBG_EXPLAIN"
+
+var pgSeqScanPattern = regexp.MustCompile(`Seq Scan on (\S+)`)
+
+// ExplainSQL 获取语句的执行计划，事务结束时总是回滚，不会修改数据
+// MySQL/PostgreSQL 在只读事务中执行 EXPLAIN；Oracle 的 EXPLAIN PLAN FOR 需要写入 PLAN_TABLE，
+// 无法在只读事务中执行，改为在普通事务中执行后回滚
+// 语句存在语法错误或引用了不存在的表/列时返回数据库的错误信息；args 为语句的驱动绑定参数
+func (c *Connector) ExplainSQL(query string, args []interface{}) (*ExplainResult, error) {
+	if c.db == nil {
+		return nil, fmt.Errorf("数据库未连接")
+	}
+
+	readOnly := c.config.DbType != config.DbTypeOracle
+	tx, err := c.db.BeginTx(context.Background(), &sql.TxOptions{ReadOnly: readOnly})
+	if err != nil {
+		return nil, fmt.Errorf("开启事务失败: %v", err)
+	}
+	defer tx.Rollback()
+
+	switch c.config.DbType {
+	case config.DbTypeMySQL, config.DbTypePostgreSQL:
+		rows, err := tx.Query("EXPLAIN "+query, args...)
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+		result, err := scanExplainRows(rows)
+		if err != nil {
+			return nil, err
+		}
+		if c.config.DbType == config.DbTypeMySQL {
+			result.FullScans = mysqlFullScans(result)
+		} else {
+			result.FullScans = postgresFullScans(result)
+		}
+		return result, nil
+
+	case config.DbTypeOracle:
+		if _, err := tx.Exec(fmt.Sprintf("EXPLAIN PLAN SET STATEMENT_ID = '%s' FOR %s", oracleExplainStatementID, query), args...); err != nil {
+			return nil, err
+		}
+		rows, err := tx.Query(`
+			SELECT ID, LPAD(' ', 2 * DEPTH) || OPERATION AS OPERATION, OPTIONS, OBJECT_NAME, CARDINALITY, COST
+			FROM PLAN_TABLE
+			WHERE STATEMENT_ID = :1
+			ORDER BY ID
+		`, oracleExplainStatementID)
+		if err != nil {
+			return nil, fmt.Errorf("读取执行计划失败: %v", err)
+		}
+		defer rows.Close()
+		result, err := scanExplainRows(rows)
+		if err != nil {
+			return nil, err
+		}
+		result.FullScans = oracleFullScans(result)
+		return result, nil
+
+	default:
+		return nil, fmt.Errorf("不支持的数据库类型: %s", c.config.DbType)
+	}
+}
+
+// scanExplainRows 将执行计划结果集读取为字符串表格
+func scanExplainRows(rows *sql.Rows) (*ExplainResult, error) {
+	columns, data, _, err := scanStringRows(rows, 0)
+	if err != nil {
+		return nil, fmt.Errorf("读取执行计划失败: %v", err)
+	}
+	return &ExplainResult{Columns: columns, Rows: data}, nil
+}
+
+// scanStringRows 读取结果集为字符串表格（NULL 为空串），maxRows 大于0时最多读取 maxRows 行
+// truncated 表示结果集还有未读取的行
+func scanStringRows(rows *sql.Rows, maxRows int) (columns []string, data [][]string, truncated bool, err error) {
+	if columns, err = rows.Columns(); err != nil {
+		return nil, nil, false, err
+	}
+
+	data = [][]string{}
+	values := make([]interface{}, len(columns))
+	ptrs := make([]interface{}, len(columns))
+	for i := range values {
+		ptrs[i] = &values[i]
+	}
+	for rows.Next() {
+		if maxRows > 0 && len(data) >= maxRows {
+			return columns, data, true, nil
+		}
+		if err := rows.Scan(ptrs...); err != nil {
+			return nil, nil, false, err
+		}
+		row := make([]string, len(columns))
+		for i, v := range values {
+			switch val := v.(type) {
+			case nil:
+			case []byte:
+				row[i] = string(val)
+			default:
+				row[i] = fmt.Sprint(val)
+			}
+		}
+		data = append(data, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, false, err
+	}
+	return columns, data, false, nil
+}
+
+// columnIndex 查找计划列下标（忽略大小写），不存在返回 -1
+func (r *ExplainResult) columnIndex(name string) int {
+	for i, col := range r.Columns {
+		if strings.EqualFold(col, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// mysqlFullScans MySQL 计划中 type 为 ALL 的表即为全表扫描
+func mysqlFullScans(r *ExplainResult) []string {
+	typeIdx, tableIdx := r.columnIndex("type"), r.columnIndex("table")
+	if typeIdx < 0 || tableIdx < 0 {
+		return []string{}
+	}
+	var tables []string
+	for _, row := range r.Rows {
+		if strings.EqualFold(row[typeIdx], "ALL") {
+			tables = appendUnique(tables, row[tableIdx])
+		}
+	}
+	return orEmpty(tables)
+}
+
+// postgresFullScans PostgreSQL 计划中 Seq Scan 节点即为全表扫描
+func postgresFullScans(r *ExplainResult) []string {
+	var tables []string
+	for _, row := range r.Rows {
+		for _, m := range pgSeqScanPattern.FindAllStringSubmatch(strings.Join(row, " "), -1) {
+			tables = appendUnique(tables, m[1])
+		}
+	}
+	return orEmpty(tables)
+}
+
+// oracleFullScans Oracle 计划中 TABLE ACCESS FULL 即为全表扫描
+func oracleFullScans(r *ExplainResult) []string {
+	opIdx, optIdx, objIdx := r.columnIndex("OPERATION"), r.columnIndex("OPTIONS"), r.columnIndex("OBJECT_NAME")
+	if opIdx < 0 || optIdx < 0 || objIdx < 0 {
+		return []string{}
+	}
+	var tables []string
+	for _, row := range r.Rows {
+		if strings.TrimSpace(row[opIdx]) == "TABLE ACCESS" && row[optIdx] == "FULL" {
+			tables = appendUnique(tables, row[objIdx])
+		}
+	}
+	return orEmpty(tables)
+}
+
+// appendUnique 追加不重复的表名
+func appendUnique(tables []string, name string) []string {
+	for _, t := range tables {
+		if t == name {
+			return tables
+		}
+	}
+	return append(tables, name)
+}
+
+// orEmpty 将 nil 切片转换为空切片（JSON 序列化为 []）
+func orEmpty(tables []string) []string {
+	if tables == nil {
+		return []string{}
+	}
+	return tables
+}
@@ -0,0 +1,61 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/yourusername/mybatis-generator-gui-go/internal/config"
+)
+
+// SandboxResult 沙箱执行结果
+type SandboxResult struct {
+	Columns      []string   `json:"columns"`      // 查询返回的列名
+	Rows         [][]string `json:"rows"`         // 查询返回的前 N 行（值均转为字符串，NULL 为空串）
+	Truncated    bool       `json:"truncated"`    // 是否还有未返回的行
+	RowsAffected int64      `json:"rowsAffected"` // DML 影响的行数（事务回滚前）
+}
+
+// ExecuteInSandbox 在事务中执行语句，事务结束时总是回滚，不会修改数据
+// args 为驱动绑定参数（占位符按数据库类型为 ?、$n 或 :n）
+// isQuery 为 true 时读取结果集的前 maxRows 行，否则执行 DML 并返回影响行数
+func (c *Connector) ExecuteInSandbox(query string, args []interface{}, isQuery bool, maxRows int) (*SandboxResult, error) {
+	if c.db == nil {
+		return nil, fmt.Errorf("数据库未连接")
+	}
+
+	tx, err := c.db.BeginTx(context.Background(), sandboxTxOptions(c.config.DbType, isQuery))
+	if err != nil {
+		return nil, fmt.Errorf("开启事务失败: %v", err)
+	}
+	defer tx.Rollback()
+
+	if !isQuery {
+		res, err := tx.Exec(query, args...)
+		if err != nil {
+			return nil, err
+		}
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return nil, fmt.Errorf("获取影响行数失败: %v", err)
+		}
+		return &SandboxResult{Columns: []string{}, Rows: [][]string{}, RowsAffected: affected}, nil
+	}
+
+	rows, err := tx.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	columns, data, truncated, err := scanStringRows(rows, maxRows)
+	if err != nil {
+		return nil, fmt.Errorf("读取查询结果失败: %v", err)
+	}
+	return &SandboxResult{Columns: columns, Rows: data, Truncated: truncated}, nil
+}
+
+// sandboxTxOptions 沙箱事务选项：查询在只读事务中执行
+// go-ora 不支持只读事务，Oracle 与 DML 一样使用普通事务，依靠结束时的回滚保证不修改数据
+func sandboxTxOptions(dbType string, isQuery bool) *sql.TxOptions {
+	return &sql.TxOptions{ReadOnly: isQuery && dbType != config.DbTypeOracle}
+}
@@ -0,0 +1,19 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yourusername/mybatis-generator-gui-go/internal/config"
+)
+
+func TestSandboxTxOptions(t *testing.T) {
+	assert.True(t, sandboxTxOptions(config.DbTypeMySQL, true).ReadOnly)
+	assert.True(t, sandboxTxOptions(config.DbTypePostgreSQL, true).ReadOnly)
+	// go-ora 开启只读事务会报错，Oracle 查询依靠回滚
+	assert.False(t, sandboxTxOptions(config.DbTypeOracle, true).ReadOnly)
+
+	for _, dbType := range []string{config.DbTypeMySQL, config.DbTypePostgreSQL, config.DbTypeOracle} {
+		assert.False(t, sandboxTxOptions(dbType, false).ReadOnly, dbType)
+	}
+}
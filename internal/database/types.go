@@ -2,14 +2,19 @@ package database
 
 // TableColumn 表列信息
 type TableColumn struct {
-	ColumnName    string `json:"columnName"`    // 列名
-	DataType      string `json:"dataType"`      // 数据类型
-	ColumnComment string `json:"columnComment"` // 列注释
-	IsNullable    bool   `json:"isNullable"`    // 是否可为空
-	ColumnKey     string `json:"columnKey"`     // 键类型 (PRI, UNI, MUL)
-	Extra         string `json:"extra"`         // 额外信息 (auto_increment等)
-	JavaType      string `json:"javaType"`      // 映射的Java类型
-	JdbcType      string `json:"jdbcType"`      // 映射的JDBC类型
+	ColumnName       string `json:"columnName"`       // 列名
+	DataType         string `json:"dataType"`         // 数据类型
+	ColumnComment    string `json:"columnComment"`    // 列注释
+	IsNullable       bool   `json:"isNullable"`       // 是否可为空
+	ColumnKey        string `json:"columnKey"`        // 键类型 (PRI, UNI, MUL)
+	Extra            string `json:"extra"`            // 额外信息 (auto_increment等)
+	JavaType         string `json:"javaType"`         // 映射的Java类型
+	JdbcType         string `json:"jdbcType"`         // 映射的JDBC类型
+	CharMaxLength    int64  `json:"charMaxLength"`    // 字符最大长度（非字符类型为0）
+	NumericPrecision int64  `json:"numericPrecision"` // 数值精度（非数值类型为0）
+	NumericScale     int64  `json:"numericScale"`     // 数值小数位数
+	ColumnDefault    string `json:"columnDefault"`    // 默认值表达式（无默认值为空）
+	HasDefault       bool   `json:"hasDefault"`       // 是否定义了默认值
 }
 
 // TableInfo 表信息
@@ -18,3 +23,20 @@ type TableInfo struct {
 	TableComment string         `json:"tableComment"` // 表注释
 	Columns      []*TableColumn `json:"columns"`      // 列信息
 }
+
+// ForeignKey 外键信息
+type ForeignKey struct {
+	ConstraintName    string   `json:"constraintName"`    // 约束名
+	TableName         string   `json:"tableName"`         // 外键所在表
+	Columns           []string `json:"columns"`           // 外键列（按约束中的顺序）
+	ReferencedTable   string   `json:"referencedTable"`   // 被引用表
+	ReferencedColumns []string `json:"referencedColumns"` // 被引用列（与 Columns 一一对应）
+}
+
+// TableIndex 索引信息
+type TableIndex struct {
+	IndexName string   `json:"indexName"` // 索引名
+	IsUnique  bool     `json:"isUnique"`  // 是否唯一索引
+	IsPrimary bool     `json:"isPrimary"` // 是否主键索引
+	Columns   []string `json:"columns"`   // 索引列（按索引中的顺序）
+}
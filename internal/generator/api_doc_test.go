@@ -0,0 +1,44 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yourusername/mybatis-generator-gui-go/internal/config"
+	"github.com/yourusername/mybatis-generator-gui-go/internal/database"
+)
+
+func TestBuildFieldDocAnnotation(t *testing.T) {
+	col := &database.TableColumn{ColumnName: "status", ColumnComment: `状态 "1"启用`, HasDefault: true, ColumnDefault: "1"}
+	imports := make(map[string]bool)
+
+	assert.Equal(t, `@Schema(description = "状态 \"1\"启用", example = "1")`,
+		buildFieldDocAnnotation(config.APIDocOpenAPI3, col, "status", imports))
+	assert.True(t, imports["io.swagger.v3.oas.annotations.media.Schema"])
+
+	required := &database.TableColumn{ColumnName: "user_name"}
+	assert.Equal(t, `@ApiModelProperty(value = "userName", required = true)`,
+		buildFieldDocAnnotation(config.APIDocSwagger2, required, "userName", imports))
+	assert.Equal(t, `@Schema(description = "userName", requiredMode = Schema.RequiredMode.REQUIRED)`,
+		buildFieldDocAnnotation(config.APIDocOpenAPI3, required, "userName", imports))
+
+	assert.Equal(t, "", buildFieldDocAnnotation("", col, "status", imports))
+}
+
+func TestExampleValue(t *testing.T) {
+	tests := []struct {
+		def      string
+		expected string
+	}{
+		{"'active'::character varying", "active"},
+		{"('0')", "0"},
+		{"12.5", "12.5"},
+		{"CURRENT_TIMESTAMP", ""},
+		{"nextval('user_id_seq'::regclass)", ""},
+	}
+
+	for _, tt := range tests {
+		col := &database.TableColumn{HasDefault: true, ColumnDefault: tt.def}
+		assert.Equal(t, tt.expected, exampleValue(col), tt.def)
+	}
+}
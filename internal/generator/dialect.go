@@ -0,0 +1,55 @@
+package generator
+
+import (
+	"fmt"
+
+	"github.com/yourusername/mybatis-generator-gui-go/internal/config"
+)
+
+// Dialect SQL方言，决定分页等数据库相关语法
+type Dialect struct {
+	DbType       string // 数据库类型（config.DbTypeXxx），空值按 MySQL 处理
+	LegacyPaging bool   // Oracle 12c 之前的版本使用 ROWNUM 分页
+}
+
+// NewDialect 根据数据库配置创建方言
+func NewDialect(dbCfg *config.DatabaseConfig) Dialect {
+	if dbCfg == nil {
+		return Dialect{DbType: config.DbTypeMySQL}
+	}
+	return Dialect{DbType: dbCfg.DbType, LegacyPaging: dbCfg.OracleLegacyPaging}
+}
+
+// IsOracle 是否为 Oracle 方言
+func (d Dialect) IsOracle() bool {
+	return d.DbType == config.DbTypeOracle
+}
+
+// IsPostgreSQL 是否为 PostgreSQL 方言
+func (d Dialect) IsPostgreSQL() bool {
+	return d.DbType == config.DbTypePostgreSQL
+}
+
+// PagingClauses 返回包裹查询语句的分页前缀与后缀（XML 已转义）
+// offsetExpr、limitExpr 为 SQL 表达式（如 #{offset}、10），offsetExpr 为空表示仅限制返回行数
+func (d Dialect) PagingClauses(offsetExpr, limitExpr string) (prefix, suffix string) {
+	switch {
+	case d.IsOracle() && d.LegacyPaging:
+		if offsetExpr == "" {
+			return "SELECT * FROM (", fmt.Sprintf(") WHERE ROWNUM &lt;= %s", limitExpr)
+		}
+		return "SELECT * FROM (SELECT p_.*, ROWNUM rn_ FROM (",
+			fmt.Sprintf(") p_ WHERE ROWNUM &lt;= %s + %s) WHERE rn_ &gt; %s", offsetExpr, limitExpr, offsetExpr)
+	case d.IsOracle():
+		if offsetExpr == "" {
+			return "", fmt.Sprintf("FETCH FIRST %s ROWS ONLY", limitExpr)
+		}
+		return "", fmt.Sprintf("OFFSET %s ROWS FETCH NEXT %s ROWS ONLY", offsetExpr, limitExpr)
+	default:
+		// MySQL / PostgreSQL
+		if offsetExpr == "" {
+			return "", "LIMIT " + limitExpr
+		}
+		return "", fmt.Sprintf("LIMIT %s OFFSET %s", limitExpr, offsetExpr)
+	}
+}
@@ -0,0 +1,62 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yourusername/mybatis-generator-gui-go/internal/config"
+)
+
+func TestDialectPagingClauses(t *testing.T) {
+	prefix, suffix := NewDialect(nil).PagingClauses("#{offset}", "#{limit}")
+	assert.Empty(t, prefix)
+	assert.Equal(t, "LIMIT #{limit} OFFSET #{offset}", suffix)
+
+	pg := NewDialect(&config.DatabaseConfig{DbType: config.DbTypePostgreSQL})
+	_, suffix = pg.PagingClauses("", "10")
+	assert.Equal(t, "LIMIT 10", suffix)
+
+	oracle := NewDialect(&config.DatabaseConfig{DbType: config.DbTypeOracle})
+	prefix, suffix = oracle.PagingClauses("#{offset}", "#{limit}")
+	assert.Empty(t, prefix)
+	assert.Equal(t, "OFFSET #{offset} ROWS FETCH NEXT #{limit} ROWS ONLY", suffix)
+	_, suffix = oracle.PagingClauses("", "10")
+	assert.Equal(t, "FETCH FIRST 10 ROWS ONLY", suffix)
+
+	legacy := NewDialect(&config.DatabaseConfig{DbType: config.DbTypeOracle, OracleLegacyPaging: true})
+	prefix, suffix = legacy.PagingClauses("#{offset}", "#{limit}")
+	assert.Equal(t, "SELECT * FROM (SELECT p_.*, ROWNUM rn_ FROM (", prefix)
+	assert.Equal(t, ") p_ WHERE ROWNUM &lt;= #{offset} + #{limit}) WHERE rn_ &gt; #{offset}", suffix)
+}
+
+func TestGenerateSnippet_LimitUsesDialect(t *testing.T) {
+	cfg := &config.SnippetConfig{
+		Operation:   config.OperationSelect,
+		WhereFields: []config.SnippetField{{ColumnName: "status", FieldName: "status", JavaType: "Integer", Operator: "="}},
+		HasLimit:    true,
+	}
+
+	result, err := GenerateSnippet(cfg, "UserMapper", "com.example.model.User", "user", NewDialect(nil))
+	assert.NoError(t, err)
+	assert.Contains(t, result.XMLCode, "LIMIT #{limit}")
+	// 附带同条件的 count 方法，且不含 limit 参数
+	assert.Contains(t, result.JavaCode, "long countByStatus(Integer status);")
+	assert.Contains(t, result.XMLCode, `<select id="countByStatus" resultType="java.lang.Long">`)
+
+	oracle := NewDialect(&config.DatabaseConfig{DbType: config.DbTypeOracle})
+	result, err = GenerateSnippet(cfg, "UserMapper", "com.example.model.User", "USER", oracle)
+	assert.NoError(t, err)
+	assert.Contains(t, result.XMLCode, "FETCH FIRST #{limit} ROWS ONLY")
+	assert.NotContains(t, result.XMLCode, "LIMIT")
+}
+
+func TestBuildCountMethodName(t *testing.T) {
+	assert.Equal(t, "countByStatus", buildCountMethodName("selectByStatus"))
+	assert.Equal(t, "countByName", buildCountMethodName("findByName"))
+	assert.Equal(t, "countSelect", buildCountMethodName("select"))
+	assert.Equal(t, "countSelectAll", buildCountMethodName("selectAll"))
+	assert.Equal(t, "countRecentOrders", buildCountMethodName("recentOrders"))
+	assert.Equal(t, "countByAmountGreaterThan", buildCountMethodName("findByAmountGreaterThanOrderByIdDesc"))
+	assert.Equal(t, "countSelectAll", buildCountMethodName("selectAllOrderByName"))
+	assert.Equal(t, "countByOrderByStatus", buildCountMethodName("selectByOrderByStatus"))
+}
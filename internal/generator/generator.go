@@ -5,12 +5,12 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"text/template"
 
 	"github.com/yourusername/mybatis-generator-gui-go/internal/config"
 	"github.com/yourusername/mybatis-generator-gui-go/internal/database"
-	"github.com/yourusername/mybatis-generator-gui-go/internal/utils"
 )
 
 // Generator 代码生成器
@@ -18,6 +18,12 @@ type Generator struct {
 	config    *config.GeneratorConfig
 	dbConfig  *config.DatabaseConfig
 	connector *database.Connector
+	dialect   Dialect
+	relations []*Relation             // 基于外键推导的关联关系（UseAssociations 时加载）
+	indexes   []*database.TableIndex  // 表索引（用于生成索引查询方法）
+	keyGen    *KeyGeneration          // 主键生成策略（KeyStrategy 非空时加载）
+	namer     *Namer                  // 命名规则（首次使用时按配置创建）
+	columns   []*database.TableColumn // 表列信息（Generate 时加载）
 }
 
 // NewGenerator 创建新的代码生成器
@@ -26,9 +32,15 @@ func NewGenerator(cfg *config.GeneratorConfig, dbCfg *config.DatabaseConfig) *Ge
 		config:    cfg,
 		dbConfig:  dbCfg,
 		connector: database.NewConnector(dbCfg),
+		dialect:   NewDialect(dbCfg),
 	}
 }
 
+// Columns 返回 Generate 过程中加载的表列信息
+func (g *Generator) Columns() []*database.TableColumn {
+	return g.columns
+}
+
 // Generate 生成代码
 func (g *Generator) Generate() ([]string, error) {
 	var generatedFiles []string
@@ -45,12 +57,39 @@ func (g *Generator) Generate() ([]string, error) {
 		return nil, fmt.Errorf("获取表列信息失败: %v", err)
 	}
 
+	g.columns = columns
+
 	// 获取表注释
 	tableComment, err := g.connector.GetTableComment(g.config.TableName)
 	if err != nil {
 		return nil, fmt.Errorf("获取表注释失败: %v", err)
 	}
 
+	// 获取索引信息（仅用于索引查询方法和 upsert 冲突判定，失败时跳过，不影响基础代码生成）
+	indexes, err := g.connector.GetTableIndexes(g.config.TableName)
+	if err != nil {
+		log.Printf("[Generator] 获取表 %s 的索引信息失败，跳过索引查询方法: %v", g.config.TableName, err)
+	}
+	g.indexes = indexes
+
+	// 加载主键生成策略
+	if g.config.KeyStrategy != "" {
+		keyGen, err := g.loadKeyGeneration(g.buildColumnMappings(columns))
+		if err != nil {
+			return nil, fmt.Errorf("获取主键生成策略失败: %v", err)
+		}
+		g.keyGen = keyGen
+	}
+
+	// 加载外键关联
+	if g.config.UseAssociations {
+		relations, err := g.loadRelations(columns)
+		if err != nil {
+			return nil, fmt.Errorf("获取外键关联失败: %v", err)
+		}
+		g.relations = relations
+	}
+
 	// 生成Model类
 	modelFile, err := g.generateModel(columns, tableComment)
 	if err != nil {
@@ -160,6 +199,13 @@ func (g *Generator) generateMapper(columns []*database.TableColumn) (string, err
 func (g *Generator) generateMapperXML(columns []*database.TableColumn) (string, error) {
 	// 准备模板数据
 	data := g.prepareMapperXMLData(columns)
+	if data.Upsert != nil {
+		upsertXML, err := renderUpsertXML(g.dialect, data)
+		if err != nil {
+			return "", err
+		}
+		data.UpsertXML = upsertXML
+	}
 
 	// 解析模板
 	tmpl, err := template.New("mapperXML").Parse(mapperXMLTemplate)
@@ -197,11 +243,12 @@ func (g *Generator) generateMapperXML(columns []*database.TableColumn) (string,
 
 // ModelField Model字段
 type ModelField struct {
-	FieldName    string // Java字段名
-	FieldType    string // Java类型
-	ColumnName   string // 数据库列名
-	Comment      string // 注释
-	IsPrimaryKey bool   // 是否主键
+	FieldName    string   // Java字段名
+	FieldType    string   // Java类型
+	ColumnName   string   // 数据库列名
+	Comment      string   // 注释
+	IsPrimaryKey bool     // 是否主键
+	Annotations  []string // 字段注解（如 Bean Validation 注解）
 }
 
 // ModelData Model模板数据
@@ -209,7 +256,9 @@ type ModelData struct {
 	Package                    string
 	ClassName                  string
 	TableComment               string
+	ClassAnnotations           []string // 类注解（如接口文档注解）
 	Fields                     []*ModelField
+	RelationFields             []*ModelField // 外键关联属性（不参与构造方法与equals/hashCode）
 	Imports                    []string
 	UseJsonProperty            bool
 	JsonPropertyUpperCase      bool
@@ -237,6 +286,9 @@ func (g *Generator) prepareModelData(columns []*database.TableColumn, tableComme
 		imports["java.util.Objects"] = true
 	}
 
+	// 类级别接口文档注解
+	data.ClassAnnotations = buildClassDocAnnotations(g.config.APIDocStyle, data.ClassName, tableComment, imports)
+
 	// 构建忽略列集合
 	ignoredSet := make(map[string]bool)
 	for _, col := range g.config.IgnoredColumns {
@@ -277,23 +329,60 @@ func (g *Generator) prepareModelData(columns []*database.TableColumn, tableComme
 			Comment:      col.ColumnComment,
 			IsPrimaryKey: col.ColumnKey == "PRI",
 		}
+
+		// 接口文档注解
+		if annotation := buildFieldDocAnnotation(g.config.APIDocStyle, col, fieldName, imports); annotation != "" {
+			field.Annotations = append(field.Annotations, annotation)
+		}
+
+		// Bean Validation 注解
+		if g.config.UseBeanValidation {
+			field.Annotations = append(field.Annotations,
+				buildValidationAnnotations(col, javaType, g.config.UseJakartaValidation, imports)...)
+		}
+
 		data.Fields = append(data.Fields, field)
 	}
 
-	// 转换imports为切片
+	// 外键关联属性
+	for _, relation := range g.relations {
+		fieldType := relation.ClassName
+		comment := "关联 " + relation.TableName
+		if relation.IsCollection() {
+			fieldType = "List<" + relation.ClassName + ">"
+			imports["java.util.List"] = true
+		}
+		data.RelationFields = append(data.RelationFields, &ModelField{
+			FieldName: relation.Property,
+			FieldType: fieldType,
+			Comment:   comment,
+		})
+	}
+
+	// 转换imports为切片（排序保证输出稳定）
 	for imp := range imports {
 		data.Imports = append(data.Imports, imp)
 	}
+	sort.Strings(data.Imports)
 
 	return data
 }
 
 // getFieldName 获取字段名
 func (g *Generator) getFieldName(columnName string) string {
-	if g.config.UseActualColumnNames {
-		return columnName
+	return g.names().FieldName(columnName, g.config.UseActualColumnNames)
+}
+
+// names 获取命名器，无效的正则规则会被忽略并记录日志
+func (g *Generator) names() *Namer {
+	if g.namer == nil {
+		namer, err := NewNamer(g.config.Naming)
+		if err != nil {
+			log.Printf("[Generator] %v", err)
+		}
+		g.namer = namer
 	}
-	return utils.DBStringToCamelCase(columnName)
+	return g.namer
 }
 
 // addImport 添加导入语句
@@ -362,6 +451,11 @@ type MapperData struct {
 	OffsetLimit    bool
 	UseBatchInsert bool
 	UseBatchUpdate bool
+	UseUpsert      bool // 是否生成 upsert（无主键且无唯一索引时为 false）
+	UseBatchUpsert bool
+	Relations      []*Relation
+	IndexFinders   []*IndexFinder
+	Imports        []string // 主键及索引查询方法参数类型需要的 import（如 java.util.Date）
 }
 
 // prepareMapperData 准备Mapper模板数据
@@ -375,6 +469,7 @@ func (g *Generator) prepareMapperData(columns []*database.TableColumn) *MapperDa
 		OffsetLimit:    g.config.OffsetLimit,
 		UseBatchInsert: g.config.UseBatchInsert,
 		UseBatchUpdate: g.config.UseBatchUpdate,
+		Relations:      g.relations,
 	}
 
 	if data.MapperName == "" {
@@ -395,6 +490,14 @@ func (g *Generator) prepareMapperData(columns []*database.TableColumn) *MapperDa
 		}
 	}
 
+	mappings := g.buildColumnMappings(columns)
+	data.IndexFinders = g.buildIndexFinders(mappings)
+	data.Imports = mapperParamImports(data.PrimaryKey, data.IndexFinders)
+	if upsert := g.buildUpsert(mappings, mappings); upsert != nil {
+		data.UseUpsert = true
+		data.UseBatchUpsert = upsert.Batch
+	}
+
 	return data
 }
 
@@ -408,43 +511,34 @@ type MapperXMLData struct {
 	InsertColumns     []*ColumnMapping // 插入时使用的列
 	PrimaryKey        *ColumnMapping
 	OffsetLimit       bool
+	PagePrefix        string // 分页包裹前缀（Oracle ROWNUM 分页）
+	PageSuffix        string // 分页后缀（LIMIT/OFFSET 或 OFFSET ... FETCH NEXT）
 	UseGeneratedKeys  bool
 	GenerateKeys      string
 	UseBatchInsert    bool
 	UseBatchUpdate    bool
 	NeedForUpdate     bool
 	UseTableNameAlias bool
+	Relations         []*Relation
+	IndexFinders      []*IndexFinder
+	KeyColumn         string         // 主键列名（useGeneratedKeys 的 keyColumn）
+	SequenceKey       *KeyGeneration // 序列主键，插入前通过 selectKey 获取
+	ReturningColumn   string         // PostgreSQL RETURNING 返回的主键列
+	Upsert            *Upsert        // 为 nil 时不生成 upsert
+	UpsertXML         string         // 按方言渲染后的 upsert 语句
 }
 
 // ColumnMapping 列映射
 type ColumnMapping struct {
-	ColumnName string
-	FieldName  string
-	JdbcType   string
-	JavaType   string
+	ColumnName   string
+	FieldName    string
+	JdbcType     string
+	JavaType     string
+	IsPrimaryKey bool
 }
 
-// prepareMapperXMLData 准备Mapper XML模板数据
-func (g *Generator) prepareMapperXMLData(columns []*database.TableColumn) *MapperXMLData {
-	mapperName := g.config.MapperName
-	if mapperName == "" {
-		mapperName = g.config.DomainObjectName + "Mapper"
-	}
-
-	data := &MapperXMLData{
-		Namespace:         g.config.DaoPackage + "." + mapperName,
-		ModelType:         g.config.ModelPackage + "." + g.config.DomainObjectName,
-		TableName:         g.config.TableName,
-		Columns:           make([]*ColumnMapping, 0),
-		OffsetLimit:       g.config.OffsetLimit,
-		UseGeneratedKeys:  g.config.GenerateKeys != "",
-		GenerateKeys:      g.config.GenerateKeys,
-		UseBatchInsert:    g.config.UseBatchInsert,
-		UseBatchUpdate:    g.config.UseBatchUpdate,
-		NeedForUpdate:     g.config.NeedForUpdate,
-		UseTableNameAlias: g.config.UseTableNameAlias,
-	}
-
+// buildColumnMappings 构建列映射（跳过忽略的列，并应用列覆盖配置）
+func (g *Generator) buildColumnMappings(columns []*database.TableColumn) []*ColumnMapping {
 	// 构建忽略列集合
 	ignoredSet := make(map[string]bool)
 	for _, col := range g.config.IgnoredColumns {
@@ -457,6 +551,7 @@ func (g *Generator) prepareMapperXMLData(columns []*database.TableColumn) *Mappe
 		overrideMap[override.ColumnName] = override
 	}
 
+	mappings := make([]*ColumnMapping, 0, len(columns))
 	for _, col := range columns {
 		// 检查是否忽略此列
 		if ignoredSet[col.ColumnName] {
@@ -475,30 +570,80 @@ func (g *Generator) prepareMapperXMLData(columns []*database.TableColumn) *Mappe
 			javaType = override.JavaType
 		}
 
-		jdbcType := database.GetJdbcType(g.dbConfig.DbType, col.DataType)
+		mappings = append(mappings, &ColumnMapping{
+			ColumnName:   col.ColumnName,
+			FieldName:    fieldName,
+			JdbcType:     database.GetJdbcType(g.dbConfig.DbType, col.DataType),
+			JavaType:     javaType,
+			IsPrimaryKey: col.ColumnKey == "PRI",
+		})
+	}
 
-		mapping := &ColumnMapping{
-			ColumnName: col.ColumnName,
-			FieldName:  fieldName,
-			JdbcType:   jdbcType,
-			JavaType:   javaType,
-		}
+	return mappings
+}
 
-		data.Columns = append(data.Columns, mapping)
+// prepareMapperXMLData 准备Mapper XML模板数据
+func (g *Generator) prepareMapperXMLData(columns []*database.TableColumn) *MapperXMLData {
+	mapperName := g.config.MapperName
+	if mapperName == "" {
+		mapperName = g.config.DomainObjectName + "Mapper"
+	}
 
+	data := &MapperXMLData{
+		Namespace:         g.config.DaoPackage + "." + mapperName,
+		ModelType:         g.config.ModelPackage + "." + g.config.DomainObjectName,
+		TableName:         g.config.TableName,
+		OffsetLimit:       g.config.OffsetLimit,
+		UseGeneratedKeys:  g.config.GenerateKeys != "",
+		GenerateKeys:      g.config.GenerateKeys,
+		UseBatchInsert:    g.config.UseBatchInsert,
+		UseBatchUpdate:    g.config.UseBatchUpdate,
+		NeedForUpdate:     g.config.NeedForUpdate,
+		UseTableNameAlias: g.config.UseTableNameAlias,
+		Relations:         g.relations,
+	}
+
+	data.Columns = g.buildColumnMappings(columns)
+	for _, mapping := range data.Columns {
 		// 记录主键，否则添加到非主键列
-		if col.ColumnKey == "PRI" {
+		if mapping.IsPrimaryKey {
 			data.PrimaryKey = mapping
 		} else {
 			data.NonPkColumns = append(data.NonPkColumns, mapping)
 		}
 	}
 
+	data.IndexFinders = g.buildIndexFinders(data.Columns)
+
+	if data.OffsetLimit {
+		data.PagePrefix, data.PageSuffix = g.dialect.PagingClauses("#{offset}", "#{limit}")
+	}
+
 	if data.PrimaryKey != nil && g.config.IgnorePKOnInsert {
 		data.InsertColumns = data.NonPkColumns
 	} else {
 		data.InsertColumns = data.Columns
 	}
 
+	if kg := g.keyGen; kg != nil {
+		switch kg.Strategy {
+		case config.KeyStrategySequence:
+			// 序列值在插入前获取，主键必须参与插入
+			data.UseGeneratedKeys = false
+			data.SequenceKey = kg
+			data.InsertColumns = data.Columns
+		default:
+			data.UseGeneratedKeys = true
+			data.GenerateKeys = kg.KeyProperty
+			data.KeyColumn = kg.KeyColumn
+			data.InsertColumns = data.NonPkColumns
+			if kg.Strategy == config.KeyStrategyReturning {
+				data.ReturningColumn = kg.KeyColumn
+			}
+		}
+	}
+
+	data.Upsert = g.buildUpsert(data.Columns, data.InsertColumns)
+
 	return data
 }
@@ -0,0 +1,97 @@
+package generator
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/yourusername/mybatis-generator-gui-go/internal/utils"
+)
+
+// IndexFinder 基于索引生成的查询方法
+type IndexFinder struct {
+	IndexName  string           // 索引名
+	MethodName string           // 方法名，如 selectByTenantIdAndCode
+	Unique     bool             // 唯一索引返回单个对象，否则返回 List
+	Columns    []*ColumnMapping // 查询条件列（按索引中的顺序）
+	Params     string           // Mapper 接口方法参数
+}
+
+// buildIndexFinders 根据索引构建查询方法：唯一索引总是生成，普通索引仅在 UseIndexFinders 时生成
+func (g *Generator) buildIndexFinders(mappings []*ColumnMapping) []*IndexFinder {
+	mappingMap := make(map[string]*ColumnMapping)
+	var pkColumns []string
+	for _, m := range mappings {
+		mappingMap[m.ColumnName] = m
+		if m.IsPrimaryKey {
+			pkColumns = append(pkColumns, m.ColumnName)
+		}
+	}
+	pkKey := strings.Join(pkColumns, ",")
+
+	var finders []*IndexFinder
+	seen := make(map[string]bool)
+	// 唯一索引优先，同名方法只保留第一个
+	for _, unique := range []bool{true, false} {
+		if !unique && !g.config.UseIndexFinders {
+			break
+		}
+		for _, index := range g.indexes {
+			if index.IsPrimary || index.IsUnique != unique || strings.Join(index.Columns, ",") == pkKey {
+				continue
+			}
+
+			finder := &IndexFinder{IndexName: index.IndexName, Unique: unique}
+			parts := make([]string, 0, len(index.Columns))
+			for _, columnName := range index.Columns {
+				mapping, ok := mappingMap[columnName]
+				if !ok {
+					finder = nil
+					break
+				}
+				finder.Columns = append(finder.Columns, mapping)
+				parts = append(parts, utils.FirstUpper(mapping.FieldName))
+			}
+			if finder == nil {
+				log.Printf("[Generator] 索引 %s 包含被忽略的列，跳过生成查询方法", index.IndexName)
+				continue
+			}
+
+			finder.MethodName = "selectBy" + strings.Join(parts, "And")
+			if seen[finder.MethodName] {
+				continue
+			}
+			seen[finder.MethodName] = true
+			finder.Params = indexFinderParams(finder.Columns)
+			finders = append(finders, finder)
+		}
+	}
+
+	return finders
+}
+
+// indexFinderParams 构建查询方法参数，多个参数时使用 @Param
+func indexFinderParams(columns []*ColumnMapping) string {
+	if len(columns) == 1 {
+		return fmt.Sprintf("%s %s", columns[0].JavaType, columns[0].FieldName)
+	}
+	params := make([]string, len(columns))
+	for i, col := range columns {
+		params[i] = fmt.Sprintf("@Param(\"%s\") %s %s", col.FieldName, col.JavaType, col.FieldName)
+	}
+	return strings.Join(params, ", ")
+}
+
+// mapperParamImports 收集 Mapper 接口方法参数类型需要的 import（主键与索引查询条件列）
+func mapperParamImports(pk *ModelField, finders []*IndexFinder) []string {
+	var types []string
+	if pk != nil {
+		types = append(types, pk.FieldType)
+	}
+	for _, finder := range finders {
+		for _, col := range finder.Columns {
+			types = append(types, col.JavaType)
+		}
+	}
+	return javaTypeImports(types)
+}
@@ -0,0 +1,76 @@
+package generator
+
+import (
+	"bytes"
+	"testing"
+	"text/template"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yourusername/mybatis-generator-gui-go/internal/config"
+	"github.com/yourusername/mybatis-generator-gui-go/internal/database"
+)
+
+func TestBuildIndexFinders(t *testing.T) {
+	mappings := []*ColumnMapping{
+		{ColumnName: "id", FieldName: "id", JavaType: "Long", JdbcType: "BIGINT", IsPrimaryKey: true},
+		{ColumnName: "tenant_id", FieldName: "tenantId", JavaType: "Long", JdbcType: "BIGINT"},
+		{ColumnName: "code", FieldName: "code", JavaType: "String", JdbcType: "VARCHAR"},
+		{ColumnName: "status", FieldName: "status", JavaType: "Integer", JdbcType: "INTEGER"},
+	}
+	indexes := []*database.TableIndex{
+		{IndexName: "PRIMARY", IsUnique: true, IsPrimary: true, Columns: []string{"id"}},
+		{IndexName: "uk_tenant_code", IsUnique: true, Columns: []string{"tenant_id", "code"}},
+		{IndexName: "idx_status", Columns: []string{"status"}},
+		{IndexName: "idx_deleted", Columns: []string{"deleted"}}, // 被忽略的列
+	}
+
+	g := &Generator{config: &config.GeneratorConfig{}, indexes: indexes}
+	finders := g.buildIndexFinders(mappings)
+	assert.Len(t, finders, 1, "普通索引默认不生成")
+	assert.Equal(t, "selectByTenantIdAndCode", finders[0].MethodName)
+	assert.True(t, finders[0].Unique)
+	assert.Equal(t, `@Param("tenantId") Long tenantId, @Param("code") String code`, finders[0].Params)
+
+	g.config.UseIndexFinders = true
+	finders = g.buildIndexFinders(mappings)
+	assert.Len(t, finders, 2)
+	assert.Equal(t, "selectByStatus", finders[1].MethodName)
+	assert.False(t, finders[1].Unique)
+	assert.Equal(t, "Integer status", finders[1].Params)
+}
+
+func TestMapperTemplate_IndexFinders(t *testing.T) {
+	data := &MapperData{
+		Package:      "com.example.dao",
+		MapperName:   "UserMapper",
+		ModelPackage: "com.example.model",
+		ModelName:    "User",
+		IndexFinders: []*IndexFinder{
+			{IndexName: "uk_email", MethodName: "selectByEmail", Unique: true, Params: "String email"},
+			{IndexName: "idx_status", MethodName: "selectByStatus", Params: "Integer status"},
+			{
+				IndexName: "uk_biz_date", MethodName: "selectByBizDateAndAmount", Unique: true,
+				Columns: []*ColumnMapping{{ColumnName: "biz_date", FieldName: "bizDate", JavaType: "Date"}, {ColumnName: "amount", FieldName: "amount", JavaType: "BigDecimal"}},
+				Params:  `@Param("bizDate") Date bizDate, @Param("amount") BigDecimal amount`,
+			},
+		},
+	}
+	data.Imports = mapperParamImports(&ModelField{FieldName: "id", FieldType: "Long"}, data.IndexFinders)
+	assert.Equal(t, []string{"java.math.BigDecimal", "java.util.Date"}, data.Imports)
+
+	tmpl, err := template.New("mapper").Parse(mapperTemplate)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		t.Fatal(err)
+	}
+
+	output := buf.String()
+	assert.Contains(t, output, "User selectByEmail(String email);")
+	assert.Contains(t, output, "List<User> selectByStatus(Integer status);")
+	// 索引列的参数类型需导入，否则生成的 Mapper 无法编译
+	assert.Contains(t, output, "import org.apache.ibatis.annotations.Param;\nimport java.math.BigDecimal;\nimport java.util.Date;\n")
+}
@@ -0,0 +1,103 @@
+package generator
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/yourusername/mybatis-generator-gui-go/internal/config"
+)
+
+// KeyGeneration 主键生成策略
+type KeyGeneration struct {
+	Strategy     string // config.KeyStrategyXxx
+	KeyProperty  string // 主键属性名
+	KeyColumn    string // 主键列名
+	ResultType   string // selectKey 结果类型（全限定名）
+	SequenceName string // 序列名（sequence 策略）
+	SelectKeySQL string // selectKey 中获取下一个序列值的语句
+	NextValExpr  string // 批量插入时直接取序列值的表达式
+}
+
+// loadKeyGeneration 根据 KeyStrategy 构建主键生成策略，sequence 策略未指定序列名时自动查找
+func (g *Generator) loadKeyGeneration(mappings []*ColumnMapping) (*KeyGeneration, error) {
+	pk := singlePrimaryKey(mappings)
+	if pk == nil {
+		log.Printf("[Generator] 表 %s 没有单列主键，跳过主键生成策略", g.config.TableName)
+		return nil, nil
+	}
+
+	sequenceName := strings.ReplaceAll(g.config.SequenceName, "{table}", g.config.TableName)
+	if g.config.KeyStrategy == config.KeyStrategySequence && sequenceName == "" {
+		name, err := g.connector.GetSequenceName(g.config.TableName, pk.ColumnName)
+		if err != nil {
+			return nil, err
+		}
+		if name == "" {
+			return nil, fmt.Errorf("未找到表 %s 主键 %s 对应的序列，请手动指定序列名", g.config.TableName, pk.ColumnName)
+		}
+		sequenceName = name
+	}
+
+	return g.newKeyGeneration(pk, sequenceName)
+}
+
+// newKeyGeneration 按方言构建主键生成策略
+func (g *Generator) newKeyGeneration(pk *ColumnMapping, sequenceName string) (*KeyGeneration, error) {
+	keyGen := &KeyGeneration{
+		Strategy:    g.config.KeyStrategy,
+		KeyProperty: pk.FieldName,
+		KeyColumn:   pk.ColumnName,
+		ResultType:  javaTypeFQN(pk.JavaType),
+	}
+
+	switch g.config.KeyStrategy {
+	case config.KeyStrategyIdentity:
+	case config.KeyStrategySequence:
+		keyGen.SequenceName = sequenceName
+		switch {
+		case g.dialect.IsOracle():
+			keyGen.NextValExpr = sequenceName + ".NEXTVAL"
+			keyGen.SelectKeySQL = "SELECT " + keyGen.NextValExpr + " FROM DUAL"
+		case g.dialect.IsPostgreSQL():
+			keyGen.NextValExpr = fmt.Sprintf("nextval('%s')", sequenceName)
+			keyGen.SelectKeySQL = "SELECT " + keyGen.NextValExpr
+		default:
+			return nil, fmt.Errorf("%s 不支持序列主键策略", g.dialect.DbType)
+		}
+	case config.KeyStrategyReturning:
+		if !g.dialect.IsPostgreSQL() {
+			return nil, fmt.Errorf("RETURNING 主键策略仅支持 PostgreSQL")
+		}
+	default:
+		return nil, fmt.Errorf("未知主键生成策略: %s", g.config.KeyStrategy)
+	}
+
+	return keyGen, nil
+}
+
+// singlePrimaryKey 返回单列主键，无主键或联合主键时返回 nil
+func singlePrimaryKey(mappings []*ColumnMapping) *ColumnMapping {
+	var pk *ColumnMapping
+	for _, m := range mappings {
+		if m.IsPrimaryKey {
+			if pk != nil {
+				return nil
+			}
+			pk = m
+		}
+	}
+	return pk
+}
+
+// javaTypeFQN 获取 Java 类型的全限定名
+func javaTypeFQN(javaType string) string {
+	switch {
+	case strings.Contains(javaType, "."):
+		return javaType
+	case javaType == "BigDecimal" || javaType == "BigInteger":
+		return "java.math." + javaType
+	default:
+		return "java.lang." + javaType
+	}
+}
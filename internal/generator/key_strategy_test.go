@@ -0,0 +1,94 @@
+package generator
+
+import (
+	"bytes"
+	"testing"
+	"text/template"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yourusername/mybatis-generator-gui-go/internal/config"
+	"github.com/yourusername/mybatis-generator-gui-go/internal/database"
+)
+
+func TestNewKeyGeneration(t *testing.T) {
+	pk := &ColumnMapping{ColumnName: "ID", FieldName: "id", JavaType: "Long", IsPrimaryKey: true}
+
+	oracle := &Generator{
+		config:  &config.GeneratorConfig{KeyStrategy: config.KeyStrategySequence},
+		dialect: NewDialect(&config.DatabaseConfig{DbType: config.DbTypeOracle}),
+	}
+	keyGen, err := oracle.newKeyGeneration(pk, "USER_SEQ")
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT USER_SEQ.NEXTVAL FROM DUAL", keyGen.SelectKeySQL)
+	assert.Equal(t, "java.lang.Long", keyGen.ResultType)
+
+	pg := &Generator{
+		config:  &config.GeneratorConfig{KeyStrategy: config.KeyStrategySequence},
+		dialect: NewDialect(&config.DatabaseConfig{DbType: config.DbTypePostgreSQL}),
+	}
+	keyGen, err = pg.newKeyGeneration(pk, "public.user_id_seq")
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT nextval('public.user_id_seq')", keyGen.SelectKeySQL)
+
+	mysql := &Generator{
+		config:  &config.GeneratorConfig{KeyStrategy: config.KeyStrategyReturning},
+		dialect: NewDialect(nil),
+	}
+	_, err = mysql.newKeyGeneration(pk, "")
+	assert.Error(t, err, "RETURNING 仅支持 PostgreSQL")
+	mysql.config.KeyStrategy = config.KeyStrategySequence
+	_, err = mysql.newKeyGeneration(pk, "user_seq")
+	assert.Error(t, err, "MySQL 不支持序列")
+}
+
+func renderKeyStrategyXML(t *testing.T, dbType, strategy, sequenceName string) string {
+	dbCfg := &config.DatabaseConfig{DbType: dbType}
+	g := &Generator{
+		config:   &config.GeneratorConfig{TableName: "user", KeyStrategy: strategy, IgnorePKOnInsert: true},
+		dbConfig: dbCfg,
+		dialect:  NewDialect(dbCfg),
+	}
+	idType := "bigint"
+	if dbType == config.DbTypeOracle {
+		idType = "number"
+	}
+	columns := []*database.TableColumn{
+		{ColumnName: "id", DataType: idType, ColumnKey: "PRI"},
+		{ColumnName: "name", DataType: "varchar"},
+	}
+	keyGen, err := g.newKeyGeneration(singlePrimaryKey(g.buildColumnMappings(columns)), sequenceName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.keyGen = keyGen
+
+	tmpl, err := template.New("mapperXML").Parse(mapperXMLTemplate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, g.prepareMapperXMLData(columns)); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+func TestMapperXMLTemplate_KeyStrategies(t *testing.T) {
+	// 序列：插入前获取序列值，主键参与插入
+	output := renderKeyStrategyXML(t, config.DbTypeOracle, config.KeyStrategySequence, "USER_SEQ")
+	assert.Contains(t, output, `<selectKey keyProperty="id" resultType="java.math.BigDecimal" order="BEFORE">`)
+	assert.Contains(t, output, "SELECT USER_SEQ.NEXTVAL FROM DUAL")
+	assert.Contains(t, output, "id, name")
+	assert.NotContains(t, output, "useGeneratedKeys")
+
+	// RETURNING：主键由数据库生成并返回
+	output = renderKeyStrategyXML(t, config.DbTypePostgreSQL, config.KeyStrategyReturning, "")
+	assert.Contains(t, output, `useGeneratedKeys="true" keyProperty="id" keyColumn="id"`)
+	assert.Contains(t, output, "RETURNING id")
+	assert.NotContains(t, output, "<selectKey")
+
+	// identity
+	output = renderKeyStrategyXML(t, config.DbTypeMySQL, config.KeyStrategyIdentity, "")
+	assert.Contains(t, output, `useGeneratedKeys="true" keyProperty="id" keyColumn="id"`)
+	assert.NotContains(t, output, "RETURNING")
+}
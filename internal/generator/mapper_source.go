@@ -0,0 +1,239 @@
+package generator
+
+import (
+	"encoding/xml"
+	"strings"
+)
+
+// javaImport Java 源码中的一条 import 声明
+type javaImport struct {
+	Name   string // 导入的类型或静态成员（不含 static 关键字，如 java.util.List、org.junit.Assert.*）
+	Static bool   // 是否为 import static
+	Start  int    // 声明所在行的行首下标
+	End    int    // 声明所在行的行尾下标（换行符之后）
+}
+
+// javaLayout Java 源码的结构位置，下标均基于原始内容
+type javaLayout struct {
+	PackageEnd int          // package 语句所在行的行尾下标，无 package 为 -1
+	Imports    []javaImport // import 声明（按出现顺序）
+	TypeName   string       // 第一个顶层类型的名称
+	BodyStart  int          // 第一个顶层类型体 { 的下标，未找到为 -1
+	BodyEnd    int          // 第一个顶层类型体 } 的下标，未找到或未闭合为 -1
+}
+
+// locateJava 定位 package、import 及顶层类型体的位置
+// 忽略注释和字符串中的内容；注解参数中的 {}（如 @SuppressWarnings({"a"})）不视为类型体
+func locateJava(src string) *javaLayout {
+	code := stripJavaCommentsAndStrings(src)
+	layout := &javaLayout{PackageEnd: -1, BodyStart: -1, BodyEnd: -1}
+	depth, parens := 0, 0
+	for i := 0; i < len(code); i++ {
+		ch := code[i]
+		switch {
+		case ch == '(':
+			parens++
+		case ch == ')':
+			parens--
+		case ch == '{' && parens == 0:
+			if depth == 0 && layout.BodyStart < 0 {
+				layout.BodyStart = i
+			}
+			depth++
+		case ch == '}' && parens == 0:
+			depth--
+			if depth == 0 && layout.BodyStart >= 0 {
+				layout.BodyEnd = i
+				return layout
+			}
+		case depth == 0 && parens == 0 && isJavaIdentStart(ch) && (i == 0 || !isJavaIdentPart(code[i-1])):
+			start := i
+			for i+1 < len(code) && isJavaIdentPart(code[i+1]) {
+				i++
+			}
+			word := code[start : i+1]
+			switch word {
+			case "class", "interface", "enum", "record":
+				if layout.TypeName == "" {
+					layout.TypeName = nextJavaIdent(code, i+1)
+				}
+				continue
+			case "package", "import":
+			default:
+				continue
+			}
+			semi := strings.IndexByte(code[i:], ';')
+			if semi < 0 {
+				return layout
+			}
+			semi += i
+			if word == "package" {
+				layout.PackageEnd = lineEnd(src, semi)
+			} else {
+				fields := strings.Fields(code[i+1 : semi])
+				imp := javaImport{Start: lineStart(src, start), End: lineEnd(src, semi)}
+				if len(fields) > 0 && fields[0] == "static" {
+					imp.Static = true
+					fields = fields[1:]
+				}
+				imp.Name = strings.Join(fields, "")
+				layout.Imports = append(layout.Imports, imp)
+			}
+			i = semi
+		}
+	}
+	return layout
+}
+
+// hasImport 判断是否已导入（含通配导入，如 java.util.* 覆盖 java.util.List）
+func (l *javaLayout) hasImport(name string, static bool) bool {
+	owner := ""
+	if dot := strings.LastIndex(name, "."); dot >= 0 {
+		owner = name[:dot]
+	}
+	for _, imp := range l.Imports {
+		if imp.Static != static {
+			continue
+		}
+		if imp.Name == name || owner != "" && imp.Name == owner+".*" {
+			return true
+		}
+	}
+	return false
+}
+
+// importInsertPos 新 import 的插入位置：同类（普通/静态）import 之后；
+// 普通 import 没有同类时插在第一条静态 import 之前，静态 import 没有同类时插在最后一条 import 之后；
+// 没有任何 import 返回 -1
+func (l *javaLayout) importInsertPos(static bool) int {
+	pos := -1
+	for _, imp := range l.Imports {
+		if imp.Static == static {
+			pos = imp.End
+		}
+	}
+	if pos >= 0 || len(l.Imports) == 0 {
+		return pos
+	}
+	if static {
+		return l.Imports[len(l.Imports)-1].End
+	}
+	return l.Imports[0].Start
+}
+
+// nextJavaIdent 返回下标 i 之后的第一个标识符，不存在返回空串
+func nextJavaIdent(code string, i int) string {
+	i = skipSpaces(code, i)
+	start := i
+	for i < len(code) && isJavaIdentPart(code[i]) {
+		i++
+	}
+	return code[start:i]
+}
+
+// lineStart 返回下标 i 所在行的行首下标
+func lineStart(src string, i int) int {
+	return strings.LastIndexByte(src[:i], '\n') + 1
+}
+
+// lineEnd 返回下标 i 所在行的行尾下标（换行符之后），最后一行返回内容长度
+func lineEnd(src string, i int) int {
+	if nl := strings.IndexByte(src[i:], '\n'); nl >= 0 {
+		return i + nl + 1
+	}
+	return len(src)
+}
+
+// mapperCloseOffset 按 XML 结构定位根元素 </mapper> 的下标
+// 注释、CDATA 中的 </mapper> 不受影响；XML 无法解析或根元素不是 mapper 时返回 -1
+func mapperCloseOffset(xmlContent string) int {
+	decoder := xml.NewDecoder(strings.NewReader(xmlContent))
+	depth := 0
+	for {
+		offset := int(decoder.InputOffset())
+		tok, err := decoder.Token()
+		if err != nil {
+			return -1
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if depth == 0 && t.Name.Local != "mapper" {
+				return -1
+			}
+			depth++
+		case xml.EndElement:
+			depth--
+			if depth == 0 {
+				return offset
+			}
+		}
+	}
+}
+
+// stripJavaCommentsAndStrings 将注释、字符串和字符字面量替换为空格（保留换行和下标位置）
+func stripJavaCommentsAndStrings(src string) string {
+	b := []byte(src)
+	for i := 0; i < len(b); i++ {
+		switch {
+		case b[i] == '/' && i+1 < len(b) && b[i+1] == '/':
+			for i < len(b) && b[i] != '\n' {
+				b[i] = ' '
+				i++
+			}
+		case b[i] == '/' && i+1 < len(b) && b[i+1] == '*':
+			for i < len(b) && !(b[i] == '*' && i+1 < len(b) && b[i+1] == '/') {
+				if b[i] != '\n' {
+					b[i] = ' '
+				}
+				i++
+			}
+			if i < len(b) {
+				b[i], b[i+1] = ' ', ' '
+				i++
+			}
+		case b[i] == '"' || b[i] == '\'':
+			quote := b[i]
+			for i++; i < len(b) && b[i] != quote && b[i] != '\n'; i++ {
+				if b[i] == '\\' && i+1 < len(b) {
+					b[i] = ' '
+					i++
+				}
+				b[i] = ' '
+			}
+		}
+	}
+	return string(b)
+}
+
+// matchParen 返回与 open 处 ( 匹配的 ) 下标，未闭合返回 -1
+func matchParen(code string, open int) int {
+	depth := 0
+	for i := open; i < len(code); i++ {
+		switch code[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// skipSpaces 跳过空白字符，返回下一个非空白字符的下标
+func skipSpaces(code string, i int) int {
+	for i < len(code) && strings.ContainsRune(" \t\r\n", rune(code[i])) {
+		i++
+	}
+	return i
+}
+
+func isJavaIdentStart(ch byte) bool {
+	return ch == '_' || ch == '$' || ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z'
+}
+
+func isJavaIdentPart(ch byte) bool {
+	return isJavaIdentStart(ch) || ch >= '0' && ch <= '9'
+}
@@ -0,0 +1,109 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yourusername/mybatis-generator-gui-go/internal/config"
+)
+
+const uploadedMapperJava = `/*
+ * Copyright; see LICENSE
+ * import com.example.Ignored;
+ */
+package com.example.mapper;
+
+import java.util.*;
+import com.example.User;
+import static org.apache.ibatis.jdbc.SqlBuilder.*;
+
+@SuppressWarnings({"unchecked"})
+public interface UserMapper {
+    // 旧方法 }
+    @Select("SELECT '}' FROM user")
+    List<User> selectAll();
+}
+// end of UserMapper }
+`
+
+func TestLocateJava(t *testing.T) {
+	layout := locateJava(uploadedMapperJava)
+	assert.Equal(t, "package com.example.mapper;\n\nimport java.util.*;\n", uploadedMapperJava[layout.PackageEnd-28:layout.Imports[0].End])
+	assert.Len(t, layout.Imports, 3)
+	assert.Equal(t, "org.apache.ibatis.jdbc.SqlBuilder.*", layout.Imports[2].Name)
+	assert.True(t, layout.Imports[2].Static)
+	assert.Equal(t, "}\n// end", uploadedMapperJava[layout.BodyEnd:layout.BodyEnd+8])
+
+	assert.True(t, layout.hasImport("java.util.List", false))
+	assert.False(t, layout.hasImport("com.example.Ignored", false))
+	assert.False(t, layout.hasImport("java.util.List", true))
+}
+
+func TestAppendImportsToJava(t *testing.T) {
+	output := AppendImportsToJava(uploadedMapperJava, []string{
+		"java.util.List",
+		"org.apache.ibatis.annotations.Param",
+		"com.example.Ignored",
+		"static org.junit.Assert.assertEquals",
+	})
+	assert.Contains(t, output, "import com.example.User;\nimport org.apache.ibatis.annotations.Param;\nimport com.example.Ignored;\nimport static org.apache.ibatis")
+	assert.Contains(t, output, "SqlBuilder.*;\nimport static org.junit.Assert.assertEquals;\n\n@SuppressWarnings")
+	assert.NotContains(t, output, "import java.util.List;")
+
+	// 没有 import 时插入到 package 语句之后；普通 import 插在静态 import 之前
+	output = AppendImportsToJava("package a; // pkg\n\ninterface A {}\n", []string{"java.util.List"})
+	assert.Equal(t, "package a; // pkg\n\nimport java.util.List;\n\ninterface A {}\n", output)
+	output = AppendImportsToJava("import static a.B.c;\ninterface A {}\n", []string{"java.util.List"})
+	assert.Equal(t, "import java.util.List;\nimport static a.B.c;\ninterface A {}\n", output)
+}
+
+func TestAppendSnippetToJava(t *testing.T) {
+	output := AppendSnippetToJava(uploadedMapperJava, "    int countAll();")
+	assert.Contains(t, output, "    List<User> selectAll();\n\n    int countAll();\n}\n// end of UserMapper }\n")
+}
+
+func TestAppendSnippetToXML(t *testing.T) {
+	xmlText := `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE mapper PUBLIC "-//mybatis.org//DTD Mapper 3.0//EN" "http://mybatis.org/dtd/mybatis-3-mapper.dtd">
+<mapper namespace="com.example.UserMapper">
+    <select id="selectAll"><![CDATA[ SELECT '</mapper>' ]]></select>
+</mapper>
+<!-- </mapper> -->
+`
+	output := AppendSnippetToXML(xmlText, `    <delete id="deleteAll">DELETE FROM user</delete>`)
+	assert.Contains(t, output, "</select>\n\n    <delete id=\"deleteAll\">DELETE FROM user</delete>\n</mapper>\n<!-- </mapper> -->\n")
+
+	// 无法解析时按最后一个 </mapper> 插入
+	assert.Equal(t, "<mapper><x>\n<y/>\n</mapper>", AppendSnippetToXML("<mapper><x></mapper>", "<y/>"))
+}
+
+func TestMergeSnippetsIntoMapper(t *testing.T) {
+	xmlText := `<mapper namespace="com.example.mapper.UserMapper">
+    <resultMap id="BaseResultMap" type="com.example.User"/>
+</mapper>
+`
+	assert.Equal(t, "UserMapper", JavaTypeName(uploadedMapperJava))
+	assert.Equal(t, "com.example.User", MapperModelType(xmlText))
+
+	snippets := []config.SnippetConfig{{
+		MethodName: "findByStatus",
+		Operation:  config.OperationSelect,
+		WhereFields: []config.SnippetField{
+			{ColumnName: "status", FieldName: "status", JavaType: "Integer", JdbcType: "INTEGER"},
+			{ColumnName: "name", FieldName: "name", JavaType: "String", JdbcType: "VARCHAR"},
+		},
+	}}
+	merges, err := ResolveSnippetConflicts(snippets, uploadedMapperJava, xmlText, "UserMapper", "com.example.User", "user", NewDialect(nil), "")
+	assert.NoError(t, err)
+	javaContent, xmlContent := MergeSnippetsIntoMapper(uploadedMapperJava, xmlText, merges)
+
+	// java.util.* 已覆盖 List，只补充 @Param
+	assert.Contains(t, javaContent, "import com.example.User;\nimport org.apache.ibatis.annotations.Param;\n")
+	assert.NotContains(t, javaContent, "import java.util.List;")
+	assert.Contains(t, javaContent, "List<User> findByStatus(@Param(\"status\") Integer status, @Param(\"name\") String name);\n\n}\n// end of UserMapper }\n")
+	assert.Contains(t, xmlContent, `<select id="findByStatus"`)
+
+	unchangedJava, unchangedXML := MergeSnippetsIntoMapper(uploadedMapperJava, xmlText, nil)
+	assert.Equal(t, uploadedMapperJava, unchangedJava)
+	assert.Equal(t, xmlText, unchangedXML)
+}
@@ -6,7 +6,8 @@ const mapperTemplate = `package {{.Package}};
 import {{.ModelPackage}}.{{.ModelName}};
 import java.util.List;
 import org.apache.ibatis.annotations.Param;
-
+{{range .Imports}}import {{.}};
+{{end}}
 /**
  * {{.ModelName}}Mapper接口
  */
@@ -45,18 +46,48 @@ public interface {{.MapperName}} {
      * 分页查询
      */
     List<{{.ModelName}}> selectByPage(@Param("offset") int offset, @Param("limit") int limit);
+
+    /**
+     * 查询总数（配合分页查询）
+     */
+    long countAll();
 {{end}}
 {{if .UseBatchInsert}}
     /**
      * 批量插入
      */
     int insertBatch(@Param("list") List<{{.ModelName}}> list);
+{{end}}{{if .UseUpsert}}
+    /**
+     * 插入或更新（冲突时更新）
+     */
+    int upsert({{.ModelName}} record);
+
+    /**
+     * 插入或更新（选择性）
+     */
+    int upsertSelective({{.ModelName}} record);
+{{end}}{{if .UseBatchUpsert}}
+    /**
+     * 批量插入或更新
+     */
+    int upsertBatch(@Param("list") List<{{.ModelName}}> list);
 {{end}}
 {{if .UseBatchUpdate}}
     /**
      * 批量更新
      */
     int updateBatch(@Param("list") List<{{.ModelName}}> list);
-{{end}}
+{{end}}{{range .IndexFinders}}
+    /**
+     * 根据{{if .Unique}}唯一{{end}}索引 {{.IndexName}} 查询
+     */
+    {{if .Unique}}{{$.ModelName}}{{else}}List<{{$.ModelName}}>{{end}} {{.MethodName}}({{.Params}});
+{{end}}{{if .PrimaryKey}}{{range .Relations}}
+    /**
+     * 根据主键查询并关联 {{.TableName}}
+     */
+    {{$.ModelName}} {{.MethodName}}({{$.PrimaryKey.FieldType}} {{$.PrimaryKey.FieldName}});
+{{end}}{{end}}
 }
 `
@@ -0,0 +1,926 @@
+package generator
+
+import (
+	"encoding/xml"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/yourusername/mybatis-generator-gui-go/internal/config"
+	"github.com/yourusername/mybatis-generator-gui-go/internal/database"
+	"github.com/yourusername/mybatis-generator-gui-go/internal/utils"
+)
+
+// MapperXMLImport Mapper XML 反向导入结果
+type MapperXMLImport struct {
+	Snippets []ImportedSnippet `json:"snippets"` // 可转换为片段配置的语句
+	Raw      []RawStatement    `json:"raw"`      // 无法用片段配置表达的语句（保留原文）
+}
+
+// ImportedSnippet 由 XML 语句转换得到的片段配置
+type ImportedSnippet struct {
+	StatementID string               `json:"statementId"`
+	Config      config.SnippetConfig `json:"config"`
+}
+
+// RawStatement 无法转换的语句
+type RawStatement struct {
+	StatementID string `json:"statementId"`
+	Kind        string `json:"kind"`   // select / insert / update / delete
+	Reason      string `json:"reason"` // 无法转换的原因
+	XML         string `json:"xml"`    // 语句原文
+}
+
+// mybatisParamPattern #{name} 或 #{name,jdbcType=...}
+var mybatisParamPattern = regexp.MustCompile(`#\{([^}]*)\}`)
+
+// ImportMapperXML 解析 Mapper XML，将单表的简单语句转换为片段配置
+// 支持：普通列查询（映射到实体类）、COUNT(*) 计数查询、WHERE 比较/LIKE/IN/IS NULL 条件（<where> 中的 <if> 视为可选条件）、
+// ORDER BY、LIMIT/FETCH FIRST，以及按实体属性插入/更新和按条件删除；其余语句原样列为 raw
+func ImportMapperXML(xmlText, tableName, modelType string, columns []*database.TableColumn) (*MapperXMLImport, error) {
+	root, err := parseMapperXML(xmlText)
+	if err != nil {
+		return nil, err
+	}
+	sources := statementSources(xmlText)
+
+	cols := make(map[string]*database.TableColumn, len(columns))
+	for _, col := range columns {
+		cols[strings.ToLower(col.ColumnName)] = col
+	}
+
+	result := &MapperXMLImport{Snippets: []ImportedSnippet{}, Raw: []RawStatement{}}
+	for _, stmt := range root.Children {
+		switch stmt.Name {
+		case "select", "insert", "update", "delete":
+		default:
+			continue
+		}
+		id := stmt.Attrs["id"]
+		im := &xmlStatementImporter{root: root, stmt: stmt, tableName: tableName, modelType: modelType, columns: cols}
+		cfg := im.convert()
+		if len(im.issues) == 0 {
+			// 转换结果需能正常生成片段
+			if _, err := GenerateSnippet(cfg, "", modelType, tableName, NewDialect(nil)); err != nil {
+				im.unsupported("%v", err)
+			}
+		}
+		if len(im.issues) > 0 {
+			result.Raw = append(result.Raw, RawStatement{StatementID: id, Kind: stmt.Name, Reason: strings.Join(im.issues, "; "), XML: sources[id]})
+			continue
+		}
+		result.Snippets = append(result.Snippets, ImportedSnippet{StatementID: id, Config: *cfg})
+	}
+
+	// 带 LIMIT 的查询会自动生成计数方法，已有的同名计数语句不再单独导入
+	kept := make([]ImportedSnippet, 0, len(result.Snippets))
+	for _, s := range result.Snippets {
+		if owner := countCompanionOwner(result.Snippets, s.StatementID); owner != "" {
+			result.Raw = append(result.Raw, RawStatement{StatementID: s.StatementID, Kind: string(s.Config.Operation), XML: sources[s.StatementID]})
+			continue
+		}
+		kept = append(kept, s)
+	}
+	for i, raw := range result.Raw {
+		if owner := countCompanionOwner(kept, raw.StatementID); owner != "" {
+			result.Raw[i].Reason = fmt.Sprintf("导入 %s 后自动生成同名计数方法", owner)
+		}
+	}
+	result.Snippets = kept
+	return result, nil
+}
+
+// countCompanionOwner 返回以 id 为计数方法的分页查询语句 id，不存在返回空串
+func countCompanionOwner(snippets []ImportedSnippet, id string) string {
+	for _, s := range snippets {
+		if s.StatementID != id && s.Config.Operation == config.OperationSelect && s.Config.HasLimit &&
+			buildCountMethodName(s.StatementID) == id {
+			return s.StatementID
+		}
+	}
+	return ""
+}
+
+// statementSources 按语句 id 提取 <mapper> 下各语句的原文
+func statementSources(xmlText string) map[string]string {
+	if !mapperRootPattern.MatchString(xmlText) {
+		xmlText = "<mapper>" + xmlText + "</mapper>"
+	}
+	sources := make(map[string]string)
+	decoder := xml.NewDecoder(strings.NewReader(xmlText))
+	depth, start := 0, 0
+	id := ""
+	for {
+		offset := int(decoder.InputOffset())
+		tok, err := decoder.Token()
+		if err != nil {
+			return sources
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			depth++
+			if depth == 2 {
+				start, id = offset, ""
+				for _, attr := range t.Attr {
+					if attr.Name.Local == "id" {
+						id = attr.Value
+					}
+				}
+			}
+		case xml.EndElement:
+			if depth == 2 && id != "" {
+				sources[id] = xmlText[start:decoder.InputOffset()]
+			}
+			depth--
+		}
+	}
+}
+
+// xmlStatementImporter 单条 XML 语句的转换状态
+type xmlStatementImporter struct {
+	root      *mapperNode
+	stmt      *mapperNode
+	tableName string
+	modelType string
+	columns   map[string]*database.TableColumn // 小写列名 -> 列
+
+	optional    map[string]bool // <where> 的 <if> 中出现的参数
+	collections map[string]bool // <foreach> 的集合参数
+	including   map[string]bool
+	issues      []string
+
+	tokens []sqlToken
+	sql    string
+	pos    int
+	alias  string // FROM/UPDATE 中主表的别名
+}
+
+func (im *xmlStatementImporter) unsupported(format string, args ...interface{}) {
+	msg := fmt.Sprintf(format, args...)
+	for _, issue := range im.issues {
+		if issue == msg {
+			return
+		}
+	}
+	im.issues = append(im.issues, msg)
+}
+
+// convert 展开动态标签后按语句类型解析 SQL，存在不支持的写法时返回 nil 并记录原因
+func (im *xmlStatementImporter) convert() *config.SnippetConfig {
+	im.optional = make(map[string]bool)
+	im.collections = make(map[string]bool)
+	im.including = make(map[string]bool)
+
+	var b strings.Builder
+	im.flatten(im.stmt, &b, false, false)
+	if len(im.issues) > 0 {
+		return nil
+	}
+	im.sql = b.String()
+	tokens, err := tokenizeSQL(im.sql)
+	if err != nil {
+		im.unsupported("SQL 解析失败: %v", err)
+		return nil
+	}
+	for len(tokens) > 0 && tokens[len(tokens)-1].is(";") {
+		tokens = tokens[:len(tokens)-1]
+	}
+	if len(tokens) == 0 {
+		im.unsupported("语句为空")
+		return nil
+	}
+	im.tokens = tokens
+
+	cfg := &config.SnippetConfig{MethodName: im.stmt.Attrs["id"], Operation: config.SnippetOperation(im.stmt.Name)}
+	switch im.stmt.Name {
+	case "select":
+		im.parseSelect(cfg)
+	case "insert":
+		im.parseInsert(cfg)
+	case "update":
+		im.parseUpdate(cfg)
+	case "delete":
+		im.parseDelete(cfg)
+	}
+	if len(im.issues) == 0 && im.pos < len(im.tokens) {
+		im.unsupported("无法识别的内容: %s", im.sql[im.tokens[im.pos].Start:])
+	}
+	if len(im.issues) > 0 {
+		return nil
+	}
+	return cfg
+}
+
+// flatten 将语句展开为 SQL 文本，#{name} 替换为 :name 占位符
+// 仅支持 <where> 中的 <if>（可选条件）、IN 集合 <foreach>、<include> 和不含 <if> 的 <set>
+func (im *xmlStatementImporter) flatten(n *mapperNode, b *strings.Builder, inWhere, inIf bool) {
+	for _, child := range n.Children {
+		switch child.Name {
+		case "":
+			im.flattenText(child.Text, b, inIf)
+		case "where":
+			if inWhere {
+				im.unsupported("不支持嵌套的 <where>")
+				continue
+			}
+			b.WriteString(" WHERE ")
+			im.flatten(child, b, true, false)
+		case "if":
+			if !inWhere || inIf {
+				im.unsupported("仅支持 <where> 中的 <if>")
+				continue
+			}
+			b.WriteString(" ")
+			im.flatten(child, b, inWhere, true)
+			b.WriteString(" ")
+		case "set":
+			b.WriteString(" SET ")
+			im.flatten(child, b, false, false)
+		case "foreach":
+			im.flattenForeach(child, b, inIf)
+		case "choose":
+			// 生成器为必选 IN 条件生成的空集合保护：<when> 中为 IN 集合，<otherwise> 为 1 = 0 / 1 = 1
+			when := inCollectionGuard(child)
+			if when == nil || !inWhere {
+				im.unsupported("不支持的标签 <choose>")
+				continue
+			}
+			im.flatten(when, b, inWhere, inIf)
+		case "include":
+			refID := child.Attrs["refid"]
+			fragment := im.root.findSQLFragment(refID)
+			switch {
+			case fragment == nil:
+				im.unsupported("SQL 片段不存在: %s", refID)
+			case hasChildElement(child, "property"):
+				im.unsupported("不支持带 <property> 的 <include>")
+			case im.including[refID]:
+				im.unsupported("SQL 片段循环引用: %s", refID)
+			default:
+				im.including[refID] = true
+				b.WriteString(" ")
+				im.flatten(fragment, b, inWhere, inIf)
+				b.WriteString(" ")
+				delete(im.including, refID)
+			}
+		default:
+			im.unsupported("不支持的标签 <%s>", child.Name)
+		}
+	}
+}
+
+// inCollectionGuard 识别 IN 条件的空集合保护写法，返回其中的 <when>；不是该写法返回 nil
+func inCollectionGuard(choose *mapperNode) *mapperNode {
+	var when, otherwise *mapperNode
+	for _, child := range choose.Children {
+		switch child.Name {
+		case "":
+		case "when":
+			if when != nil {
+				return nil
+			}
+			when = child
+		case "otherwise":
+			otherwise = child
+		default:
+			return nil
+		}
+	}
+	if when == nil || otherwise == nil || !hasChildElement(when, "foreach") || len(otherwise.Children) != 1 {
+		return nil
+	}
+	switch compactSQL(otherwise.Children[0].Text) {
+	case "1 = 0", "1 = 1":
+		return when
+	}
+	return nil
+}
+
+// hasChildElement 判断节点是否包含指定名称的子元素
+func hasChildElement(n *mapperNode, name string) bool {
+	for _, child := range n.Children {
+		if child.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// flattenText 替换文本中的 #{} 参数；${} 文本替换和非简单参数（如 user.name）无法转换
+func (im *xmlStatementImporter) flattenText(text string, b *strings.Builder, inIf bool) {
+	if strings.Contains(text, "${") {
+		im.unsupported("不支持 ${} 文本替换")
+		return
+	}
+	b.WriteString(mybatisParamPattern.ReplaceAllStringFunc(text, func(m string) string {
+		name := strings.TrimSpace(strings.SplitN(m[2:len(m)-1], ",", 2)[0])
+		if !javaIdentifierPattern.MatchString(name) {
+			im.unsupported("不支持的参数写法: %s", m)
+			return m
+		}
+		if inIf {
+			im.optional[name] = true
+		}
+		return ":" + name
+	}))
+}
+
+// flattenForeach 仅支持 IN 集合写法：<foreach collection="ids" item="x" open="(" separator="," close=")">#{x}</foreach>
+func (im *xmlStatementImporter) flattenForeach(n *mapperNode, b *strings.Builder, inIf bool) {
+	collection, item := n.Attrs["collection"], n.Attrs["item"]
+	body := ""
+	for _, child := range n.Children {
+		if child.Name != "" {
+			im.unsupported("不支持 <foreach> 中的 <%s>", child.Name)
+			return
+		}
+		body += child.Text
+	}
+	m := mybatisParamPattern.FindStringSubmatch(strings.TrimSpace(body))
+	if m == nil || m[0] != strings.TrimSpace(body) || strings.TrimSpace(strings.SplitN(m[1], ",", 2)[0]) != item ||
+		strings.TrimSpace(n.Attrs["open"]) != "(" || strings.TrimSpace(n.Attrs["close"]) != ")" ||
+		strings.TrimSpace(n.Attrs["separator"]) != "," || !javaIdentifierPattern.MatchString(collection) {
+		im.unsupported("仅支持 IN (...) 集合参数的 <foreach>")
+		return
+	}
+	im.collections[collection] = true
+	if inIf {
+		im.optional[collection] = true
+	}
+	b.WriteString(" (:" + collection + ") ")
+}
+
+// ---- 语句解析 ----
+
+func (im *xmlStatementImporter) peek() sqlToken {
+	if im.pos < len(im.tokens) {
+		return im.tokens[im.pos]
+	}
+	return sqlToken{}
+}
+
+// accept 当前词法单元为指定关键字/符号时前进并返回 true
+func (im *xmlStatementImporter) accept(text string) bool {
+	if im.pos < len(im.tokens) && im.tokens[im.pos].is(text) {
+		im.pos++
+		return true
+	}
+	return false
+}
+
+// expect 要求当前为指定关键字/符号
+func (im *xmlStatementImporter) expect(text string) bool {
+	if im.accept(text) {
+		return true
+	}
+	if im.pos < len(im.tokens) {
+		im.unsupported("此处应为 %s: %s", text, im.tokens[im.pos].Text)
+	} else {
+		im.unsupported("语句缺少 %s", text)
+	}
+	return false
+}
+
+// parseTable 解析主表（[schema.]table [AS] [alias]），表名需为当前表
+func (im *xmlStatementImporter) parseTable() bool {
+	tok := im.peek()
+	if tok.Kind != sqlWord {
+		im.unsupported("未找到语句操作的表")
+		return false
+	}
+	im.pos++
+	name := tok.Text
+	if im.accept(".") {
+		name = im.peek().Text
+		im.pos++
+	}
+	if !strings.EqualFold(name, im.tableName) {
+		im.unsupported("操作的表为 %s，不是当前表 %s", name, im.tableName)
+		return false
+	}
+	im.accept("AS")
+	if next := im.peek(); next.Kind == sqlWord && !sqlReservedWords[strings.ToUpper(next.Text)] {
+		im.alias = next.Text
+		im.pos++
+	}
+	if im.peek().is(",") || im.peek().is("JOIN") || im.peek().is("LEFT") || im.peek().is("INNER") ||
+		im.peek().is("RIGHT") || im.peek().is("CROSS") {
+		im.unsupported("不支持关联查询")
+		return false
+	}
+	return true
+}
+
+// parseColumn 解析列引用（[alias.]column），限定符需为主表的表名或别名
+func (im *xmlStatementImporter) parseColumn() *database.TableColumn {
+	tok := im.peek()
+	if tok.Kind != sqlWord {
+		im.unsupported("此处应为列名: %s", tok.Text)
+		return nil
+	}
+	im.pos++
+	name := tok.Text
+	if im.accept(".") {
+		if !strings.EqualFold(tok.Text, im.alias) && !strings.EqualFold(tok.Text, im.tableName) {
+			im.unsupported("不支持引用其他表的列: %s", tok.Text)
+			return nil
+		}
+		name = im.peek().Text
+		im.pos++
+	}
+	col := im.columns[strings.ToLower(name)]
+	if col == nil {
+		im.unsupported("表 %s 中不存在列 %s", im.tableName, name)
+	}
+	return col
+}
+
+// paramName 当前为 :name 占位符时返回参数名并前进
+func (im *xmlStatementImporter) paramName() (string, bool) {
+	tok := im.peek()
+	if tok.Kind != sqlParam || !strings.HasPrefix(tok.Text, ":") {
+		return "", false
+	}
+	im.pos++
+	return tok.Text[1:], true
+}
+
+func (im *xmlStatementImporter) parseSelect(cfg *config.SnippetConfig) {
+	im.expect("SELECT")
+	if im.accept("DISTINCT") {
+		im.unsupported("不支持 DISTINCT")
+		return
+	}
+
+	// 查询列：* 或主表的普通列（可带别名），不支持表达式和聚合；单独的 COUNT(*) 映射为计数返回形式
+	// 列的限定符在 FROM 之前出现，解析主表后统一校验
+	countAll := im.acceptCountAll()
+	allColumns := false
+	var qualifiers []string
+	for !countAll {
+		tok := im.peek()
+		switch {
+		case im.accept("*"):
+			allColumns = true
+		case tok.Kind == sqlWord && im.pos+2 < len(im.tokens) && im.tokens[im.pos+1].is(".") && im.tokens[im.pos+2].is("*"):
+			qualifiers = append(qualifiers, tok.Text)
+			im.pos += 3
+			allColumns = true
+		default:
+			f, qualifier := im.parseSelectColumn()
+			if f == nil {
+				if len(im.issues) == 0 {
+					im.unsupported("查询列不是普通列: %s", tok.Text)
+				}
+				return
+			}
+			if qualifier != "" {
+				qualifiers = append(qualifiers, qualifier)
+			}
+			cfg.SelectFields = append(cfg.SelectFields, *f)
+		}
+		if !im.accept(",") {
+			break
+		}
+	}
+	if allColumns && len(cfg.SelectFields) > 0 {
+		im.unsupported("不支持 * 与列混用")
+		return
+	}
+
+	if !im.expect("FROM") || !im.parseTable() {
+		return
+	}
+	for _, q := range qualifiers {
+		if !strings.EqualFold(q, im.alias) && !strings.EqualFold(q, im.tableName) {
+			im.unsupported("不支持引用其他表的列: %s", q)
+			return
+		}
+	}
+
+	if im.accept("WHERE") {
+		im.parseWhere(cfg)
+	}
+	if im.peek().is("GROUP") || im.peek().is("HAVING") {
+		im.unsupported("不支持 GROUP BY / HAVING")
+		return
+	}
+	if im.accept("ORDER") {
+		im.expect("BY")
+		im.parseOrderBy(cfg)
+	}
+	im.parseLimit(cfg)
+
+	if countAll {
+		if resultType := im.stmt.Attrs["resultType"]; !countResultTypes[resultType] {
+			im.unsupported("计数查询的返回类型 %s 不是整数类型", resultType)
+			return
+		}
+		cfg.ReturnShape = config.ReturnShapeCount
+		return
+	}
+
+	// 查询结果需映射到实体类
+	resultType := im.stmt.Attrs["resultType"]
+	if resultMapID := im.stmt.Attrs["resultMap"]; resultMapID != "" {
+		// 生成的片段引用 Mapper 中的 BaseResultMap，单独导入片段时视为映射到实体类
+		if rm := im.root.findResultMap(resultMapID); rm != nil {
+			resultType = rm.Attrs["type"]
+		} else if resultMapID == snippetBaseResultMap {
+			resultType = im.modelType
+		} else {
+			im.unsupported("resultMap %s 不存在", resultMapID)
+			return
+		}
+	}
+	switch {
+	case resultType == "":
+		im.unsupported("未指定 resultType 或 resultMap")
+	case resultType != im.modelType:
+		im.unsupported("返回类型 %s 不是实体类 %s", resultType, im.modelType)
+	}
+}
+
+// countResultTypes 计数查询可用的返回类型（含 MyBatis 内置别名）
+var countResultTypes = map[string]bool{
+	"java.lang.Long": true, "java.lang.Integer": true, "long": true, "int": true,
+	"Long": true, "Integer": true, "_long": true, "_int": true, "integer": true,
+}
+
+// acceptCountAll 当前为 COUNT(*) / COUNT(1)（可带别名）时前进并返回 true
+func (im *xmlStatementImporter) acceptCountAll() bool {
+	if im.pos+3 >= len(im.tokens) {
+		return false
+	}
+	t := im.tokens[im.pos : im.pos+4]
+	arg := t[2].is("*") || (t[2].Kind == sqlNumber && t[2].Text == "1")
+	if !t[0].is("COUNT") || !t[1].is("(") || !arg || !t[3].is(")") {
+		return false
+	}
+	im.pos += 4
+	im.accept("AS")
+	if next := im.peek(); next.Kind == sqlWord && !sqlReservedWords[strings.ToUpper(next.Text)] {
+		im.pos++
+	}
+	return true
+}
+
+// parseSelectColumn 解析查询列（[qualifier.]column [[AS] alias]），返回字段及限定符；不是普通列返回 nil
+func (im *xmlStatementImporter) parseSelectColumn() (*config.SnippetField, string) {
+	tok := im.peek()
+	if tok.Kind != sqlWord || sqlReservedWords[strings.ToUpper(tok.Text)] {
+		return nil, ""
+	}
+	im.pos++
+	qualifier, name := "", tok.Text
+	if im.accept(".") {
+		qualifier, name = tok.Text, im.peek().Text
+		im.pos++
+	}
+	if im.peek().is("(") {
+		return nil, ""
+	}
+	col := im.columns[strings.ToLower(name)]
+	if col == nil {
+		im.unsupported("表 %s 中不存在列 %s", im.tableName, name)
+		return nil, ""
+	}
+	f := &config.SnippetField{
+		ColumnName: col.ColumnName,
+		FieldName:  utils.DBStringToCamelCase(col.ColumnName),
+		JdbcType:   col.JdbcType,
+		JavaType:   col.JavaType,
+	}
+	im.accept("AS")
+	if next := im.peek(); next.Kind == sqlWord && !sqlReservedWords[strings.ToUpper(next.Text)] {
+		f.Alias = next.Text
+		im.pos++
+	}
+	return f, qualifier
+}
+
+// parseWhere 解析 WHERE 条件（条件之间需统一为 AND 或 OR，不支持括号分组）
+func (im *xmlStatementImporter) parseWhere(cfg *config.SnippetConfig) {
+	// <where> 中首个条件前的 AND/OR 由 MyBatis 去除
+	if !im.accept("AND") {
+		im.accept("OR")
+	}
+	for {
+		tok := im.peek()
+		if im.pos >= len(im.tokens) || tok.is("ORDER") || tok.is("GROUP") || tok.is("LIMIT") || tok.is("FETCH") {
+			return
+		}
+		f, ok := im.parseCondition()
+		if !ok {
+			return
+		}
+		cfg.WhereFields = append(cfg.WhereFields, f)
+
+		logic := strings.ToUpper(im.peek().Text)
+		if logic != "AND" && logic != "OR" {
+			return
+		}
+		if cfg.WhereLogic != "" && cfg.WhereLogic != logic {
+			im.unsupported("不支持 AND 与 OR 混用")
+			return
+		}
+		cfg.WhereLogic = logic
+		im.pos++
+	}
+}
+
+// parseCondition 解析单个条件：比较、LIKE、IN、IS [NOT] NULL，右侧为参数或字面量
+func (im *xmlStatementImporter) parseCondition() (config.SnippetField, bool) {
+	if im.peek().is("(") || im.peek().is("NOT") {
+		im.unsupported("不支持括号分组或 NOT 条件")
+		return config.SnippetField{}, false
+	}
+	col := im.parseColumn()
+	if col == nil {
+		return config.SnippetField{}, false
+	}
+	f := config.SnippetField{ColumnName: col.ColumnName, FieldName: utils.DBStringToCamelCase(col.ColumnName), JdbcType: col.JdbcType, JavaType: col.JavaType}
+
+	switch {
+	case im.accept("IS"):
+		f.Operator = "IS NULL"
+		if im.accept("NOT") {
+			f.Operator = "IS NOT NULL"
+		}
+		return f, im.expect("NULL")
+
+	case im.peek().is("IN") || im.peek().is("NOT") && im.pos+1 < len(im.tokens) && im.tokens[im.pos+1].is("IN"):
+		f.Operator = "IN"
+		if im.accept("NOT") {
+			f.Operator = "NOT IN"
+		}
+		im.pos++
+		open := im.peek()
+		if !im.expect("(") {
+			return f, false
+		}
+		if name, ok := im.paramName(); ok && im.collections[name] {
+			f.FieldName, f.Optional = name, im.optional[name]
+			return f, im.expect(")")
+		}
+		// 固定值列表：(1, 2, 'a')
+		for im.pos < len(im.tokens) && !im.peek().is(")") {
+			if k := im.peek().Kind; k != sqlNumber && k != sqlString && !im.peek().is(",") {
+				im.unsupported("IN 列表仅支持集合参数或字面量")
+				return f, false
+			}
+			im.pos++
+		}
+		closeTok := im.peek()
+		if !im.expect(")") {
+			return f, false
+		}
+		f.IsFixed, f.FixedValue = true, strings.TrimSpace(im.sql[open.End:closeTok.Start])
+		return f, true
+
+	case im.accept("LIKE"):
+		f.Operator = "LIKE"
+		if tok := im.peek(); tok.Kind == sqlString {
+			im.pos++
+			f.IsFixed, f.FixedValue = true, tok.Text[1:len(tok.Text)-1]
+			return f, true
+		}
+		// 生成器的 LIKE 为前后模糊匹配，只接受 CONCAT('%', :p, '%') 或 '%' || :p || '%'
+		name, ok := im.parseContainsPattern()
+		if !ok {
+			im.unsupported("LIKE 仅支持 CONCAT('%%', 参数, '%%') 形式的模糊匹配")
+			return f, false
+		}
+		f.FieldName, f.Optional = name, im.optional[name]
+		return f, true
+	}
+
+	op := im.peek().Text
+	if im.peek().Kind != sqlSymbol || !sqlComparators[op] {
+		im.unsupported("不支持的条件运算: %s", op)
+		return f, false
+	}
+	im.pos++
+	if op == "<>" {
+		op = "!="
+	}
+	f.Operator = op
+	if name, ok := im.paramName(); ok {
+		if im.collections[name] {
+			im.unsupported("集合参数 %s 只能用于 IN", name)
+			return f, false
+		}
+		f.FieldName, f.Optional = name, im.optional[name]
+		return f, true
+	}
+	value, ok := im.fixedLiteral(col)
+	if !ok {
+		return f, false
+	}
+	f.IsFixed, f.FixedValue = true, value
+	return f, true
+}
+
+// parseContainsPattern 解析 CONCAT('%', :p, '%') 或 '%' || :p || '%'，返回参数名
+func (im *xmlStatementImporter) parseContainsPattern() (string, bool) {
+	isPercent := func() bool {
+		if im.peek().Kind == sqlString && im.peek().Text == "'%'" {
+			im.pos++
+			return true
+		}
+		return false
+	}
+	if im.accept("CONCAT") {
+		if !im.accept("(") || !isPercent() || !im.accept(",") {
+			return "", false
+		}
+		name, ok := im.paramName()
+		if !ok || !im.accept(",") || !isPercent() || !im.accept(")") {
+			return "", false
+		}
+		return name, true
+	}
+	if !isPercent() || !im.accept("||") {
+		return "", false
+	}
+	name, ok := im.paramName()
+	if !ok || !im.accept("||") || !isPercent() {
+		return "", false
+	}
+	return name, true
+}
+
+// fixedLiteral 解析固定值字面量；生成器对 VARCHAR/CHAR/TEXT 列的固定值加引号，其他列原样内嵌
+func (im *xmlStatementImporter) fixedLiteral(col *database.TableColumn) (string, bool) {
+	tok := im.peek()
+	quoted := col.JdbcType == "VARCHAR" || col.JdbcType == "CHAR" || col.JdbcType == "TEXT"
+	switch {
+	case tok.Kind == sqlString && quoted:
+		im.pos++
+		return tok.Text[1 : len(tok.Text)-1], true
+	case tok.Kind == sqlNumber && !quoted:
+		im.pos++
+		return tok.Text, true
+	case tok.Kind == sqlString || tok.Kind == sqlNumber:
+		im.unsupported("列 %s 的固定值类型与列类型不符: %s", col.ColumnName, tok.Text)
+	default:
+		im.unsupported("条件右侧仅支持参数或字面量: %s", tok.Text)
+	}
+	return "", false
+}
+
+// parseOrderBy 解析排序列（[alias.]column [ASC|DESC]）
+func (im *xmlStatementImporter) parseOrderBy(cfg *config.SnippetConfig) {
+	for {
+		col := im.parseColumn()
+		if col == nil {
+			return
+		}
+		direction := "ASC"
+		if im.accept("DESC") {
+			direction = "DESC"
+		} else {
+			im.accept("ASC")
+		}
+		cfg.OrderByFields = append(cfg.OrderByFields, config.OrderByField{
+			ColumnName: col.ColumnName,
+			FieldName:  utils.DBStringToCamelCase(col.ColumnName),
+			JdbcType:   col.JdbcType,
+			Direction:  direction,
+		})
+		if !im.accept(",") {
+			return
+		}
+	}
+}
+
+// parseLimit 解析 LIMIT n / LIMIT :p 及 FETCH FIRST n ROWS ONLY，不支持 OFFSET
+func (im *xmlStatementImporter) parseLimit(cfg *config.SnippetConfig) {
+	fetch := false
+	switch {
+	case im.accept("LIMIT"):
+	case im.accept("FETCH"):
+		fetch = true
+		if !im.accept("FIRST") && !im.expect("NEXT") {
+			return
+		}
+	default:
+		return
+	}
+
+	cfg.HasLimit = true
+	if tok := im.peek(); tok.Kind == sqlNumber {
+		im.pos++
+		cfg.IsLimitFixed, cfg.LimitValue = true, tok.Text
+	} else if name, ok := im.paramName(); ok {
+		cfg.LimitValue = name
+	} else {
+		im.unsupported("LIMIT 仅支持数字或参数")
+		return
+	}
+
+	if fetch {
+		if !im.accept("ROWS") && !im.expect("ROW") {
+			return
+		}
+		im.expect("ONLY")
+	}
+	if im.peek().is(",") || im.peek().is("OFFSET") {
+		im.unsupported("不支持 OFFSET 分页")
+	}
+}
+
+func (im *xmlStatementImporter) parseDelete(cfg *config.SnippetConfig) {
+	if !im.expect("DELETE") || !im.expect("FROM") || !im.parseTable() {
+		return
+	}
+	if im.accept("WHERE") {
+		im.parseWhere(cfg)
+	}
+}
+
+// parseUpdate 解析 UPDATE t SET col = :prop, ... WHERE ...，参数需为实体属性（片段方法以实体对象为参数）
+func (im *xmlStatementImporter) parseUpdate(cfg *config.SnippetConfig) {
+	if !im.expect("UPDATE") || !im.parseTable() || !im.expect("SET") {
+		return
+	}
+	for {
+		col := im.parseColumn()
+		if col == nil || !im.expect("=") {
+			return
+		}
+		name, ok := im.paramName()
+		if !ok {
+			im.unsupported("SET 的值仅支持参数: %s", col.ColumnName)
+			return
+		}
+		cfg.SetFields = append(cfg.SetFields, config.SnippetField{ColumnName: col.ColumnName, FieldName: name, JdbcType: col.JdbcType, JavaType: col.JavaType})
+		if !im.accept(",") {
+			break
+		}
+		// <set> 末尾的逗号
+		if im.peek().is("WHERE") || im.pos >= len(im.tokens) {
+			break
+		}
+	}
+	if im.accept("WHERE") {
+		im.parseWhere(cfg)
+	}
+	im.checkModelProperties(cfg.SetFields, cfg.WhereFields)
+}
+
+// parseInsert 解析 INSERT INTO t (cols) VALUES (:prop, ...)，参数需为实体属性
+func (im *xmlStatementImporter) parseInsert(cfg *config.SnippetConfig) {
+	if im.stmt.Attrs["useGeneratedKeys"] == "true" {
+		im.unsupported("不支持 useGeneratedKeys 主键回填")
+		return
+	}
+	if !im.expect("INSERT") || !im.expect("INTO") || !im.parseTable() || !im.expect("(") {
+		return
+	}
+	var cols []*database.TableColumn
+	for {
+		col := im.parseColumn()
+		if col == nil {
+			return
+		}
+		cols = append(cols, col)
+		if !im.accept(",") {
+			break
+		}
+	}
+	if !im.expect(")") || !im.expect("VALUES") || !im.expect("(") {
+		return
+	}
+	for i, col := range cols {
+		if i > 0 && !im.expect(",") {
+			return
+		}
+		name, ok := im.paramName()
+		if !ok {
+			im.unsupported("VALUES 仅支持参数: %s", col.ColumnName)
+			return
+		}
+		cfg.InsertFields = append(cfg.InsertFields, config.SnippetField{ColumnName: col.ColumnName, FieldName: name, JdbcType: col.JdbcType, JavaType: col.JavaType})
+	}
+	im.expect(")")
+	im.checkModelProperties(cfg.InsertFields)
+}
+
+// checkModelProperties 插入/更新片段以实体对象为参数，参数名需为实体属性（列名转驼峰）
+func (im *xmlStatementImporter) checkModelProperties(fieldLists ...[]config.SnippetField) {
+	properties := make(map[string]bool, len(im.columns))
+	for _, col := range im.columns {
+		properties[utils.DBStringToCamelCase(col.ColumnName)] = true
+	}
+	for _, fields := range fieldLists {
+		for _, f := range fields {
+			if !f.IsFixed && f.Operator != "IS NULL" && f.Operator != "IS NOT NULL" && !properties[f.FieldName] {
+				im.unsupported("参数 %s 不是实体属性", f.FieldName)
+			}
+			if f.Optional || isCollectionOperator(f.Operator) && !f.IsFixed {
+				im.unsupported("插入/更新语句不支持可选条件或集合参数")
+			}
+		}
+	}
+}
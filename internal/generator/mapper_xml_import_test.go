@@ -0,0 +1,129 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yourusername/mybatis-generator-gui-go/internal/config"
+	"github.com/yourusername/mybatis-generator-gui-go/internal/database"
+)
+
+func xmlImportColumns() []*database.TableColumn {
+	return append(methodParserColumns(),
+		&database.TableColumn{ColumnName: "user_ids", JavaType: "Long", JdbcType: "BIGINT"},
+		&database.TableColumn{ColumnName: "created_by", JavaType: "String", JdbcType: "VARCHAR"},
+	)
+}
+
+func TestImportMapperXML_GeneratedSnippetRoundTrip(t *testing.T) {
+	cfg := evalSnippetConfig()
+	cfg.WhereFields[2].ColumnName = "user_id"
+	cfg.OrderByFields = []config.OrderByField{{ColumnName: "created_at", FieldName: "createdAt", JdbcType: "TIMESTAMP", Direction: "DESC"}}
+	result, err := GenerateSnippet(cfg, "UserMapper", "com.example.User", "user", NewDialect(nil))
+	assert.NoError(t, err)
+
+	imported, err := ImportMapperXML("<mapper>"+result.XMLCode+"</mapper>", "user", "com.example.User", xmlImportColumns())
+	assert.NoError(t, err)
+	assert.Len(t, imported.Snippets, 1)
+	assert.Equal(t, "countSearch", imported.Raw[0].StatementID)
+	assert.Contains(t, imported.Raw[0].Reason, "导入 search 后自动生成")
+	assert.Contains(t, imported.Raw[0].XML, `<select id="countSearch"`)
+
+	got := imported.Snippets[0].Config
+	assert.Equal(t, "search", got.MethodName)
+	assert.Equal(t, "AND", got.WhereLogic)
+	assert.Len(t, got.WhereFields, 4)
+	assert.Equal(t, config.SnippetField{ColumnName: "user_name", FieldName: "userName", JavaType: "String", JdbcType: "VARCHAR", Operator: "=", Optional: true}, got.WhereFields[1])
+	assert.Equal(t, "IN", got.WhereFields[2].Operator)
+	assert.Equal(t, "userIds", got.WhereFields[2].FieldName)
+	assert.False(t, got.WhereFields[2].Optional)
+	assert.Equal(t, "<", got.WhereFields[3].Operator)
+	assert.Equal(t, "DESC", got.OrderByFields[0].Direction)
+	assert.True(t, got.HasLimit)
+	assert.Equal(t, "limit", got.LimitValue)
+
+	// 重新生成的 SQL 与原片段一致
+	regenerated, err := GenerateSnippet(&got, "UserMapper", "com.example.User", "user", NewDialect(nil))
+	assert.NoError(t, err)
+	params := map[string]interface{}{"status": 1, "userName": "tom", "userIds": []interface{}{1}, "limit": 5}
+	want, _ := EvaluateStatement(result.XMLCode, "search", params)
+	bound, err := EvaluateStatement(regenerated.XMLCode, "search", params)
+	assert.NoError(t, err)
+	assert.Equal(t, want.SQL, bound.SQL)
+}
+
+func TestImportMapperXML_HandWritten(t *testing.T) {
+	xmlText := `<?xml version="1.0" encoding="UTF-8"?>
+<mapper namespace="com.example.mapper.UserMapper">
+    <resultMap id="BaseResultMap" type="com.example.User">
+        <id column="id" property="id"/>
+    </resultMap>
+    <sql id="Base_Column_List">u.id, u.user_name AS name</sql>
+    <select id="findActive" resultMap="BaseResultMap">
+        SELECT <include refid="Base_Column_List"/> FROM user u
+        WHERE u.status = 1 AND u.created_by = 'system' AND u.expire_time IS NULL
+          AND u.user_name LIKE CONCAT('%', #{keyword}, '%')
+        ORDER BY u.sort_order, u.id DESC
+        FETCH FIRST 20 ROWS ONLY
+    </select>
+    <update id="updateStatus" parameterType="com.example.User">
+        UPDATE user SET status = #{status,jdbcType=INTEGER}, WHERE id = #{id}
+    </update>
+    <insert id="insertUser">INSERT INTO user (id, user_name) VALUES (#{id}, #{userName})</insert>
+    <delete id="deleteExpired">DELETE FROM user WHERE expire_time &lt; #{now} OR status IN (3, 4)</delete>
+
+    <select id="countByStatus" resultType="java.lang.Long">SELECT COUNT(*) FROM user WHERE status = #{status}</select>
+    <select id="findByRole" resultMap="RoleResultMap">SELECT * FROM user WHERE status = #{status}</select>
+    <select id="findWithRole" resultMap="BaseResultMap">SELECT u.* FROM user u LEFT JOIN role r ON r.id = u.id</select>
+    <select id="findSorted" resultMap="BaseResultMap">SELECT * FROM user ORDER BY ${sort}</select>
+    <select id="findByName" resultMap="BaseResultMap">SELECT * FROM user WHERE user_name LIKE #{name}</select>
+    <update id="touch">UPDATE user SET status = #{newStatus} WHERE id = #{id}</update>
+    <delete id="deleteByNames">DELETE FROM user WHERE user_name IN <foreach collection="list" item="u" open="(" separator="," close=")">#{u.name}</foreach></delete>
+</mapper>`
+
+	imported, err := ImportMapperXML(xmlText, "user", "com.example.User", xmlImportColumns())
+	assert.NoError(t, err)
+
+	ids := make([]string, 0, len(imported.Snippets))
+	for _, s := range imported.Snippets {
+		ids = append(ids, s.StatementID)
+	}
+	assert.Equal(t, []string{"findActive", "updateStatus", "insertUser", "deleteExpired", "countByStatus"}, ids)
+
+	find := imported.Snippets[0].Config
+	assert.Equal(t, []config.SnippetField{
+		{ColumnName: "id", FieldName: "id", JavaType: "Long", JdbcType: "BIGINT"},
+		{ColumnName: "user_name", FieldName: "userName", JavaType: "String", JdbcType: "VARCHAR", Alias: "name"},
+	}, find.SelectFields)
+	assert.Equal(t, "1", find.WhereFields[0].FixedValue)
+	assert.Equal(t, "system", find.WhereFields[1].FixedValue)
+	assert.Equal(t, "IS NULL", find.WhereFields[2].Operator)
+	assert.Equal(t, "keyword", find.WhereFields[3].FieldName)
+	assert.Equal(t, "ASC", find.OrderByFields[0].Direction)
+	assert.True(t, find.IsLimitFixed)
+	assert.Equal(t, "20", find.LimitValue)
+
+	assert.Equal(t, "status", imported.Snippets[1].Config.SetFields[0].FieldName)
+	assert.Equal(t, "userName", imported.Snippets[2].Config.InsertFields[1].FieldName)
+	del := imported.Snippets[3].Config
+	assert.Equal(t, "OR", del.WhereLogic)
+	assert.Equal(t, config.SnippetField{ColumnName: "status", FieldName: "status", JavaType: "Integer", JdbcType: "INTEGER", Operator: "IN", IsFixed: true, FixedValue: "3, 4"}, del.WhereFields[1])
+
+	count := imported.Snippets[4].Config
+	assert.Equal(t, config.ReturnShapeCount, count.ReturnShape)
+	assert.Empty(t, count.SelectFields)
+	assert.Equal(t, "status", count.WhereFields[0].FieldName)
+
+	reasons := make(map[string]string)
+	for _, raw := range imported.Raw {
+		reasons[raw.StatementID] = raw.Reason
+	}
+	assert.Len(t, reasons, 6)
+	assert.Contains(t, reasons["findByRole"], "resultMap RoleResultMap 不存在")
+	assert.Contains(t, reasons["findWithRole"], "不支持关联查询")
+	assert.Contains(t, reasons["findSorted"], "不支持 ${} 文本替换")
+	assert.Contains(t, reasons["findByName"], "LIKE 仅支持")
+	assert.Contains(t, reasons["touch"], "参数 newStatus 不是实体属性")
+	assert.Contains(t, reasons["deleteByNames"], "仅支持 IN (...) 集合参数")
+	assert.Equal(t, `<update id="touch">UPDATE user SET status = #{newStatus} WHERE id = #{id}</update>`, imported.Raw[4].XML)
+}
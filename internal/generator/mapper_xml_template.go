@@ -24,18 +24,25 @@ const mapperXMLTemplate = `<?xml version="1.0" encoding="UTF-8"?>
     </select>
 {{end}}
     <!-- 插入 -->
-    <insert id="insert" parameterType="{{.ModelType}}"{{if .UseGeneratedKeys}} useGeneratedKeys="true" keyProperty="{{.GenerateKeys}}"{{end}}>
-        INSERT INTO {{.TableName}} (
+    <insert id="insert" parameterType="{{.ModelType}}"{{if .UseGeneratedKeys}} useGeneratedKeys="true" keyProperty="{{.GenerateKeys}}"{{if .KeyColumn}} keyColumn="{{.KeyColumn}}"{{end}}{{end}}>
+{{if .SequenceKey}}        <selectKey keyProperty="{{.SequenceKey.KeyProperty}}" resultType="{{.SequenceKey.ResultType}}" order="BEFORE">
+            {{.SequenceKey.SelectKeySQL}}
+        </selectKey>
+{{end}}        INSERT INTO {{.TableName}} (
             {{range $index, $col := .InsertColumns}}{{if $index}}, {{end}}{{$col.ColumnName}}{{end}}
         )
         VALUES (
             {{range $index, $col := .InsertColumns}}{{if $index}}, {{end}}#{{"{"}}{{$col.FieldName}},jdbcType={{$col.JdbcType}}{{"}"}}{{end}}
-        )
+        ){{if .ReturningColumn}}
+        RETURNING {{.ReturningColumn}}{{end}}
     </insert>
 
     <!-- 选择性插入 -->
-    <insert id="insertSelective" parameterType="{{.ModelType}}"{{if .UseGeneratedKeys}} useGeneratedKeys="true" keyProperty="{{.GenerateKeys}}"{{end}}>
-        INSERT INTO {{.TableName}}
+    <insert id="insertSelective" parameterType="{{.ModelType}}"{{if .UseGeneratedKeys}} useGeneratedKeys="true" keyProperty="{{.GenerateKeys}}"{{if .KeyColumn}} keyColumn="{{.KeyColumn}}"{{end}}{{end}}>
+{{if .SequenceKey}}        <selectKey keyProperty="{{.SequenceKey.KeyProperty}}" resultType="{{.SequenceKey.ResultType}}" order="BEFORE">
+            {{.SequenceKey.SelectKeySQL}}
+        </selectKey>
+{{end}}        INSERT INTO {{.TableName}}
         <trim prefix="(" suffix=")" suffixOverrides=",">
 {{range .InsertColumns}}            <if test="{{.FieldName}} != null">
                 {{.ColumnName}},
@@ -45,7 +52,8 @@ const mapperXMLTemplate = `<?xml version="1.0" encoding="UTF-8"?>
 {{range .InsertColumns}}            <if test="{{.FieldName}} != null">
                 #{{"{"}}{{.FieldName}},jdbcType={{.JdbcType}}{{"}"}},
             </if>
-{{end}}        </trim>
+{{end}}        </trim>{{if .ReturningColumn}}
+        RETURNING {{.ReturningColumn}}{{end}}
     </insert>
 {{if .PrimaryKey}}
     <!-- 根据主键更新 -->
@@ -75,23 +83,30 @@ const mapperXMLTemplate = `<?xml version="1.0" encoding="UTF-8"?>
 {{end}}{{if .OffsetLimit}}
     <!-- 分页查询 -->
     <select id="selectByPage" resultMap="BaseResultMap">
-        SELECT <include refid="Base_Column_List" />
+{{if .PagePrefix}}        {{.PagePrefix}}
+{{end}}        SELECT <include refid="Base_Column_List" />
         FROM {{.TableName}}{{if .UseTableNameAlias}} t{{end}}
-        LIMIT #{offset}, #{limit}
+        {{.PageSuffix}}
+    </select>
+
+    <!-- 查询总数（配合分页查询） -->
+    <select id="countAll" resultType="java.lang.Long">
+        SELECT COUNT(*) FROM {{.TableName}}
     </select>
 {{end}}
 {{if .UseBatchInsert}}
     <!-- 批量插入 -->
-    <insert id="insertBatch" parameterType="java.util.List"{{if .UseGeneratedKeys}} useGeneratedKeys="true" keyProperty="{{.GenerateKeys}}"{{end}}>
+    <insert id="insertBatch" parameterType="java.util.List"{{if .UseGeneratedKeys}} useGeneratedKeys="true" keyProperty="{{.GenerateKeys}}"{{if .KeyColumn}} keyColumn="{{.KeyColumn}}"{{end}}{{end}}>
         INSERT INTO {{.TableName}} (
             {{range $index, $col := .InsertColumns}}{{if $index}}, {{end}}{{$col.ColumnName}}{{end}}
         )
         VALUES
         <foreach collection="list" item="item" separator=",">
-            ({{range $index, $col := .InsertColumns}}{{if $index}}, {{end}}#{{"{"}}item.{{$col.FieldName}},jdbcType={{$col.JdbcType}}{{"}"}}{{end}})
-        </foreach>
+            ({{range $index, $col := .InsertColumns}}{{if $index}}, {{end}}{{if and $.SequenceKey (eq $col.ColumnName $.SequenceKey.KeyColumn)}}{{$.SequenceKey.NextValExpr}}{{else}}#{{"{"}}item.{{$col.FieldName}},jdbcType={{$col.JdbcType}}{{"}"}}{{end}}{{end}})
+        </foreach>{{if .ReturningColumn}}
+        RETURNING {{.ReturningColumn}}{{end}}
     </insert>
-{{end}}
+{{end}}{{if .Upsert}}{{.UpsertXML}}{{end}}
 
 {{if and .UseBatchUpdate .PrimaryKey}}
     <!-- 批量更新 -->
@@ -103,6 +118,29 @@ const mapperXMLTemplate = `<?xml version="1.0" encoding="UTF-8"?>
             WHERE {{.PrimaryKey.ColumnName}} = #{{"{"}}item.{{.PrimaryKey.FieldName}},jdbcType={{.PrimaryKey.JdbcType}}{{"}"}}
         </foreach>
     </update>
-{{end}}
+{{end}}{{range .IndexFinders}}
+    <!-- 根据{{if .Unique}}唯一{{end}}索引 {{.IndexName}} 查询 -->
+    <select id="{{.MethodName}}" resultMap="BaseResultMap">
+        SELECT <include refid="Base_Column_List" />
+        FROM {{$.TableName}}{{if $.UseTableNameAlias}} t{{end}}
+        WHERE {{range $index, $col := .Columns}}{{if $index}}
+          AND {{end}}{{if $.UseTableNameAlias}}t.{{end}}{{$col.ColumnName}} = #{{"{"}}{{$col.FieldName}},jdbcType={{$col.JdbcType}}{{"}"}}{{end}}
+    </select>
+{{end}}{{if .PrimaryKey}}{{range .Relations}}
+    <!-- 关联结果映射 - {{.Property}} -->
+    <resultMap id="{{.ResultMapID}}" type="{{$.ModelType}}" extends="BaseResultMap">
+{{if .IsCollection}}        <collection property="{{.Property}}" ofType="{{.ModelType}}" columnPrefix="{{.Alias}}_" resultMap="{{.ResultMap}}" />
+{{else}}        <association property="{{.Property}}" javaType="{{.ModelType}}" columnPrefix="{{.Alias}}_" resultMap="{{.ResultMap}}" />
+{{end}}    </resultMap>
+
+    <!-- 关联查询 - {{.MethodName}} -->
+    <select id="{{.MethodName}}" parameterType="{{$.PrimaryKey.JavaType}}" resultMap="{{.ResultMapID}}">
+        SELECT {{range $index, $col := $.Columns}}{{if $index}}, {{end}}t.{{$col.ColumnName}}{{end}},
+            {{$alias := .Alias}}{{range $index, $col := .Columns}}{{if $index}}, {{end}}{{$alias}}.{{$col.ColumnName}} AS {{$alias}}_{{$col.ColumnName}}{{end}}
+        FROM {{$.TableName}} t
+        LEFT JOIN {{.TableName}} {{.Alias}} ON t.{{.LocalColumn}} = {{.Alias}}.{{.ForeignColumn}}
+        WHERE t.{{$.PrimaryKey.ColumnName}} = #{{"{"}}{{$.PrimaryKey.FieldName}},jdbcType={{$.PrimaryKey.JdbcType}}{{"}"}}
+    </select>
+{{end}}{{end}}
 </mapper>
 `
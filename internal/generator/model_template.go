@@ -11,13 +11,18 @@ import java.io.Serializable;
 {{if .TableComment}}/**
  * {{.TableComment}}
  */
+{{end}}{{range .ClassAnnotations}}{{.}}
 {{end}}public class {{.ClassName}} implements Serializable {
     private static final long serialVersionUID = 1L;
 {{range .Fields}}
 {{if .Comment}}    /** {{.Comment}} */
+{{end}}{{range .Annotations}}    {{.}}
 {{end}}{{if $.UseJsonProperty}}{{if $.JsonPropertyUpperCase}}    @JsonProperty("{{title .ColumnName}}")
 {{else}}    @JsonProperty("{{.ColumnName}}")
 {{end}}{{end}}    private {{.FieldType}} {{.FieldName}};
+{{end}}{{range .RelationFields}}
+    /** {{.Comment}} */
+    private {{.FieldType}} {{.FieldName}};
 {{end}}
 {{if .NeedConstructors}}    public {{.ClassName}}() {}
 
@@ -31,6 +36,14 @@ import java.io.Serializable;
         return {{.FieldName}};
     }
 
+    public void set{{title .FieldName}}({{.FieldType}} {{.FieldName}}) {
+        this.{{.FieldName}} = {{.FieldName}};
+    }
+{{end}}{{range .RelationFields}}
+    public {{.FieldType}} get{{title .FieldName}}() {
+        return {{.FieldName}};
+    }
+
     public void set{{title .FieldName}}({{.FieldType}} {{.FieldName}}) {
         this.{{.FieldName}} = {{.FieldName}};
     }
@@ -64,7 +77,8 @@ const modelLombokTemplate = `package {{.Package}};
 
 import lombok.Data;
 import lombok.EqualsAndHashCode;
-{{if .NeedConstructors}}import lombok.NoArgsConstructor;
+{{if .RelationFields}}import lombok.ToString;
+{{end}}{{if .NeedConstructors}}import lombok.NoArgsConstructor;
 import lombok.AllArgsConstructor;
 {{end}}import java.io.Serializable;
 {{range .Imports}}import {{.}};
@@ -74,6 +88,7 @@ import lombok.AllArgsConstructor;
 {{if .TableComment}}/**
  * {{.TableComment}}
  */
+{{end}}{{range .ClassAnnotations}}{{.}}
 {{end}}@Data
 @EqualsAndHashCode(callSuper = false)
 {{if .NeedConstructors}}@NoArgsConstructor
@@ -82,8 +97,14 @@ import lombok.AllArgsConstructor;
     private static final long serialVersionUID = 1L;
 {{range .Fields}}
 {{if .Comment}}    /** {{.Comment}} */
+{{end}}{{range .Annotations}}    {{.}}
 {{end}}{{if $.UseJsonProperty}}{{if $.JsonPropertyUpperCase}}    @JsonProperty("{{title .ColumnName}}")
 {{else}}    @JsonProperty("{{.ColumnName}}")
 {{end}}{{end}}    private {{.FieldType}} {{.FieldName}};
+{{end}}{{range .RelationFields}}
+    /** {{.Comment}} */
+    @ToString.Exclude
+    @EqualsAndHashCode.Exclude
+    private {{.FieldType}} {{.FieldName}};
 {{end}}}
 `
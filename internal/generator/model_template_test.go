@@ -98,3 +98,28 @@ func TestModelTemplate_Lombok(t *testing.T) {
 	assert.Contains(t, output, "@NoArgsConstructor", "Should contain @NoArgsConstructor")
 	assert.Contains(t, output, "@AllArgsConstructor", "Should contain @AllArgsConstructor")
 }
+
+func TestModelTemplate_FieldAnnotations(t *testing.T) {
+	data := &ModelData{
+		Package:   "com.example",
+		ClassName: "User",
+		Fields: []*ModelField{
+			{FieldName: "name", FieldType: "String", ColumnName: "name", Annotations: []string{"@NotNull", "@Size(max = 64)"}},
+		},
+	}
+
+	for _, tmplStr := range []string{modelTemplate, modelLombokTemplate} {
+		tmpl, err := template.New("model").Funcs(TemplateFuncs).Parse(tmplStr)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, data); err != nil {
+			t.Fatal(err)
+		}
+
+		output := buf.String()
+		assert.Contains(t, output, "    @NotNull\n    @Size(max = 64)\n    private String name;", "Annotations should precede the field")
+	}
+}
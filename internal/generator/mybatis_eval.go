@@ -0,0 +1,746 @@
+package generator
+
+import (
+	"encoding/xml"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// BoundParam 动态SQL求值后按占位符顺序绑定的参数
+type BoundParam struct {
+	Name     string      `json:"name"`               // 参数路径（foreach 元素为 list[0].userId 形式）
+	Value    interface{} `json:"value"`              // 参数值（未提供时为 nil）
+	JdbcType string      `json:"jdbcType,omitempty"` // #{} 中声明的 jdbcType
+}
+
+// BoundSQL 动态SQL求值结果
+type BoundSQL struct {
+	StatementID string       `json:"statementId"` // 语句ID
+	SQL         string       `json:"sql"`         // 最终SQL（参数以 ? 占位）
+	Params      []BoundParam `json:"params"`      // 按 ? 顺序排列的绑定参数
+}
+
+// mapperNode Mapper XML 节点（Name 为空表示文本节点）
+type mapperNode struct {
+	Name     string
+	Attrs    map[string]string
+	Text     string
+	Children []*mapperNode
+}
+
+var mapperRootPattern = regexp.MustCompile(`<mapper[\s>]`)
+
+// parseMapperXML 解析 Mapper XML，返回 <mapper> 根节点
+// 片段 XML（若干语句、无 <mapper> 根元素）会自动包裹后解析
+func parseMapperXML(xmlText string) (*mapperNode, error) {
+	if !mapperRootPattern.MatchString(xmlText) {
+		xmlText = "<mapper>" + xmlText + "</mapper>"
+	}
+
+	decoder := xml.NewDecoder(strings.NewReader(xmlText))
+	var stack []*mapperNode
+	var root *mapperNode
+	for {
+		tok, err := decoder.Token()
+		if err != nil {
+			if root != nil && len(stack) == 0 {
+				return root, nil
+			}
+			return nil, fmt.Errorf("解析XML失败: %v", err)
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			node := &mapperNode{Name: t.Name.Local, Attrs: make(map[string]string)}
+			for _, attr := range t.Attr {
+				node.Attrs[attr.Name.Local] = attr.Value
+			}
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.Children = append(parent.Children, node)
+			} else if root == nil {
+				root = node
+			}
+			stack = append(stack, node)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.Children = append(parent.Children, &mapperNode{Text: string(t)})
+			}
+		}
+	}
+}
+
+// findStatement 按 id 查找 select/insert/update/delete 语句，id 为空时返回第一条语句
+func (n *mapperNode) findStatement(id string) *mapperNode {
+	for _, child := range n.Children {
+		switch child.Name {
+		case "select", "insert", "update", "delete":
+			if id == "" || child.Attrs["id"] == id {
+				return child
+			}
+		}
+	}
+	return nil
+}
+
+// findSQLFragment 按 refid 查找 <sql> 片段（refid 可带命名空间前缀）
+func (n *mapperNode) findSQLFragment(refID string) *mapperNode {
+	for _, id := range []string{refID, refID[strings.LastIndex(refID, ".")+1:]} {
+		for _, child := range n.Children {
+			if child.Name == "sql" && child.Attrs["id"] == id {
+				return child
+			}
+		}
+	}
+	return nil
+}
+
+// findResultMap 按 id 查找 <resultMap>
+func (n *mapperNode) findResultMap(id string) *mapperNode {
+	for _, child := range n.Children {
+		if child.Name == "resultMap" && child.Attrs["id"] == id {
+			return child
+		}
+	}
+	return nil
+}
+
+// EvaluateStatement 对 Mapper XML 中指定语句按参数求值，得到最终SQL和绑定参数
+// 支持 <if>、<choose>/<when>/<otherwise>、<where>、<set>、<trim>、<foreach>、<include>、#{} 与 ${}；
+// statementID 为空时取第一条语句，<selectKey> 单独执行，不计入语句SQL
+func EvaluateStatement(xmlText, statementID string, params map[string]interface{}) (*BoundSQL, error) {
+	root, err := parseMapperXML(xmlText)
+	if err != nil {
+		return nil, err
+	}
+	stmt := root.findStatement(statementID)
+	if stmt == nil {
+		if statementID == "" {
+			return nil, fmt.Errorf("XML中没有 select/insert/update/delete 语句")
+		}
+		return nil, fmt.Errorf("语句不存在: %s", statementID)
+	}
+	statementID = stmt.Attrs["id"]
+
+	ctx := &evalContext{root: root, including: make(map[string]bool)}
+	sqlText, err := ctx.evalChildren(stmt, &evalScope{root: params})
+	if err != nil {
+		return nil, fmt.Errorf("语句 %s 求值失败: %v", statementID, err)
+	}
+	return &BoundSQL{StatementID: statementID, SQL: compactSQL(sqlText), Params: ctx.params}, nil
+}
+
+// evalContext 求值过程中的 Mapper 根节点、<include> 属性及收集的绑定参数
+type evalContext struct {
+	root       *mapperNode
+	properties []map[string]string // <include> 的 <property>，内层优先
+	including  map[string]bool     // 正在展开的 <sql> 片段，用于检测循环引用
+	params     []BoundParam
+}
+
+// scopeVar foreach 绑定的变量（值及其在参数中的路径）
+type scopeVar struct {
+	value interface{}
+	path  string
+}
+
+// evalScope 变量作用域（foreach 每次迭代新建一层）
+type evalScope struct {
+	root   map[string]interface{}
+	vars   map[string]scopeVar
+	parent *evalScope
+}
+
+// lookup 解析 a.b.c 形式的属性路径，返回值与参数路径
+func (s *evalScope) lookup(path string) (interface{}, string) {
+	parts := strings.Split(path, ".")
+	var value interface{}
+	resolved := parts[0]
+	found := false
+	for scope := s; scope != nil && !found; scope = scope.parent {
+		if v, ok := scope.vars[parts[0]]; ok {
+			value, resolved, found = v.value, v.path, true
+		}
+	}
+	if !found {
+		root := s
+		for root.parent != nil {
+			root = root.parent
+		}
+		value = root.root[parts[0]]
+	}
+	for _, part := range parts[1:] {
+		value = propertyOf(value, part)
+		resolved += "." + part
+	}
+	return value, resolved
+}
+
+// propertyOf 读取 map 参数的属性，不存在时返回 nil
+func propertyOf(value interface{}, name string) interface{} {
+	if m, ok := value.(map[string]interface{}); ok {
+		return m[name]
+	}
+	return nil
+}
+
+func (ctx *evalContext) evalChildren(n *mapperNode, scope *evalScope) (string, error) {
+	var b strings.Builder
+	for _, child := range n.Children {
+		s, err := ctx.evalNode(child, scope)
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(s)
+	}
+	return b.String(), nil
+}
+
+func (ctx *evalContext) evalNode(n *mapperNode, scope *evalScope) (string, error) {
+	switch n.Name {
+	case "":
+		return ctx.bindText(n.Text, scope), nil
+	case "if":
+		ok, err := evalTest(n.Attrs["test"], scope)
+		if err != nil || !ok {
+			return "", err
+		}
+		return ctx.evalChildren(n, scope)
+	case "choose":
+		for _, child := range n.Children {
+			switch child.Name {
+			case "when":
+				ok, err := evalTest(child.Attrs["test"], scope)
+				if err != nil {
+					return "", err
+				}
+				if ok {
+					return ctx.evalChildren(child, scope)
+				}
+			case "otherwise":
+				return ctx.evalChildren(child, scope)
+			}
+		}
+		return "", nil
+	case "where":
+		body, err := ctx.evalChildren(n, scope)
+		if err != nil {
+			return "", err
+		}
+		return applyTrim(body, "WHERE", "", []string{"AND ", "OR "}, nil), nil
+	case "set":
+		body, err := ctx.evalChildren(n, scope)
+		if err != nil {
+			return "", err
+		}
+		return applyTrim(body, "SET", "", nil, []string{","}), nil
+	case "trim":
+		body, err := ctx.evalChildren(n, scope)
+		if err != nil {
+			return "", err
+		}
+		return applyTrim(body, n.Attrs["prefix"], n.Attrs["suffix"],
+			splitOverrides(n.Attrs["prefixOverrides"]), splitOverrides(n.Attrs["suffixOverrides"])), nil
+	case "foreach":
+		return ctx.evalForeach(n, scope)
+	case "include":
+		return ctx.evalInclude(n, scope)
+	case "selectKey":
+		return "", nil
+	default:
+		return "", fmt.Errorf("不支持的标签 <%s>", n.Name)
+	}
+}
+
+// evalForeach 展开 <foreach>，空集合不输出任何内容（含 open/close）
+func (ctx *evalContext) evalForeach(n *mapperNode, scope *evalScope) (string, error) {
+	collection := n.Attrs["collection"]
+	value, path := scope.lookup(collection)
+	if value == nil {
+		return "", fmt.Errorf("foreach 集合参数为空: %s", collection)
+	}
+	rv := reflect.ValueOf(value)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return "", fmt.Errorf("foreach 参数不是集合: %s", collection)
+	}
+	if rv.Len() == 0 {
+		return "", nil
+	}
+
+	parts := make([]string, 0, rv.Len())
+	for i := 0; i < rv.Len(); i++ {
+		vars := make(map[string]scopeVar)
+		if item := n.Attrs["item"]; item != "" {
+			vars[item] = scopeVar{value: rv.Index(i).Interface(), path: fmt.Sprintf("%s[%d]", path, i)}
+		}
+		if index := n.Attrs["index"]; index != "" {
+			vars[index] = scopeVar{value: i, path: index}
+		}
+		body, err := ctx.evalChildren(n, &evalScope{vars: vars, parent: scope})
+		if err != nil {
+			return "", err
+		}
+		parts = append(parts, body)
+	}
+	return n.Attrs["open"] + strings.Join(parts, n.Attrs["separator"]) + n.Attrs["close"], nil
+}
+
+// evalInclude 展开 <include>，<property> 定义的值用于替换片段中的 ${}
+func (ctx *evalContext) evalInclude(n *mapperNode, scope *evalScope) (string, error) {
+	refID := ctx.substitute(n.Attrs["refid"], scope)
+	fragment := ctx.root.findSQLFragment(refID)
+	if fragment == nil {
+		return "", fmt.Errorf("<include> 引用的 <sql> 片段不存在: %s", refID)
+	}
+	if ctx.including[refID] {
+		return "", fmt.Errorf("<sql> 片段存在循环引用: %s", refID)
+	}
+
+	props := make(map[string]string)
+	for _, child := range n.Children {
+		if child.Name == "property" {
+			props[child.Attrs["name"]] = ctx.substitute(child.Attrs["value"], scope)
+		}
+	}
+	ctx.including[refID] = true
+	ctx.properties = append(ctx.properties, props)
+	defer func() {
+		delete(ctx.including, refID)
+		ctx.properties = ctx.properties[:len(ctx.properties)-1]
+	}()
+	return ctx.evalChildren(fragment, scope)
+}
+
+var (
+	bindParamPattern = regexp.MustCompile(`#\{([^}]*)\}`)
+	textParamPattern = regexp.MustCompile(`\$\{([^}]*)\}`)
+)
+
+// substitute 替换 ${}：优先取 <include> 的 <property>，否则取参数值直接拼接（null 为空串）
+func (ctx *evalContext) substitute(text string, scope *evalScope) string {
+	return textParamPattern.ReplaceAllStringFunc(text, func(m string) string {
+		name := strings.TrimSpace(m[2 : len(m)-1])
+		for i := len(ctx.properties) - 1; i >= 0; i-- {
+			if v, ok := ctx.properties[i][name]; ok {
+				return v
+			}
+		}
+		value, _ := scope.lookup(name)
+		if value == nil {
+			return ""
+		}
+		if f, ok := toNumber(value); ok {
+			return strconv.FormatFloat(f, 'f', -1, 64)
+		}
+		return fmt.Sprint(value)
+	})
+}
+
+// bindText 替换文本中的 ${}，再将 #{} 替换为 ? 并记录绑定参数
+func (ctx *evalContext) bindText(text string, scope *evalScope) string {
+	text = ctx.substitute(text, scope)
+	return bindParamPattern.ReplaceAllStringFunc(text, func(m string) string {
+		parts := strings.Split(m[2:len(m)-1], ",")
+		param := BoundParam{}
+		param.Value, param.Name = scope.lookup(strings.TrimSpace(parts[0]))
+		for _, opt := range parts[1:] {
+			if k, v, ok := strings.Cut(opt, "="); ok && strings.TrimSpace(k) == "jdbcType" {
+				param.JdbcType = strings.TrimSpace(v)
+			}
+		}
+		ctx.params = append(ctx.params, param)
+		return "?"
+	})
+}
+
+// splitOverrides 拆分 prefixOverrides/suffixOverrides（以 | 分隔）
+func splitOverrides(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, "|")
+}
+
+// applyTrim 按 MyBatis <trim> 规则处理内容：去除首尾多余的关键字/符号，非空时添加前后缀
+func applyTrim(body, prefix, suffix string, prefixOverrides, suffixOverrides []string) string {
+	trimmed := compactSQL(body)
+	if trimmed == "" {
+		return ""
+	}
+	upper := strings.ToUpper(trimmed)
+	for _, p := range prefixOverrides {
+		if strings.HasPrefix(upper, strings.ToUpper(p)) {
+			trimmed = strings.TrimSpace(trimmed[len(p):])
+			break
+		}
+	}
+	upper = strings.ToUpper(trimmed)
+	for _, s := range suffixOverrides {
+		if strings.HasSuffix(upper, strings.ToUpper(s)) {
+			trimmed = strings.TrimSpace(trimmed[:len(trimmed)-len(s)])
+			break
+		}
+	}
+	if prefix != "" {
+		trimmed = prefix + " " + trimmed
+	}
+	if suffix != "" {
+		trimmed += " " + suffix
+	}
+	return " " + trimmed + " "
+}
+
+// compactSQL 合并连续空白为单个空格（单引号字符串内保持不变）
+func compactSQL(s string) string {
+	var b strings.Builder
+	inQuote, pendingSpace := false, false
+	for _, r := range s {
+		if inQuote {
+			b.WriteRune(r)
+			if r == '\'' {
+				inQuote = false
+			}
+			continue
+		}
+		if unicode.IsSpace(r) {
+			pendingSpace = true
+			continue
+		}
+		if pendingSpace && b.Len() > 0 {
+			b.WriteByte(' ')
+		}
+		pendingSpace = false
+		b.WriteRune(r)
+		if r == '\'' {
+			inQuote = true
+		}
+	}
+	return b.String()
+}
+
+// -----------------------------------------------------------------------
+// test 表达式（OGNL 子集）：== != > >= < <=、and/or/not（&& || !）、括号、
+// null/true/false、数字与字符串字面量、属性路径及 size()/length()/isEmpty()
+// -----------------------------------------------------------------------
+
+// evalTest 计算 test 表达式，结果按 MyBatis 规则转为布尔值（数字非0、其他非null为真）
+func evalTest(expr string, scope *evalScope) (bool, error) {
+	p := &testParser{tokens: tokenizeTest(expr), scope: scope}
+	value, err := p.parseOr()
+	if err != nil {
+		return false, fmt.Errorf("test 表达式 %q 无效: %v", expr, err)
+	}
+	if p.pos < len(p.tokens) {
+		return false, fmt.Errorf("test 表达式 %q 无效: 多余的 %s", expr, p.tokens[p.pos])
+	}
+	return truthy(value), nil
+}
+
+func truthy(v interface{}) bool {
+	switch val := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return val
+	}
+	if f, ok := toNumber(v); ok {
+		return f != 0
+	}
+	return true
+}
+
+// tokenizeTest 将 test 表达式切分为记号（字符串字面量保留引号）
+func tokenizeTest(expr string) []string {
+	var tokens []string
+	runes := []rune(expr)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '\'' || r == '"':
+			j := i + 1
+			for j < len(runes) && runes[j] != r {
+				j++
+			}
+			tokens = append(tokens, string(runes[i:min(j+1, len(runes))]))
+			i = j + 1
+		case strings.ContainsRune("=!<>&|", r):
+			j := i + 1
+			if j < len(runes) && strings.ContainsRune("=&|", runes[j]) {
+				j++
+			}
+			tokens = append(tokens, string(runes[i:j]))
+			i = j
+		case r == '(' || r == ')':
+			tokens = append(tokens, string(r))
+			i++
+		default:
+			j := i
+			for j < len(runes) && (unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j]) || runes[j] == '_' || runes[j] == '.' || runes[j] == '$') {
+				j++
+			}
+			if j == i {
+				j++
+			}
+			tokens = append(tokens, string(runes[i:j]))
+			i = j
+		}
+	}
+	return tokens
+}
+
+type testParser struct {
+	tokens []string
+	pos    int
+	scope  *evalScope
+}
+
+func (p *testParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *testParser) next() string {
+	tok := p.peek()
+	p.pos++
+	return tok
+}
+
+func (p *testParser) parseOr() (interface{}, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek() == "or" || p.peek() == "||" {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = truthy(left) || truthy(right)
+	}
+	return left, nil
+}
+
+func (p *testParser) parseAnd() (interface{}, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek() == "and" || p.peek() == "&&" {
+		p.next()
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = truthy(left) && truthy(right)
+	}
+	return left, nil
+}
+
+func (p *testParser) parseNot() (interface{}, error) {
+	if p.peek() == "!" || p.peek() == "not" {
+		p.next()
+		v, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return !truthy(v), nil
+	}
+	return p.parseCompare()
+}
+
+var testCompareOps = map[string]string{
+	"==": "==", "!=": "!=", ">": ">", ">=": ">=", "<": "<", "<=": "<=",
+	"eq": "==", "neq": "!=", "gt": ">", "gte": ">=", "lt": "<", "lte": "<=",
+}
+
+func (p *testParser) parseCompare() (interface{}, error) {
+	left, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	op, ok := testCompareOps[p.peek()]
+	if !ok {
+		return left, nil
+	}
+	p.next()
+	right, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	return compareValues(left, right, op), nil
+}
+
+func (p *testParser) parsePrimary() (interface{}, error) {
+	tok := p.next()
+	switch {
+	case tok == "":
+		return nil, fmt.Errorf("表达式不完整")
+	case tok == "(":
+		v, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.next() != ")" {
+			return nil, fmt.Errorf("缺少右括号")
+		}
+		return v, nil
+	case tok == "null":
+		return nil, nil
+	case tok == "true" || tok == "false":
+		return tok == "true", nil
+	case tok[0] == '\'' || tok[0] == '"':
+		if len(tok) < 2 || tok[len(tok)-1] != tok[0] {
+			return nil, fmt.Errorf("字符串未结束: %s", tok)
+		}
+		return tok[1 : len(tok)-1], nil
+	case unicode.IsDigit(rune(tok[0])):
+		f, err := strconv.ParseFloat(tok, 64)
+		if err != nil {
+			return nil, fmt.Errorf("无效的数字: %s", tok)
+		}
+		return f, nil
+	case unicode.IsLetter(rune(tok[0])) || tok[0] == '_':
+		return p.parsePath(tok)
+	default:
+		return nil, fmt.Errorf("无法识别的 %s", tok)
+	}
+}
+
+// parsePath 解析属性路径，末段后跟 () 时视为方法调用
+func (p *testParser) parsePath(tok string) (interface{}, error) {
+	if p.peek() != "(" {
+		v, _ := p.scope.lookup(tok)
+		return v, nil
+	}
+	dot := strings.LastIndex(tok, ".")
+	if dot < 0 {
+		return nil, fmt.Errorf("不支持的函数调用: %s()", tok)
+	}
+	p.next()
+	if p.next() != ")" {
+		return nil, fmt.Errorf("不支持带参数的方法调用: %s", tok)
+	}
+	target, _ := p.scope.lookup(tok[:dot])
+	method := tok[dot+1:]
+	switch method {
+	case "size", "length":
+		if target == nil {
+			return nil, nil
+		}
+		return float64(lengthOf(target)), nil
+	case "isEmpty":
+		if target == nil {
+			return nil, nil
+		}
+		return lengthOf(target) == 0, nil
+	default:
+		return nil, fmt.Errorf("不支持的方法调用: %s()", method)
+	}
+}
+
+func lengthOf(v interface{}) int {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map, reflect.String:
+		return rv.Len()
+	}
+	return 0
+}
+
+// toNumber 将数值类型转换为 float64
+func toNumber(v interface{}) (float64, bool) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	}
+	return 0, false
+}
+
+// compareValues 比较两个值；与 OGNL 一致，数字与字符串比较时字符串按数字转换（空串为0）
+func compareValues(left, right interface{}, op string) bool {
+	if left == nil || right == nil {
+		switch op {
+		case "==":
+			return left == nil && right == nil
+		case "!=":
+			return left != nil || right != nil
+		}
+		return false
+	}
+
+	var cmp int
+	ln, lok := toNumber(left)
+	rn, rok := toNumber(right)
+	ls, lstr := left.(string)
+	rs, rstr := right.(string)
+	switch {
+	case lok && rstr:
+		rn, rok = stringNumber(rs)
+	case rok && lstr:
+		ln, lok = stringNumber(ls)
+	}
+	switch {
+	case lok && rok:
+		cmp = compareFloat(ln, rn)
+	case lstr && rstr:
+		cmp = strings.Compare(ls, rs)
+	default:
+		eq := reflect.DeepEqual(left, right)
+		switch op {
+		case "==":
+			return eq
+		case "!=":
+			return !eq
+		}
+		return false
+	}
+
+	switch op {
+	case "==":
+		return cmp == 0
+	case "!=":
+		return cmp != 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	case "<":
+		return cmp < 0
+	default:
+		return cmp <= 0
+	}
+}
+
+func stringNumber(s string) (float64, bool) {
+	if strings.TrimSpace(s) == "" {
+		return 0, true
+	}
+	f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	return f, err == nil
+}
+
+func compareFloat(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,257 @@
+package generator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yourusername/mybatis-generator-gui-go/internal/config"
+)
+
+func evalSnippetConfig() *config.SnippetConfig {
+	return &config.SnippetConfig{
+		MethodName: "search",
+		Operation:  config.OperationSelect,
+		WhereFields: []config.SnippetField{
+			{ColumnName: "status", FieldName: "status", JavaType: "Integer", JdbcType: "INTEGER", Operator: "="},
+			{ColumnName: "user_name", FieldName: "userName", JavaType: "String", JdbcType: "VARCHAR", Operator: "=", Optional: true},
+			{ColumnName: "user_id", FieldName: "userIds", JavaType: "Long", JdbcType: "BIGINT", Operator: "IN"},
+			{ColumnName: "created_at", FieldName: "createdAt", JavaType: "Date", JdbcType: "TIMESTAMP", Operator: "<", Optional: true},
+		},
+		HasLimit: true,
+	}
+}
+
+func TestEvaluateStatement_DynamicWhere(t *testing.T) {
+	result, err := GenerateSnippet(evalSnippetConfig(), "UserMapper", "com.example.User", "user", NewDialect(nil))
+	assert.NoError(t, err)
+
+	bound, err := EvaluateStatement(result.XMLCode, "search", map[string]interface{}{
+		"status":  1,
+		"userIds": []interface{}{3, 4},
+		"limit":   10,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM user WHERE status = ? AND user_id IN ( ? , ? ) LIMIT ?", bound.SQL)
+	assert.Equal(t, []BoundParam{
+		{Name: "status", Value: 1, JdbcType: "INTEGER"},
+		{Name: "userIds[0]", Value: 3, JdbcType: "BIGINT"},
+		{Name: "userIds[1]", Value: 4, JdbcType: "BIGINT"},
+		{Name: "limit", Value: 10},
+	}, bound.Params)
+
+	// 可选条件：空串跳过，空集合走 <otherwise>
+	bound, err = EvaluateStatement(result.XMLCode, "search", map[string]interface{}{
+		"status":    1,
+		"userName":  "",
+		"userIds":   []interface{}{},
+		"createdAt": "2024-01-01",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM user WHERE status = ? AND 1 = 0 AND created_at < ? LIMIT ?", bound.SQL)
+
+	bound, err = EvaluateStatement(result.XMLCode, "countSearch", map[string]interface{}{"userName": "tom"})
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT COUNT(*) FROM user WHERE status = ? AND user_name = ? AND 1 = 0", bound.SQL)
+
+	_, err = EvaluateStatement(result.XMLCode, "missing", nil)
+	assert.ErrorContains(t, err, "语句不存在: missing")
+}
+
+func TestEvaluateStatement_SetAndBatch(t *testing.T) {
+	cfg := &config.SnippetConfig{
+		MethodName:  "updateStatus",
+		Operation:   config.OperationUpdate,
+		IsBatch:     true,
+		SetFields:   []config.SnippetField{{ColumnName: "status", FieldName: "status", JavaType: "Integer", JdbcType: "INTEGER"}},
+		WhereFields: []config.SnippetField{{ColumnName: "id", FieldName: "id", JavaType: "Long", JdbcType: "BIGINT"}},
+	}
+	result, err := GenerateSnippet(cfg, "UserMapper", "com.example.User", "user", NewDialect(nil))
+	assert.NoError(t, err)
+
+	bound, err := EvaluateStatement(result.XMLCode, "updateStatus", map[string]interface{}{
+		"list": []interface{}{
+			map[string]interface{}{"id": 1, "status": 2},
+			map[string]interface{}{"id": 3, "status": 4},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE user SET status = ? WHERE id = ? ; UPDATE user SET status = ? WHERE id = ?", bound.SQL)
+	assert.Equal(t, "list[1].id", bound.Params[3].Name)
+	assert.Equal(t, 3, bound.Params[3].Value)
+}
+
+func TestEvalTest(t *testing.T) {
+	scope := &evalScope{root: map[string]interface{}{
+		"status": 0,
+		"name":   "tom",
+		"ids":    []interface{}{1},
+		"user":   map[string]interface{}{"age": 20.0},
+	}}
+	cases := map[string]bool{
+		"status != null":                    true,
+		"status != null and status != ''":   false, // OGNL 中 0 == ''
+		"name != null and name != ''":       true,
+		"ids != null and ids.size() > 0":    true,
+		"missing != null && missing.size()": false,
+		"user.age >= 18 and !(name == 'x')": true,
+		"status == 1 or user.age lt 30":     true,
+		"not ids.isEmpty()":                 true,
+	}
+	for expr, want := range cases {
+		got, err := evalTest(expr, scope)
+		assert.NoError(t, err, expr)
+		assert.Equal(t, want, got, expr)
+	}
+
+	_, err := evalTest("name ==", scope)
+	assert.Error(t, err)
+	_, err = evalTest("name.trim()", scope)
+	assert.ErrorContains(t, err, "不支持的方法调用")
+}
+
+func TestRenderSnippetSQL(t *testing.T) {
+	cfg := evalSnippetConfig()
+	result, err := GenerateSnippet(cfg, "UserMapper", "com.example.User", "user", NewDialect(nil))
+	assert.NoError(t, err)
+
+	sql, bound, err := RenderSnippetSQL(cfg, result, NewDialect(nil), nil)
+	assert.NoError(t, err)
+	assert.Len(t, bound.Params, 5)
+	assert.Equal(t, "SELECT * FROM user WHERE status = 1 AND user_name = 'a' AND user_id IN ( 1 ) AND created_at < '2000-01-01 00:00:00' LIMIT 10", sql)
+
+	oracle := NewDialect(&config.DatabaseConfig{DbType: config.DbTypeOracle})
+	result, err = GenerateSnippet(cfg, "UserMapper", "com.example.User", "user", oracle)
+	assert.NoError(t, err)
+	sql, _, err = RenderSnippetSQL(cfg, result, oracle, nil)
+	assert.NoError(t, err)
+	assert.Contains(t, sql, "created_at < TIMESTAMP '2000-01-01 00:00:00' FETCH FIRST 10 ROWS ONLY")
+
+	pg := NewDialect(&config.DatabaseConfig{DbType: config.DbTypePostgreSQL})
+	assert.Equal(t, "SELECT 'it''s ?' WHERE flag = TRUE AND name = NULL",
+		InlineBoundSQL(&BoundSQL{SQL: "SELECT 'it''s ?' WHERE flag = ? AND name = ?", Params: []BoundParam{{Value: true}, {Value: nil}}}, pg))
+}
+
+func TestBindBoundSQL(t *testing.T) {
+	bound := &BoundSQL{
+		SQL: "SELECT 'it''s ?' WHERE name = ? AND created_at < ? AND flag = ? LIMIT ?",
+		Params: []BoundParam{
+			{Value: `a\' OR 1=1 -- `},
+			{Value: "2000-01-01 00:00:00", JdbcType: "TIMESTAMP"},
+			{Value: true},
+			{Value: float64(10)},
+		},
+	}
+
+	// 参数值不拼接进SQL，只作为驱动绑定参数传递
+	query, args := BindBoundSQL(bound, NewDialect(nil))
+	assert.Equal(t, "SELECT 'it''s ?' WHERE name = ? AND created_at < ? AND flag = ? LIMIT ?", query)
+	assert.Equal(t, `a\' OR 1=1 -- `, args[0])
+	assert.Equal(t, time.Date(2000, 1, 1, 0, 0, 0, 0, time.Local), args[1])
+	assert.Equal(t, 1, args[2])
+	assert.Equal(t, int64(10), args[3])
+
+	query, args = BindBoundSQL(bound, NewDialect(&config.DatabaseConfig{DbType: config.DbTypePostgreSQL}))
+	assert.Equal(t, "SELECT 'it''s ?' WHERE name = $1 AND created_at < $2 AND flag = $3 LIMIT $4", query)
+	assert.Equal(t, true, args[2])
+
+	query, _ = BindBoundSQL(bound, NewDialect(&config.DatabaseConfig{DbType: config.DbTypeOracle}))
+	assert.Equal(t, "SELECT 'it''s ?' WHERE name = :1 AND created_at < :2 AND flag = :3 LIMIT :4", query)
+}
+
+func TestEvaluateStatement_GeneratedMapper(t *testing.T) {
+	xmlText := renderKeyStrategyXML(t, config.DbTypeOracle, config.KeyStrategySequence, "USER_SEQ")
+
+	bound, err := EvaluateStatement(xmlText, "selectByPrimaryKey", map[string]interface{}{"id": 7})
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id, name FROM user WHERE id = ?", bound.SQL)
+
+	// <selectKey> 不计入语句，<trim> 去除末尾逗号
+	bound, err = EvaluateStatement(xmlText, "insertSelective", map[string]interface{}{"id": 1, "name": "tom"})
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO user ( id, name ) values ( ?, ? )", bound.SQL)
+	assert.Equal(t, "tom", bound.Params[1].Value)
+
+	bound, err = EvaluateStatement(xmlText, "updateByPrimaryKeySelective", map[string]interface{}{"id": 1, "name": "tom"})
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE user SET name = ? WHERE id = ?", bound.SQL)
+
+	bound, err = EvaluateStatement(xmlText, "", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "selectByPrimaryKey", bound.StatementID)
+}
+
+func TestEvaluateStatement_IncludeAndTextSubstitution(t *testing.T) {
+	xmlText := `<mapper namespace="com.example.UserMapper">
+    <sql id="columns">${alias}.id, ${alias}.name</sql>
+    <sql id="loop"><include refid="loop"/></sql>
+    <select id="search">
+        SELECT <include refid="com.example.UserMapper.columns"><property name="alias" value="u"/></include>
+        FROM user u
+        <trim prefix="WHERE" prefixOverrides="AND |OR ">
+            <if test="name != null">OR u.name = #{name}</if>
+        </trim>
+        ORDER BY ${sortColumn}
+    </select>
+    <select id="broken"><include refid="loop"/></select>
+</mapper>`
+
+	bound, err := EvaluateStatement(xmlText, "search", map[string]interface{}{"name": "tom", "sortColumn": "u.id"})
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT u.id, u.name FROM user u WHERE u.name = ? ORDER BY u.id", bound.SQL)
+	assert.Equal(t, []BoundParam{{Name: "name", Value: "tom"}}, bound.Params)
+
+	_, err = EvaluateStatement(xmlText, "broken", nil)
+	assert.ErrorContains(t, err, "循环引用")
+
+	_, err = EvaluateStatement(`<select id="x"><bind name="p" value="1"/></select>`, "x", nil)
+	assert.ErrorContains(t, err, "不支持的标签 <bind>")
+}
+
+func TestMatchResultColumns(t *testing.T) {
+	columns := methodParserColumns()
+
+	// 映射到实体类（BaseResultMap）：按列名驼峰推导属性
+	cfg := &config.SnippetConfig{MethodName: "listAll", Operation: config.OperationSelect}
+	result, err := GenerateSnippet(cfg, "UserMapper", "com.example.User", "user", NewDialect(nil))
+	assert.NoError(t, err)
+	assert.Contains(t, result.XMLCode, `resultMap="BaseResultMap"`)
+	matches, err := MatchResultColumns(result.XMLCode, "listAll", "com.example.User", columns[:2], []string{"ID", "nickname"}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []ResultColumnMatch{
+		{Column: "ID", Property: "id", Returned: true},
+		{Column: "nickname", Returned: true},
+		{Column: "user_id", Property: "userId"},
+	}, matches)
+
+	// 命名规则和列覆盖修改的属性名与实体类一致
+	propertyName := PropertyNamer(&config.GeneratorConfig{
+		Naming:          config.NamingRules{ColumnPrefixes: []string{"user_"}},
+		ColumnOverrides: []config.ColumnOverride{{ColumnName: "id", PropertyName: "userKey"}},
+	})
+	matches, err = MatchResultColumns(result.XMLCode, "listAll", "com.example.User", columns[:2], []string{"id", "user_id"}, propertyName)
+	assert.NoError(t, err)
+	assert.Equal(t, []ResultColumnMatch{
+		{Column: "id", Property: "userKey", Returned: true},
+		{Column: "user_id", Property: "id", Returned: true},
+	}, matches)
+
+	// 分组查询映射到 DTO 的 resultMap
+	cfg = &config.SnippetConfig{
+		MethodName:    "countByStatus",
+		Operation:     config.OperationSelect,
+		SelectFields:  []config.SnippetField{{ColumnName: "status", FieldName: "status"}, {ColumnName: "*", Aggregate: "COUNT", Alias: "total"}},
+		GroupByFields: []config.SnippetField{{ColumnName: "status"}},
+	}
+	result, err = GenerateSnippet(cfg, "UserMapper", "com.example.User", "user", NewDialect(nil))
+	assert.NoError(t, err)
+	matches, err = MatchResultColumns(result.XMLCode, "countByStatus", "com.example.User", columns, []string{"status", "total"}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "total", matches[1].Property)
+	assert.Len(t, matches, 2)
+
+	// 简单类型结果不做对照
+	matches, err = MatchResultColumns(`<select id="cnt" resultType="java.lang.Long">SELECT COUNT(*) FROM user</select>`, "cnt", "com.example.User", columns, []string{"COUNT(*)"}, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, matches)
+}
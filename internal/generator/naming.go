@@ -0,0 +1,154 @@
+package generator
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/yourusername/mybatis-generator-gui-go/internal/config"
+	"github.com/yourusername/mybatis-generator-gui-go/internal/utils"
+)
+
+// Namer 按命名规则由表名/列名推导类名、Mapper名与属性名
+type Namer struct {
+	rules         config.NamingRules
+	tableRenames  []renameRule
+	columnRenames []renameRule
+}
+
+type renameRule struct {
+	pattern     *regexp.Regexp
+	replacement string
+}
+
+// NewNamer 创建命名器；正则无效时返回错误，同时返回忽略无效规则后的命名器
+func NewNamer(rules config.NamingRules) (*Namer, error) {
+	n := &Namer{rules: rules}
+	var errs []string
+	n.tableRenames, errs = compileRenameRules(rules.TableRenames, errs)
+	n.columnRenames, errs = compileRenameRules(rules.ColumnRenames, errs)
+	if len(errs) > 0 {
+		return n, fmt.Errorf("命名规则无效: %s", strings.Join(errs, "; "))
+	}
+	return n, nil
+}
+
+func compileRenameRules(rules []config.RenameRule, errs []string) ([]renameRule, []string) {
+	compiled := make([]renameRule, 0, len(rules))
+	for _, rule := range rules {
+		if rule.Pattern == "" {
+			continue
+		}
+		re, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", rule.Pattern, err))
+			continue
+		}
+		compiled = append(compiled, renameRule{pattern: re, replacement: rule.Replacement})
+	}
+	return compiled, errs
+}
+
+// BaseName 表名去前缀、重命名并转为帕斯卡命名（不含后缀）
+func (n *Namer) BaseName(tableName string) string {
+	name := applyRenames(stripPrefix(tableName, n.rules.TablePrefixes), n.tableRenames)
+	return pascalCase(name)
+}
+
+// DomainObjectName 推导实体类名（BaseName + 实体后缀）
+func (n *Namer) DomainObjectName(tableName string) string {
+	return n.BaseName(tableName) + n.rules.ModelSuffix
+}
+
+// MapperName 推导Mapper名称（BaseName + Mapper后缀）
+func (n *Namer) MapperName(tableName string) string {
+	suffix := n.rules.MapperSuffix
+	if suffix == "" {
+		suffix = "Mapper"
+	}
+	return n.BaseName(tableName) + suffix
+}
+
+// ColumnBaseName 列名去前缀并重命名（不转驼峰）
+func (n *Namer) ColumnBaseName(columnName string) string {
+	return applyRenames(stripPrefix(columnName, n.rules.ColumnPrefixes), n.columnRenames)
+}
+
+// MapperNameForDomain 由自定义实体类名推导Mapper名称（去掉实体后缀后加Mapper后缀）
+func (n *Namer) MapperNameForDomain(domainObjectName string) string {
+	suffix := n.rules.MapperSuffix
+	if suffix == "" {
+		suffix = "Mapper"
+	}
+	return strings.TrimSuffix(domainObjectName, n.rules.ModelSuffix) + suffix
+}
+
+// FieldName 推导属性名；useActualColumnNames 时仅去前缀和重命名，不转驼峰
+func (n *Namer) FieldName(columnName string, useActualColumnNames bool) string {
+	name := n.ColumnBaseName(columnName)
+	if useActualColumnNames {
+		return name
+	}
+	return utils.DBStringToCamelCase(name)
+}
+
+// PropertyNamer 返回由列名推导实体属性名的函数，与生成实体类时一致（命名规则及列覆盖配置），忽略的列返回空串
+func PropertyNamer(cfg *config.GeneratorConfig) func(columnName string) string {
+	g := &Generator{config: cfg}
+	ignored := make(map[string]bool)
+	for _, col := range cfg.IgnoredColumns {
+		ignored[col] = true
+	}
+	overrides := make(map[string]string)
+	for _, override := range cfg.ColumnOverrides {
+		if override.PropertyName != "" {
+			overrides[override.ColumnName] = override.PropertyName
+		}
+	}
+	return func(columnName string) string {
+		if ignored[columnName] {
+			return ""
+		}
+		if property, ok := overrides[columnName]; ok {
+			return property
+		}
+		return g.getFieldName(columnName)
+	}
+}
+
+// stripPrefix 去除第一个匹配的前缀（忽略大小写），去除后为空则保留原名
+func stripPrefix(name string, prefixes []string) string {
+	lower := strings.ToLower(name)
+	for _, prefix := range prefixes {
+		if prefix == "" || len(prefix) >= len(name) {
+			continue
+		}
+		if strings.HasPrefix(lower, strings.ToLower(prefix)) {
+			return name[len(prefix):]
+		}
+	}
+	return name
+}
+
+func applyRenames(name string, rules []renameRule) string {
+	for _, rule := range rules {
+		name = rule.pattern.ReplaceAllString(name, rule.replacement)
+	}
+	return name
+}
+
+// pascalCase 下划线命名转为帕斯卡命名；已是大小写混合的片段（如重命名结果 UserAccount）保留原有大小写
+func pascalCase(name string) string {
+	parts := strings.Split(name, "_")
+	for i, part := range parts {
+		if len(part) == 0 {
+			continue
+		}
+		if part != strings.ToLower(part) && part != strings.ToUpper(part) {
+			parts[i] = strings.ToUpper(part[:1]) + part[1:]
+		} else {
+			parts[i] = strings.ToUpper(part[:1]) + strings.ToLower(part[1:])
+		}
+	}
+	return strings.Join(parts, "")
+}
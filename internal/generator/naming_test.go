@@ -0,0 +1,55 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yourusername/mybatis-generator-gui-go/internal/config"
+)
+
+func TestNamer_Defaults(t *testing.T) {
+	namer, err := NewNamer(config.NamingRules{})
+	assert.NoError(t, err)
+	assert.Equal(t, "UserInfo", namer.DomainObjectName("user_info"))
+	assert.Equal(t, "UserInfoMapper", namer.MapperName("user_info"))
+	assert.Equal(t, "UserInfo", namer.DomainObjectName("USER_INFO"))
+	assert.Equal(t, "createTime", namer.FieldName("create_time", false))
+	assert.Equal(t, "create_time", namer.FieldName("create_time", true))
+}
+
+func TestNamer_Rules(t *testing.T) {
+	namer, err := NewNamer(config.NamingRules{
+		TablePrefixes:  []string{"t_", "tb_", "sys_"},
+		ColumnPrefixes: []string{"f_"},
+		TableRenames:   []config.RenameRule{{Pattern: "(?i)^usr_(.*)$", Replacement: "user_$1"}},
+		ColumnRenames:  []config.RenameRule{{Pattern: "_dt$", Replacement: "_date"}},
+		ModelSuffix:    "DO",
+		MapperSuffix:   "Repository",
+	})
+	assert.NoError(t, err)
+
+	assert.Equal(t, "UserOrderDO", namer.DomainObjectName("tb_user_order"))
+	assert.Equal(t, "UserOrderRepository", namer.MapperName("tb_user_order"))
+	assert.Equal(t, "UserProfileDO", namer.DomainObjectName("T_USR_PROFILE"), "前缀忽略大小写")
+	assert.Equal(t, "RoleDO", namer.DomainObjectName("sys_role"))
+	assert.Equal(t, "UserProfile", namer.BaseName("t_usr_profile"))
+	assert.Equal(t, "SysDO", namer.DomainObjectName("sys_"), "去除前缀后为空时保留原名")
+
+	assert.Equal(t, "userName", namer.FieldName("f_user_name", false))
+	assert.Equal(t, "birthDate", namer.FieldName("f_birth_dt", false))
+	assert.Equal(t, "birth_date", namer.FieldName("f_birth_dt", true))
+}
+
+func TestNamer_InvalidPattern(t *testing.T) {
+	namer, err := NewNamer(config.NamingRules{
+		TableRenames: []config.RenameRule{{Pattern: "("}, {Pattern: "^old_", Replacement: ""}},
+	})
+	assert.Error(t, err)
+	// 无效规则被忽略，其余规则仍生效
+	assert.Equal(t, "Order", namer.DomainObjectName("old_order"))
+}
+
+func TestGenerator_FieldNameUsesNamingRules(t *testing.T) {
+	g := &Generator{config: &config.GeneratorConfig{Naming: config.NamingRules{ColumnPrefixes: []string{"f_"}}}}
+	assert.Equal(t, "userId", g.getFieldName("f_user_id"))
+}
@@ -0,0 +1,158 @@
+package generator
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/yourusername/mybatis-generator-gui-go/internal/database"
+	"github.com/yourusername/mybatis-generator-gui-go/internal/utils"
+)
+
+// 关联类型
+const (
+	RelationAssociation = "association" // 多对一：本表外键引用其他表
+	RelationCollection  = "collection"  // 一对多：其他表外键引用本表
+)
+
+// Relation 基于外键推导出的关联关系
+type Relation struct {
+	Kind          string           // 关联类型（association / collection）
+	Property      string           // 模型中的关联属性名
+	ClassName     string           // 关联表的模型类名
+	ModelType     string           // 关联表的模型全限定名
+	TableName     string           // 关联表名
+	Alias         string           // 关联表在联表查询中的别名，同时作为 columnPrefix 前缀
+	LocalColumn   string           // 本表中参与关联的列
+	ForeignColumn string           // 关联表中参与关联的列
+	Columns       []*ColumnMapping // 关联表的列（用于联表查询的 SELECT 列）
+	ResultMapID   string           // 本表中扩展的 resultMap ID
+	ResultMap     string           // 关联表 Mapper 的 BaseResultMap 全限定ID
+	MethodName    string           // 联表查询方法名，如 selectWithItemsById
+}
+
+// IsCollection 是否为一对多关联
+func (r *Relation) IsCollection() bool {
+	return r.Kind == RelationCollection
+}
+
+// loadRelations 读取外键并推导关联关系（仅支持单列外键，且本表需有主键）
+func (g *Generator) loadRelations(columns []*database.TableColumn) ([]*Relation, error) {
+	var pk *database.TableColumn
+	fieldNames := make(map[string]bool)
+	for _, col := range columns {
+		if col.ColumnKey == "PRI" && pk == nil {
+			pk = col
+		}
+		fieldNames[g.getFieldName(col.ColumnName)] = true
+	}
+	if pk == nil {
+		log.Printf("[Generator] 表 %s 没有主键，跳过关联查询生成", g.config.TableName)
+		return nil, nil
+	}
+	pkField := g.getFieldName(pk.ColumnName)
+
+	imported, err := g.connector.GetForeignKeys(g.config.TableName)
+	if err != nil {
+		return nil, err
+	}
+	exported, err := g.connector.GetReferencingForeignKeys(g.config.TableName)
+	if err != nil {
+		return nil, err
+	}
+
+	var relations []*Relation
+	addRelation := func(kind string, fk *database.ForeignKey, table, localColumn, foreignColumn string) error {
+		if len(fk.Columns) != 1 {
+			log.Printf("[Generator] 外键 %s 为多列外键，暂不支持生成关联查询", fk.ConstraintName)
+			return nil
+		}
+
+		namer := g.names()
+		className, mapperName := tableClassNames(g.config, table, namer)
+		// 属性名按不含后缀的类名推导，避免 Entity/DO 等后缀影响前缀匹配
+		property := relationProperty(kind, namer.ColumnBaseName(localColumn), foreignColumn,
+			namer.BaseName(table), namer.BaseName(g.config.TableName))
+		if fieldNames[property] {
+			log.Printf("[Generator] 关联属性 %s 与已有字段冲突，跳过外键 %s", property, fk.ConstraintName)
+			return nil
+		}
+		fieldNames[property] = true
+
+		refColumns, err := g.connector.GetTableColumns(table)
+		if err != nil {
+			return fmt.Errorf("获取关联表 %s 列信息失败: %v", table, err)
+		}
+
+		alias := fmt.Sprintf("j%d", len(relations)+1)
+		relation := &Relation{
+			Kind:          kind,
+			Property:      property,
+			ClassName:     className,
+			ModelType:     g.config.ModelPackage + "." + className,
+			TableName:     table,
+			Alias:         alias,
+			LocalColumn:   localColumn,
+			ForeignColumn: foreignColumn,
+			ResultMapID:   "With" + utils.FirstUpper(property) + "ResultMap",
+			ResultMap:     g.config.DaoPackage + "." + mapperName + ".BaseResultMap",
+			MethodName:    "selectWith" + utils.FirstUpper(property) + "By" + utils.FirstUpper(pkField),
+		}
+		for _, col := range refColumns {
+			relation.Columns = append(relation.Columns, &ColumnMapping{
+				ColumnName: col.ColumnName,
+				JdbcType:   database.GetJdbcType(g.dbConfig.DbType, col.DataType),
+			})
+		}
+		relations = append(relations, relation)
+		return nil
+	}
+
+	// 多对一：本表外键 -> 被引用表
+	for _, fk := range imported {
+		if err := addRelation(RelationAssociation, fk, fk.ReferencedTable, fk.Columns[0], fk.ReferencedColumns[0]); err != nil {
+			return nil, err
+		}
+	}
+
+	// 一对多：引用本表的子表
+	for _, fk := range exported {
+		if err := addRelation(RelationCollection, fk, fk.TableName, fk.ReferencedColumns[0], fk.Columns[0]); err != nil {
+			return nil, err
+		}
+	}
+
+	return relations, nil
+}
+
+// relationProperty 推导关联属性名
+// 多对一：由外键列去掉 _id 后缀得到（user_id -> user）；一对多：子表类名去掉父表类名前缀后取复数（OrderItem -> items）
+func relationProperty(kind, localColumn, foreignColumn, className, ownClassName string) string {
+	if kind == RelationAssociation {
+		name := utils.DBStringToCamelCase(localColumn)
+		for _, suffix := range []string{"Id", "ID"} {
+			if strings.HasSuffix(name, suffix) && len(name) > len(suffix) {
+				return name[:len(name)-len(suffix)]
+			}
+		}
+		return utils.FirstLower(className)
+	}
+
+	name := className
+	if strings.HasPrefix(name, ownClassName) && len(name) > len(ownClassName) {
+		name = name[len(ownClassName):]
+	}
+	return pluralize(utils.FirstLower(name))
+}
+
+// pluralize 简单的英文复数转换
+func pluralize(s string) string {
+	switch {
+	case strings.HasSuffix(s, "y") && len(s) > 1 && !strings.ContainsAny(s[len(s)-2:len(s)-1], "aeiou"):
+		return s[:len(s)-1] + "ies"
+	case strings.HasSuffix(s, "s"), strings.HasSuffix(s, "x"), strings.HasSuffix(s, "ch"), strings.HasSuffix(s, "sh"):
+		return s + "es"
+	default:
+		return s + "s"
+	}
+}
@@ -0,0 +1,55 @@
+package generator
+
+import (
+	"bytes"
+	"testing"
+	"text/template"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRelationProperty(t *testing.T) {
+	assert.Equal(t, "user", relationProperty(RelationAssociation, "user_id", "id", "User", "Order"))
+	assert.Equal(t, "buyer", relationProperty(RelationAssociation, "BUYER_ID", "ID", "User", "Order"))
+	assert.Equal(t, "user", relationProperty(RelationAssociation, "owner", "id", "User", "Order"))
+	assert.Equal(t, "items", relationProperty(RelationCollection, "id", "order_id", "OrderItem", "Order"))
+	assert.Equal(t, "categories", relationProperty(RelationCollection, "id", "parent_id", "Category", "Category"))
+	assert.Equal(t, "addresses", relationProperty(RelationCollection, "id", "user_id", "Address", "User"))
+}
+
+func TestMapperXMLTemplate_Relations(t *testing.T) {
+	pk := &ColumnMapping{ColumnName: "id", FieldName: "id", JdbcType: "BIGINT", JavaType: "Long"}
+	data := &MapperXMLData{
+		Namespace:  "com.example.dao.OrderMapper",
+		ModelType:  "com.example.model.Order",
+		TableName:  "orders",
+		Columns:    []*ColumnMapping{pk, {ColumnName: "user_id", FieldName: "userId", JdbcType: "BIGINT"}},
+		PrimaryKey: pk,
+		Relations: []*Relation{
+			{
+				Kind: RelationCollection, Property: "items", ModelType: "com.example.model.OrderItem",
+				TableName: "order_item", Alias: "j1", LocalColumn: "id", ForeignColumn: "order_id",
+				Columns:     []*ColumnMapping{{ColumnName: "id"}, {ColumnName: "order_id"}},
+				ResultMapID: "WithItemsResultMap", ResultMap: "com.example.dao.OrderItemMapper.BaseResultMap",
+				MethodName: "selectWithItemsById",
+			},
+		},
+	}
+
+	tmpl, err := template.New("mapperXML").Parse(mapperXMLTemplate)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		t.Fatal(err)
+	}
+
+	output := buf.String()
+	assert.Contains(t, output, `<resultMap id="WithItemsResultMap" type="com.example.model.Order" extends="BaseResultMap">`)
+	assert.Contains(t, output, `<collection property="items" ofType="com.example.model.OrderItem" columnPrefix="j1_" resultMap="com.example.dao.OrderItemMapper.BaseResultMap" />`)
+	assert.Contains(t, output, "SELECT t.id, t.user_id,\n            j1.id AS j1_id, j1.order_id AS j1_order_id")
+	assert.Contains(t, output, "LEFT JOIN order_item j1 ON t.id = j1.order_id")
+	assert.Contains(t, output, "WHERE t.id = #{id,jdbcType=BIGINT}")
+}
@@ -0,0 +1,233 @@
+package generator
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/yourusername/mybatis-generator-gui-go/internal/config"
+)
+
+// SnippetMerge 片段合并结果（含重名检测信息）
+type SnippetMerge struct {
+	Result       *SnippetResult // 生成结果（自动重命名时为重命名后的结果）
+	OriginalName string         // 自动重命名前的方法名，未重命名为空
+	Conflicts    []string       // 重名冲突描述，无冲突为空
+}
+
+// snippetBaseResultMap 生成的 Mapper XML 中实体类结果映射的 id
+const snippetBaseResultMap = "BaseResultMap"
+
+// baseResultMapPattern 匹配 Mapper XML 中 BaseResultMap 的定义
+var baseResultMapPattern = regexp.MustCompile(`<resultMap\s[^>]*\bid\s*=\s*"BaseResultMap"`)
+
+// SnippetConflictError 片段与已有方法或语句重名（各片段的冲突描述见 SnippetMerge.Conflicts）
+type SnippetConflictError struct {
+	MapperName string
+}
+
+func (e *SnippetConflictError) Error() string {
+	return fmt.Sprintf("片段与 %s 中已有方法或语句重名", e.MapperName)
+}
+
+// maxRenameAttempts 自动重命名时尝试的最大序号
+const maxRenameAttempts = 100
+
+// javaKeywordsBeforeParen 标识符后紧跟 ( 但不是方法声明的关键字
+var javaKeywordsBeforeParen = map[string]bool{
+	"if": true, "for": true, "while": true, "switch": true, "catch": true,
+	"synchronized": true, "return": true, "new": true, "throw": true,
+}
+
+// ResolveSnippetConflicts 生成片段并检测与已有 Mapper 及前序片段之间的方法名、语句 id 重名
+// MyBatis 以方法名作为语句 id，不支持重载，同名方法即视为冲突（无论参数是否相同）
+// strategy 为 rename 时自动追加序号（如 selectByPrimaryKey2）；否则返回全部片段的检测结果及 *SnippetConflictError
+func ResolveSnippetConflicts(snippets []config.SnippetConfig, javaContent, xmlContent, mapperName, modelType, tableName string, dialect Dialect, strategy string) ([]*SnippetMerge, error) {
+	methods := make(map[string]string) // 方法名 -> 来源
+	for _, name := range JavaMethodNames(javaContent) {
+		methods[name] = mapperName + ".java"
+	}
+	ids := make(map[string]string) // 语句 id -> 来源
+	xmlIDs, err := XMLStatementIDs(xmlContent)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range xmlIDs {
+		ids[id] = mapperName + ".xml"
+	}
+
+	// 手写的 Mapper XML 可能没有 BaseResultMap，此时映射到实体类的查询改用 resultType
+	hasBaseResultMap := baseResultMapPattern.MatchString(xmlContent)
+	generate := func(cfg *config.SnippetConfig) (*SnippetResult, error) {
+		result, err := GenerateSnippet(cfg, mapperName, modelType, tableName, dialect)
+		if err == nil && !hasBaseResultMap {
+			result.XMLCode = strings.ReplaceAll(result.XMLCode, `resultMap="`+snippetBaseResultMap+`"`, `resultType="`+modelType+`"`)
+		}
+		return result, err
+	}
+
+	merges := make([]*SnippetMerge, 0, len(snippets))
+	hasConflict := false
+	for i := range snippets {
+		cfg := snippets[i]
+		result, err := generate(&cfg)
+		if err != nil {
+			return nil, fmt.Errorf("片段%d生成失败: %v", i+1, err)
+		}
+		merge := &SnippetMerge{Result: result}
+		conflicts, err := snippetConflicts(result, methods, ids)
+		if err != nil {
+			return nil, fmt.Errorf("片段%d解析失败: %v", i+1, err)
+		}
+
+		if len(conflicts) > 0 && strategy == config.SnippetConflictRename {
+			base := result.MethodName
+			renamed := false
+			for n := 2; n <= maxRenameAttempts; n++ {
+				cfg.MethodName = base + strconv.Itoa(n)
+				candidate, err := generate(&cfg)
+				if err != nil {
+					return nil, fmt.Errorf("片段%d生成失败: %v", i+1, err)
+				}
+				remaining, err := snippetConflicts(candidate, methods, ids)
+				if err != nil {
+					return nil, fmt.Errorf("片段%d解析失败: %v", i+1, err)
+				}
+				if len(remaining) == 0 {
+					merge.Result = candidate
+					merge.OriginalName = base
+					renamed = true
+					break
+				}
+			}
+			if !renamed {
+				return nil, fmt.Errorf("片段%d无法自动重命名: %s", i+1, strings.Join(conflicts, "; "))
+			}
+		}
+		merge.Conflicts = conflicts
+		if merge.OriginalName == "" && len(conflicts) > 0 {
+			hasConflict = true
+		}
+
+		// 登记本片段的方法和语句，供后续片段检测
+		source := fmt.Sprintf("片段%d", i+1)
+		for _, name := range JavaMethodNames(merge.Result.JavaCode) {
+			if _, ok := methods[name]; !ok {
+				methods[name] = source
+			}
+		}
+		snippetIDs, _ := XMLStatementIDs(merge.Result.XMLCode)
+		for _, id := range snippetIDs {
+			if _, ok := ids[id]; !ok {
+				ids[id] = source
+			}
+		}
+		merges = append(merges, merge)
+	}
+
+	if hasConflict {
+		return merges, &SnippetConflictError{MapperName: mapperName}
+	}
+	return merges, nil
+}
+
+// snippetConflicts 检测片段生成的方法名和语句 id 是否已被占用
+func snippetConflicts(result *SnippetResult, methods, ids map[string]string) ([]string, error) {
+	var conflicts []string
+	for _, name := range JavaMethodNames(result.JavaCode) {
+		if source, ok := methods[name]; ok {
+			conflicts = append(conflicts, fmt.Sprintf("方法 %s 与 %s 中的方法重名", name, source))
+		}
+	}
+	snippetIDs, err := XMLStatementIDs(result.XMLCode)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range snippetIDs {
+		if source, ok := ids[id]; ok {
+			conflicts = append(conflicts, fmt.Sprintf("语句 id %s 与 %s 中的语句重复", id, source))
+		}
+	}
+	return conflicts, nil
+}
+
+// JavaMethodNames 提取 Java 类型体中（第一层大括号内）声明的方法名，按出现顺序去重
+// 忽略注释、字符串字面量、注解参数及 default 方法体中的调用
+func JavaMethodNames(javaContent string) []string {
+	code := stripJavaCommentsAndStrings(javaContent)
+	var names []string
+	seen := make(map[string]bool)
+	depth := 0
+	for i := 0; i < len(code); i++ {
+		ch := code[i]
+		switch {
+		case ch == '{':
+			depth++
+		case ch == '}':
+			depth--
+		case isJavaIdentStart(ch) && (i == 0 || !isJavaIdentPart(code[i-1])):
+			start := i
+			for i+1 < len(code) && isJavaIdentPart(code[i+1]) {
+				i++
+			}
+			name := code[start : i+1]
+			// 类型体为第一层；片段代码没有外层类型，方法位于第0层
+			if depth > 1 || javaKeywordsBeforeParen[name] {
+				continue
+			}
+			j := skipSpaces(code, i+1)
+			if j >= len(code) || code[j] != '(' || precededByAnnotation(code, start) {
+				continue
+			}
+			end := matchParen(code, j)
+			if end < 0 {
+				continue
+			}
+			k := skipSpaces(code, end+1)
+			if strings.HasPrefix(code[k:], "throws") {
+				for k < len(code) && code[k] != ';' && code[k] != '{' {
+					k++
+				}
+			}
+			if k < len(code) && (code[k] == ';' || code[k] == '{') && !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+			i = end
+		}
+	}
+	return names
+}
+
+// XMLStatementIDs 提取 Mapper XML 中 <select>/<insert>/<update>/<delete>/<sql>/<resultMap> 的 id
+func XMLStatementIDs(xmlContent string) ([]string, error) {
+	root, err := parseMapperXML(xmlContent)
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	for _, child := range root.Children {
+		switch child.Name {
+		case "select", "insert", "update", "delete", "sql", "resultMap":
+			if id := child.Attrs["id"]; id != "" {
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids, nil
+}
+
+// precededByAnnotation 标识符前是否为 @（注解）或 .（限定名/方法调用）
+func precededByAnnotation(code string, start int) bool {
+	for p := start - 1; p >= 0; p-- {
+		switch code[p] {
+		case ' ', '\t', '\r', '\n':
+			continue
+		case '@', '.':
+			return true
+		}
+		return false
+	}
+	return false
+}
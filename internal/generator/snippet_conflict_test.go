@@ -0,0 +1,88 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yourusername/mybatis-generator-gui-go/internal/config"
+)
+
+const conflictMapperJava = `package com.example.mapper;
+
+import com.example.User;
+
+// 基础 Mapper，注释中的 deleteAll(); 不是方法
+public interface UserMapper {
+    @Select("SELECT * FROM user WHERE name = 'countByName(x);'")
+    User selectByPrimaryKey(@Param("id") Long id) throws java.sql.SQLException;
+
+    int insert(User record);
+
+    default int insertOrSkip(User record) {
+        return record == null ? 0 : insert(record);
+    }
+}
+`
+
+func TestJavaMethodNames(t *testing.T) {
+	assert.Equal(t, []string{"selectByPrimaryKey", "insert", "insertOrSkip"}, JavaMethodNames(conflictMapperJava))
+
+	result, err := GenerateSnippet(evalSnippetConfig(), "UserMapper", "com.example.User", "user", NewDialect(nil))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"search", "countSearch"}, JavaMethodNames(result.JavaCode))
+
+	ids, err := XMLStatementIDs(result.XMLCode)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"search", "countSearch"}, ids)
+}
+
+func TestResolveSnippetConflicts(t *testing.T) {
+	xmlText := renderKeyStrategyXML(t, config.DbTypeMySQL, config.KeyStrategyIdentity, "")
+	snippets := []config.SnippetConfig{
+		{MethodName: "selectByPrimaryKey", Operation: config.OperationSelect},
+		{MethodName: "listActive", Operation: config.OperationSelect},
+		{MethodName: "listActive", Operation: config.OperationSelect},
+	}
+
+	merges, err := ResolveSnippetConflicts(snippets, conflictMapperJava, xmlText, "UserMapper", "com.example.User", "user", NewDialect(nil), config.SnippetConflictError)
+	assert.ErrorContains(t, err, "重名")
+	var conflictErr *SnippetConflictError
+	assert.ErrorAs(t, err, &conflictErr)
+	assert.Len(t, merges, 3)
+	assert.Equal(t, []string{
+		"方法 selectByPrimaryKey 与 UserMapper.java 中的方法重名",
+		"语句 id selectByPrimaryKey 与 UserMapper.xml 中的语句重复",
+	}, merges[0].Conflicts)
+	assert.Empty(t, merges[1].Conflicts)
+	assert.Equal(t, []string{
+		"方法 listActive 与 片段2 中的方法重名",
+		"语句 id listActive 与 片段2 中的语句重复",
+	}, merges[2].Conflicts)
+
+	merges, err = ResolveSnippetConflicts(snippets, conflictMapperJava, xmlText, "UserMapper", "com.example.User", "user", NewDialect(nil), config.SnippetConflictRename)
+	assert.NoError(t, err)
+	assert.Equal(t, "selectByPrimaryKey2", merges[0].Result.MethodName)
+	assert.Equal(t, "selectByPrimaryKey", merges[0].OriginalName)
+	assert.Contains(t, merges[0].Result.XMLCode, `id="selectByPrimaryKey2"`)
+	assert.Equal(t, "", merges[1].OriginalName)
+	assert.Equal(t, "listActive2", merges[2].Result.MethodName)
+}
+
+func TestResolveSnippetConflicts_BaseResultMapFallback(t *testing.T) {
+	snippets := []config.SnippetConfig{{MethodName: "listActive", Operation: config.OperationSelect}}
+
+	// 生成的 Mapper XML 定义了 BaseResultMap，片段直接引用
+	xmlText := renderKeyStrategyXML(t, config.DbTypeMySQL, config.KeyStrategyIdentity, "")
+	merges, err := ResolveSnippetConflicts(snippets, conflictMapperJava, xmlText, "UserMapper", "com.example.User", "user", NewDialect(nil), config.SnippetConflictError)
+	assert.NoError(t, err)
+	assert.Contains(t, merges[0].Result.XMLCode, `resultMap="BaseResultMap"`)
+
+	// 手写的 Mapper XML 没有 BaseResultMap，改用 resultType 映射到实体类
+	handwritten := `<mapper namespace="com.example.mapper.UserMapper">
+    <resultMap id="UserMap" type="com.example.User"/>
+</mapper>`
+	merges, err = ResolveSnippetConflicts(snippets, conflictMapperJava, handwritten, "UserMapper", "com.example.User", "user", NewDialect(nil), config.SnippetConflictError)
+	assert.NoError(t, err)
+	assert.Contains(t, merges[0].Result.XMLCode, `resultType="com.example.User"`)
+	assert.NotContains(t, merges[0].Result.XMLCode, "BaseResultMap")
+}
@@ -0,0 +1,275 @@
+package generator
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yourusername/mybatis-generator-gui-go/internal/config"
+	"github.com/yourusername/mybatis-generator-gui-go/internal/database"
+	"github.com/yourusername/mybatis-generator-gui-go/internal/utils"
+)
+
+// RenderSnippetSQL 按参数对片段主语句求值，并将参数内嵌为字面量得到用于展示的SQL
+// params 为 nil 时使用示例参数（可选条件均提供参数，渲染结果覆盖全部条件）
+// 内嵌SQL仅供展示；在数据库上执行时使用 BindBoundSQL 以绑定参数传值
+func RenderSnippetSQL(cfg *config.SnippetConfig, result *SnippetResult, dialect Dialect, params map[string]interface{}) (string, *BoundSQL, error) {
+	if params == nil {
+		params = SampleSnippetParams(cfg)
+	}
+	bound, err := EvaluateStatement(result.XMLCode, result.MethodName, params)
+	if err != nil {
+		return "", nil, err
+	}
+	return InlineBoundSQL(bound, dialect), bound, nil
+}
+
+// SampleSnippetParams 按片段配置构造示例参数（集合参数和批量列表均只含一个元素）
+func SampleSnippetParams(cfg *config.SnippetConfig) map[string]interface{} {
+	params := make(map[string]interface{})
+
+	switch cfg.Operation {
+	case config.OperationInsert:
+		params = sampleFieldValues(cfg.InsertFields)
+		if cfg.IsBatch {
+			params = map[string]interface{}{"list": []interface{}{params}}
+		}
+		return params
+
+	case config.OperationUpdate:
+		params = sampleFieldValues(append(append([]config.SnippetField{}, cfg.SetFields...), snippetParamFields(cfg)...))
+		if cfg.IsBatch {
+			params = map[string]interface{}{"list": []interface{}{params}}
+		}
+		return params
+	}
+
+	if cfg.IsBatch {
+		if f := firstWhereField(cfg.WhereFields); f != nil {
+			params["list"] = []interface{}{sampleValue(f.JavaType, f.JdbcType)}
+		}
+		return params
+	}
+	params = sampleFieldValues(snippetParamFields(cfg))
+	if cfg.Operation == config.OperationSelect && cfg.HasLimit && !cfg.IsLimitFixed {
+		limitName := cfg.LimitValue
+		if limitName == "" {
+			limitName = "limit"
+		}
+		params[limitName] = 10
+	}
+	return params
+}
+
+// sampleFieldValues 为字段生成示例值（IN/NOT IN 条件为单元素列表）
+func sampleFieldValues(fields []config.SnippetField) map[string]interface{} {
+	params := make(map[string]interface{}, len(fields))
+	for _, f := range fields {
+		value := sampleValue(f.JavaType, f.JdbcType)
+		if isCollectionOperator(f.Operator) {
+			value = []interface{}{value}
+		}
+		params[f.FieldName] = value
+	}
+	return params
+}
+
+// sampleValue 按 Java/JDBC 类型生成示例值（日期时间以字符串表示，内嵌时按方言转换）
+func sampleValue(javaType, jdbcType string) interface{} {
+	switch javaType {
+	case "Integer", "Long", "Short", "Byte", "BigInteger", "BigDecimal", "Double", "Float", "int", "long":
+		return 1
+	case "Boolean", "boolean":
+		return true
+	}
+	switch jdbcType {
+	case "DATE":
+		return "2000-01-01"
+	case "TIME":
+		return "00:00:00"
+	case "TIMESTAMP":
+		return "2000-01-01 00:00:00"
+	}
+	return "a"
+}
+
+// InlineBoundSQL 将绑定参数按顺序内嵌为SQL字面量（单引号字符串内的 ? 不替换），仅用于展示
+func InlineBoundSQL(bound *BoundSQL, dialect Dialect) string {
+	return replacePlaceholders(bound.SQL, len(bound.Params), func(i int) string {
+		return sqlLiteral(bound.Params[i], dialect)
+	})
+}
+
+// BindBoundSQL 将 ? 占位符转换为方言的绑定占位符（PostgreSQL 为 $n、Oracle 为 :n、MySQL 保持 ?），
+// 并返回按顺序排列的驱动绑定参数
+func BindBoundSQL(bound *BoundSQL, dialect Dialect) (string, []interface{}) {
+	query := replacePlaceholders(bound.SQL, len(bound.Params), func(i int) string {
+		switch {
+		case dialect.IsPostgreSQL():
+			return "$" + strconv.Itoa(i+1)
+		case dialect.IsOracle():
+			return ":" + strconv.Itoa(i+1)
+		}
+		return "?"
+	})
+	args := make([]interface{}, len(bound.Params))
+	for i, p := range bound.Params {
+		args[i] = bindValue(p, dialect)
+	}
+	return query, args
+}
+
+// replacePlaceholders 依次替换单引号字符串之外的前 n 个 ? 占位符
+func replacePlaceholders(sqlText string, n int, replace func(i int) string) string {
+	var b strings.Builder
+	inQuote := false
+	next := 0
+	for _, r := range sqlText {
+		switch {
+		case r == '\'':
+			inQuote = !inQuote
+		case r == '?' && !inQuote && next < n:
+			b.WriteString(replace(next))
+			next++
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// bindValue 将参数值转换为驱动绑定参数
+// 整数值的数字（JSON 解析为 float64）转为 int64，布尔值在 PostgreSQL 之外为 1/0，日期时间字符串解析为 time.Time
+func bindValue(p BoundParam, dialect Dialect) interface{} {
+	switch v := p.Value.(type) {
+	case bool:
+		if dialect.IsPostgreSQL() {
+			return v
+		}
+		if v {
+			return 1
+		}
+		return 0
+	case string:
+		if p.JdbcType == "DATE" || p.JdbcType == "TIMESTAMP" {
+			for _, layout := range []string{"2006-01-02 15:04:05", "2006-01-02"} {
+				if t, err := time.ParseInLocation(layout, v, time.Local); err == nil {
+					return t
+				}
+			}
+		}
+		return v
+	case float64:
+		if v == math.Trunc(v) && math.Abs(v) < 1<<53 {
+			return int64(v)
+		}
+		return v
+	}
+	return p.Value
+}
+
+// sqlLiteral 将参数值转换为SQL字面量
+// 布尔值在 PostgreSQL 中为 TRUE/FALSE、其他数据库为 1/0；Oracle 的日期时间参数使用 DATE/TIMESTAMP 字面量
+func sqlLiteral(p BoundParam, dialect Dialect) string {
+	switch v := p.Value.(type) {
+	case nil:
+		return "NULL"
+	case bool:
+		if dialect.IsPostgreSQL() {
+			return strings.ToUpper(strconv.FormatBool(v))
+		}
+		if v {
+			return "1"
+		}
+		return "0"
+	case string:
+		quoted := "'" + strings.ReplaceAll(v, "'", "''") + "'"
+		if dialect.IsOracle() && (p.JdbcType == "DATE" || p.JdbcType == "TIMESTAMP") {
+			if len(v) == len("2000-01-01") {
+				return "DATE " + quoted
+			}
+			return "TIMESTAMP " + quoted
+		}
+		return quoted
+	}
+	if f, ok := toNumber(p.Value); ok {
+		return strconv.FormatFloat(f, 'f', -1, 64)
+	}
+	return "'" + strings.ReplaceAll(fmt.Sprint(p.Value), "'", "''") + "'"
+}
+
+// ResultColumnMatch 查询返回列与结果映射属性的对应关系
+type ResultColumnMatch struct {
+	Column   string `json:"column"`   // 列名（返回列取结果集列名，未返回的映射列取映射中的列名）
+	Property string `json:"property"` // 映射到的属性（无对应属性为空）
+	Returned bool   `json:"returned"` // 结果集中是否有该列
+}
+
+// MatchResultColumns 将查询返回的列与语句的结果映射对照（列名忽略大小写）
+// resultMap 取其 <id>/<result>；片段中未定义的 BaseResultMap 及 resultType 为实体类时，按 propertyName 由表列推导属性
+// （与生成的实体类一致，为 nil 时按列名转驼峰，返回空串的列不映射）；其他 resultType（如 java.lang.Long、map）返回 nil
+func MatchResultColumns(xmlText, statementID, modelType string, tableColumns []*database.TableColumn, returned []string, propertyName func(columnName string) string) ([]ResultColumnMatch, error) {
+	root, err := parseMapperXML(xmlText)
+	if err != nil {
+		return nil, err
+	}
+	stmt := root.findStatement(statementID)
+	if stmt == nil {
+		return nil, fmt.Errorf("语句不存在: %s", statementID)
+	}
+
+	if propertyName == nil {
+		propertyName = utils.DBStringToCamelCase
+	}
+	var mappings [][2]string // {列名, 属性}
+	modelMappings := func() {
+		for _, col := range tableColumns {
+			if property := propertyName(col.ColumnName); property != "" {
+				mappings = append(mappings, [2]string{col.ColumnName, property})
+			}
+		}
+	}
+	resultMapID := stmt.Attrs["resultMap"]
+	switch {
+	case resultMapID != "":
+		resultMap := root.findResultMap(resultMapID)
+		if resultMap == nil {
+			if resultMapID != snippetBaseResultMap {
+				return nil, fmt.Errorf("resultMap 不存在: %s", resultMapID)
+			}
+			modelMappings()
+			break
+		}
+		for _, child := range resultMap.Children {
+			if child.Name == "id" || child.Name == "result" {
+				mappings = append(mappings, [2]string{child.Attrs["column"], child.Attrs["property"]})
+			}
+		}
+	case stmt.Attrs["resultType"] == modelType:
+		modelMappings()
+	default:
+		return nil, nil
+	}
+
+	matched := make(map[int]bool)
+	matches := make([]ResultColumnMatch, 0, len(returned)+len(mappings))
+	for _, col := range returned {
+		m := ResultColumnMatch{Column: col, Returned: true}
+		for i, mapping := range mappings {
+			if strings.EqualFold(mapping[0], col) {
+				m.Property = mapping[1]
+				matched[i] = true
+				break
+			}
+		}
+		matches = append(matches, m)
+	}
+	for i, mapping := range mappings {
+		if !matched[i] {
+			matches = append(matches, ResultColumnMatch{Column: mapping[0], Property: mapping[1]})
+		}
+	}
+	return matches, nil
+}
@@ -12,16 +12,24 @@ import (
 
 // SnippetResult 片段生成结果
 type SnippetResult struct {
-	JavaCode string   // Mapper接口方法声明代码（使用简单类名）
-	XMLCode  string   // XML SQL片段代码
-	Imports  []string // 需要的 import（如 ["java.util.List", "org.apache.ibatis.annotations.Param"]）
+	MethodName string   // 生成的方法名（未配置时为自动推导的名称）
+	JavaCode   string   // Mapper接口方法声明代码（使用简单类名）
+	XMLCode    string   // XML SQL片段代码
+	Imports    []string // 需要的 import（如 ["java.util.List", "org.apache.ibatis.annotations.Param"]）
+	// 查询结果DTO（关联/聚合/分组查询时生成，与实体类同目录；源码按 Lombok 配置由 Render 生成），否则为 nil
+	DTO *SnippetDTO
+	// 参数对象模式生成的参数类（与实体类同目录；源码按 Lombok 配置由 Render 生成），否则为 nil
+	ParamClass *SnippetParamClass
 }
 
-// GenerateSnippet 生成自定义MyBatis片段
-func GenerateSnippet(cfg *config.SnippetConfig, mapperName, modelType, tableName string) (*SnippetResult, error) {
+// GenerateSnippet 生成自定义MyBatis片段（分页语法由 dialect 决定）
+func GenerateSnippet(cfg *config.SnippetConfig, mapperName, modelType, tableName string, dialect Dialect) (*SnippetResult, error) {
+	if err := checkParamObject(cfg); err != nil {
+		return nil, err
+	}
 	switch cfg.Operation {
 	case config.OperationSelect:
-		return generateSelectSnippet(cfg, mapperName, modelType, tableName)
+		return generateSelectSnippet(cfg, mapperName, modelType, tableName, dialect)
 	case config.OperationInsert:
 		return generateInsertSnippet(cfg, mapperName, modelType, tableName)
 	case config.OperationDelete:
@@ -33,49 +41,71 @@ func GenerateSnippet(cfg *config.SnippetConfig, mapperName, modelType, tableName
 	}
 }
 
-// AppendSnippetToJava 将片段追加到Mapper.java文件内容中（在最后一个 } 前）
+// SnippetMethodName 返回片段生成的方法名：显式指定的优先，否则按操作类型及条件推导
+func SnippetMethodName(cfg *config.SnippetConfig) string {
+	if cfg.MethodName != "" {
+		return cfg.MethodName
+	}
+	switch cfg.Operation {
+	case config.OperationSelect:
+		return buildShapeMethodName(buildSelectMethodName(cfg), snippetReturnShape(cfg))
+	case config.OperationInsert:
+		if cfg.IsBatch {
+			return "insertBatchByFields"
+		}
+		return "insertByFields"
+	case config.OperationDelete:
+		return buildDeleteMethodName(cfg)
+	case config.OperationUpdate:
+		return buildUpdateMethodName(cfg)
+	default:
+		return ""
+	}
+}
+
+// AppendSnippetToJava 将片段追加到Mapper.java文件内容中（在第一个顶层类型体的结束 } 前）
+// 类型体结束位置按源码结构定位，不受注释、字符串及文件末尾注释中 } 的影响
 func AppendSnippetToJava(javaContent, javaCode string) string {
-	lastBrace := strings.LastIndex(javaContent, "}")
-	if lastBrace < 0 {
+	closeBrace := locateJava(javaContent).BodyEnd
+	if closeBrace < 0 {
 		return javaContent + "\n" + javaCode
 	}
-	return javaContent[:lastBrace] + "\n" + javaCode + "\n" + javaContent[lastBrace:]
+	return javaContent[:closeBrace] + "\n" + javaCode + "\n" + javaContent[closeBrace:]
 }
 
 // AppendImportsToJava 将缺失的 import 注入到 Mapper.java 的 import 块
+// 以 "static " 开头的为静态导入；已存在（含通配导入）的跳过，注释中的 import 不计入
 func AppendImportsToJava(javaContent string, imports []string) string {
 	for _, imp := range imports {
-		line := "import " + imp + ";"
-		if strings.Contains(javaContent, line) {
+		static := strings.HasPrefix(imp, "static ")
+		name := strings.TrimSpace(strings.TrimPrefix(imp, "static "))
+		layout := locateJava(javaContent)
+		if layout.hasImport(name, static) {
 			continue
 		}
-		// 在最后一个 import 语句后插入
-		lastImport := strings.LastIndex(javaContent, "import ")
-		if lastImport >= 0 {
-			newlineAfter := strings.Index(javaContent[lastImport:], "\n")
-			if newlineAfter >= 0 {
-				insertAt := lastImport + newlineAfter + 1
-				javaContent = javaContent[:insertAt] + line + "\n" + javaContent[insertAt:]
-				continue
-			}
+		line := "import " + imp + ";"
+		// 插入到同类 import 之后
+		if insertAt := layout.importInsertPos(static); insertAt >= 0 {
+			javaContent = javaContent[:insertAt] + line + "\n" + javaContent[insertAt:]
+			continue
 		}
-		// 如果没有 import，就在 package 语句后插入
-		pkgEnd := strings.Index(javaContent, ";")
-		if pkgEnd >= 0 {
-			newlineAfter := strings.Index(javaContent[pkgEnd:], "\n")
-			if newlineAfter >= 0 {
-				insertAt := pkgEnd + newlineAfter + 1
-				javaContent = javaContent[:insertAt] + "\n" + line + "\n" + javaContent[insertAt:]
-			}
+		// 如果没有 import，就在 package 语句后插入；没有 package 则插入到文件开头
+		if insertAt := layout.PackageEnd; insertAt >= 0 {
+			javaContent = javaContent[:insertAt] + "\n" + line + "\n" + javaContent[insertAt:]
+			continue
 		}
+		javaContent = line + "\n\n" + javaContent
 	}
 	return javaContent
 }
 
-// AppendSnippetToXML 将片段追加到Mapper.xml文件内容中（在 </mapper> 前）
+// AppendSnippetToXML 将片段追加到Mapper.xml文件内容中（在根元素的 </mapper> 前）
+// 按XML结构定位，注释中的 </mapper> 不受影响；XML 无法解析时退回按最后一个 </mapper> 插入
 func AppendSnippetToXML(xmlContent, xmlCode string) string {
-	closeTag := "</mapper>"
-	idx := strings.LastIndex(xmlContent, closeTag)
+	idx := mapperCloseOffset(xmlContent)
+	if idx < 0 {
+		idx = strings.LastIndex(xmlContent, "</mapper>")
+	}
 	if idx < 0 {
 		return xmlContent + "\n" + xmlCode
 	}
@@ -86,13 +116,63 @@ func AppendSnippetToXML(xmlContent, xmlCode string) string {
 // 内部生成函数
 // -----------------------------------------------------------------------
 
-func generateSelectSnippet(cfg *config.SnippetConfig, mapperName, modelType, tableName string) (*SnippetResult, error) {
-	methodName := cfg.MethodName
-	if methodName == "" {
-		methodName = buildSelectMethodName(cfg)
+func generateSelectSnippet(cfg *config.SnippetConfig, mapperName, modelType, tableName string, dialect Dialect) (*SnippetResult, error) {
+	if err := checkReturnShape(cfg); err != nil {
+		return nil, err
+	}
+	shape := snippetReturnShape(cfg)
+	methodName := SnippetMethodName(cfg)
+	if usesParamObject(cfg) {
+		cfg = withParamClassName(cfg, methodName)
 	}
 	simpleModel := lastPart(modelType)
 
+	// count / exists 不映射查询列；关联或分组时仍需投影以限定列名
+	if isScalarReturnShape(shape) && len(cfg.SelectFields) == 0 && needsProjection(cfg) {
+		scalar := *cfg
+		scalar.SelectFields = []config.SnippetField{{ColumnName: "*", FieldName: "total", JavaType: "Long", JdbcType: "BIGINT", Aggregate: "COUNT", Alias: "total"}}
+		cfg = &scalar
+	}
+
+	// 关联、聚合或分组查询的结果无法映射到实体类，使用投影配置副本并映射到生成的 DTO
+	fromSQL := tableName
+	resultType := modelType
+	var proj *selectProjection
+	if needsProjection(cfg) {
+		var err error
+		proj, err = buildSelectProjection(cfg, tableName, modelType, methodName)
+		if err != nil {
+			return nil, err
+		}
+	}
+	mapKey := ""
+	if shape == config.ReturnShapeMapKey {
+		var err error
+		if mapKey, err = mapKeyProperty(cfg, proj); err != nil {
+			return nil, err
+		}
+	}
+	if proj != nil {
+		cfg = &proj.Config
+		fromSQL = proj.FromSQL
+		simpleModel = proj.DTOName
+		resultType = proj.DTOType
+	}
+	if isScalarReturnShape(shape) {
+		var groupBySQL, havingSQL string
+		if proj != nil {
+			groupBySQL, havingSQL = proj.GroupBySQL, proj.HavingSQL
+		}
+		result, err := generateScalarSelectSnippet(cfg, methodName, fromSQL, groupBySQL, havingSQL, dialect)
+		if err != nil {
+			return nil, err
+		}
+		if err := attachParamClass(result, cfg, modelType); err != nil {
+			return nil, err
+		}
+		return result, nil
+	}
+
 	// ---- Java 代码 ----
 	var javaBuilder strings.Builder
 	javaBuilder.WriteString("    /**\n")
@@ -103,62 +183,227 @@ func generateSelectSnippet(cfg *config.SnippetConfig, mapperName, modelType, tab
 	}
 	javaBuilder.WriteString("     */\n")
 
+	if mapKey != "" {
+		javaBuilder.WriteString(fmt.Sprintf("    @MapKey(\"%s\")\n", mapKey))
+	}
+	returnType := selectReturnType(cfg, simpleModel)
 	if cfg.IsBatch {
 		if len(cfg.WhereFields) == 0 {
 			return nil, fmt.Errorf("批量查询需要至少一个WHERE字段")
 		}
 		inField := cfg.WhereFields[0]
 		javaBuilder.WriteString(fmt.Sprintf(
-			"    List<%s> %s(@Param(\"list\") List<%s> list);\n",
-			simpleModel, methodName, inField.JavaType,
+			"    %s %s(@Param(\"list\") List<%s> list);\n",
+			returnType, methodName, inField.JavaType,
 		))
 	} else {
 		params := buildJavaParams(cfg)
 		javaBuilder.WriteString(fmt.Sprintf(
-			"    List<%s> %s(%s);\n",
-			simpleModel, methodName, params,
+			"    %s %s(%s);\n",
+			returnType, methodName, params,
 		))
 	}
 
 	// 预计算 SQL 片段
 	selectSQL := buildSelectSQL(cfg.SelectFields)
-	whereSQL := buildWhereSQL(cfg.WhereFields, cfg.WhereLogic)
+	whereSQL := buildWhereClauseSQL(cfg.WhereFields, cfg.WhereLogic)
 	orderBySQL := buildOrderBySQL(cfg.OrderByFields)
+	var groupBySQL, havingSQL string
+	if proj != nil {
+		groupBySQL, havingSQL = proj.GroupBySQL, proj.HavingSQL
+	}
+
+	// 按方言生成 LIMIT 语法
+	var pagePrefix, pageSuffix string
+	if cfg.HasLimit {
+		pagePrefix, pageSuffix = dialect.PagingClauses("", snippetLimitExpr(cfg))
+	}
 
 	// ---- XML 代码 ----
+	resultMapXML := ""
+	resultMapID := ""
+	if shape == config.ReturnShapeMap {
+		// Map 返回形式按列标签取值，不需要 resultMap 和 DTO
+		resultType = "java.util.Map"
+	} else if proj != nil {
+		resultMapID = methodName + "ResultMap"
+		var err error
+		if resultMapXML, err = renderProjectionResultMap(proj, resultMapID); err != nil {
+			return nil, err
+		}
+	} else {
+		// 映射到实体类时复用 BaseResultMap，命名规则和列覆盖修改的属性名才能正确填充
+		resultMapID = snippetBaseResultMap
+	}
+
 	xmlCode, err := renderTemplate("selectSnippet", selectSnippetTemplate, map[string]interface{}{
-		"MethodName":   methodName,
-		"ModelType":    modelType,
-		"TableName":    tableName,
-		"SelectSQL":    selectSQL,
-		"WhereSQL":     whereSQL,
-		"OrderBySQL":   orderBySQL,
-		"IsBatch":      cfg.IsBatch,
-		"InField":      firstWhereField(cfg.WhereFields),
-		"HasLimit":     cfg.HasLimit,
-		"IsLimitFixed": cfg.IsLimitFixed,
-		"LimitValue":   cfg.LimitValue,
+		"MethodName": methodName,
+		"ModelType":  resultType,
+		"ResultMap":  resultMapID,
+		"TableName":  fromSQL,
+		"SelectSQL":  selectSQL,
+		"WhereSQL":   whereSQL,
+		"GroupBySQL": groupBySQL,
+		"HavingSQL":  havingSQL,
+		"OrderBySQL": orderBySQL,
+		"IsBatch":    cfg.IsBatch,
+		"InField":    firstWhereField(cfg.WhereFields),
+		"PagePrefix": pagePrefix,
+		"PageSuffix": pageSuffix,
 	})
 	if err != nil {
 		return nil, err
 	}
 
+	if resultMapXML != "" {
+		xmlCode = resultMapXML + "\n\n" + xmlCode
+	}
+
+	result := &SnippetResult{
+		MethodName: methodName,
+		JavaCode:   javaBuilder.String(),
+		XMLCode:    xmlCode,
+		Imports:    collectSnippetImports(cfg),
+	}
+	if proj != nil && shape != config.ReturnShapeMap {
+		result.DTO = &SnippetDTO{Name: proj.DTOName, Type: proj.DTOType, MethodName: methodName, Columns: proj.Columns}
+		result.Imports = append(result.Imports, proj.DTOType)
+		sort.Strings(result.Imports)
+	}
+	if err := attachParamClass(result, cfg, modelType); err != nil {
+		return nil, err
+	}
+
+	// 有 LIMIT 的列表查询附带 count 方法，便于调用方构建分页结果
+	if cfg.HasLimit && !cfg.IsBatch && shape == config.ReturnShapeList {
+		if err := appendCountCompanion(result, cfg, methodName, fromSQL, whereSQL, groupBySQL, havingSQL); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// snippetLimitExpr 获取 LIMIT 的 SQL 表达式（固定值或变量参数）
+func snippetLimitExpr(cfg *config.SnippetConfig) string {
+	if cfg.IsLimitFixed {
+		if cfg.LimitValue != "" {
+			return cfg.LimitValue
+		}
+		return "10"
+	}
+	if cfg.LimitValue != "" {
+		return "#{" + cfg.LimitValue + "}"
+	}
+	return "#{limit}"
+}
+
+// appendCountCompanion 为带 LIMIT 的查询追加相同条件的 count 方法（分组查询统计分组数）
+func appendCountCompanion(result *SnippetResult, cfg *config.SnippetConfig, methodName, tableName, whereSQL, groupBySQL, havingSQL string) error {
+	countName := buildCountMethodName(methodName)
+
+	// count 方法不需要 limit 参数
+	countCfg := *cfg
+	countCfg.HasLimit = false
+
+	var javaBuilder strings.Builder
+	javaBuilder.WriteString("    /**\n")
+	javaBuilder.WriteString(fmt.Sprintf("     * 查询总数 - %s（配合 %s 分页使用）\n", countName, methodName))
+	javaBuilder.WriteString("     */\n")
+	javaBuilder.WriteString(fmt.Sprintf("    long %s(%s);\n", countName, buildJavaParams(&countCfg)))
+
+	xmlCode, err := renderTemplate("countSnippet", countSnippetTemplate, map[string]interface{}{
+		"Comment":    "查询总数",
+		"MethodName": countName,
+		"TableName":  tableName,
+		"WhereSQL":   whereSQL,
+		"GroupBySQL": groupBySQL,
+		"HavingSQL":  havingSQL,
+	})
+	if err != nil {
+		return err
+	}
+
+	result.JavaCode += "\n" + javaBuilder.String()
+	result.XMLCode += "\n\n" + xmlCode
+	return nil
+}
+
+// buildCountMethodName 由查询方法名推导 count 方法名（selectByStatus -> countByStatus）
+// count 语句不含排序，末尾的 OrderBy… 段一并去掉（findByNameOrderByIdDesc -> countByName），
+// 紧跟 By 的 OrderBy 属于属性名（selectByOrderByStatus）
+func buildCountMethodName(methodName string) string {
+	for from := 0; from < len(methodName); {
+		idx := indexWord(methodName[from:], "OrderBy")
+		if idx < 0 {
+			break
+		}
+		idx += from
+		if idx > 0 && trimSelectPrefix(methodName[:idx]) != "By" {
+			methodName = methodName[:idx]
+			break
+		}
+		from = idx + 1
+	}
+	rest := trimSelectPrefix(methodName)
+	if rest == "" || rest == "All" {
+		// 避免与基础 Mapper 的 countAll 重名
+		return "count" + capitalize(methodName)
+	}
+	return "count" + capitalize(rest)
+}
+
+// trimSelectPrefix 去掉查询方法名的动词前缀（selectByStatus -> ByStatus）
+func trimSelectPrefix(methodName string) string {
+	for _, prefix := range []string{"select", "find", "query", "list", "get"} {
+		if strings.HasPrefix(methodName, prefix) {
+			return methodName[len(prefix):]
+		}
+	}
+	return methodName
+}
+
+// generateScalarSelectSnippet 生成 count / exists 返回形式的查询（忽略查询列和排序）
+// exists 以 SELECT 1 ... LIMIT 1 探测，外层 COUNT 保证无记录时返回 0 而非 null，可直接映射到 boolean
+func generateScalarSelectSnippet(cfg *config.SnippetConfig, methodName, tableName, groupBySQL, havingSQL string, dialect Dialect) (*SnippetResult, error) {
+	whereSQL := buildWhereClauseSQL(cfg.WhereFields, cfg.WhereLogic)
+	data := map[string]interface{}{
+		"MethodName": methodName,
+		"TableName":  tableName,
+		"WhereSQL":   whereSQL,
+		"GroupBySQL": groupBySQL,
+		"HavingSQL":  havingSQL,
+	}
+
+	var javaBuilder strings.Builder
+	javaBuilder.WriteString("    /**\n")
+	var xmlCode string
+	var err error
+	if snippetReturnShape(cfg) == config.ReturnShapeExists {
+		javaBuilder.WriteString(fmt.Sprintf("     * 是否存在 - %s\n", methodName))
+		data["PagePrefix"], data["PageSuffix"] = dialect.PagingClauses("", "1")
+		xmlCode, err = renderTemplate("existsSnippet", existsSnippetTemplate, data)
+	} else {
+		javaBuilder.WriteString(fmt.Sprintf("     * 自定义计数 - %s\n", methodName))
+		data["Comment"] = "自定义计数"
+		xmlCode, err = renderTemplate("countSnippet", countSnippetTemplate, data)
+	}
+	if err != nil {
+		return nil, err
+	}
+	javaBuilder.WriteString("     */\n")
+	javaBuilder.WriteString(fmt.Sprintf("    %s %s(%s);\n", selectReturnType(cfg, ""), methodName, buildJavaParams(cfg)))
+
 	return &SnippetResult{
-		JavaCode: javaBuilder.String(),
-		XMLCode:  xmlCode,
-		Imports:  collectSnippetImports(cfg),
+		MethodName: methodName,
+		JavaCode:   javaBuilder.String(),
+		XMLCode:    xmlCode,
+		Imports:    collectSnippetImports(cfg),
 	}, nil
 }
 
 func generateInsertSnippet(cfg *config.SnippetConfig, mapperName, modelType, tableName string) (*SnippetResult, error) {
-	methodName := cfg.MethodName
-	if methodName == "" {
-		if cfg.IsBatch {
-			methodName = "insertBatchByFields"
-		} else {
-			methodName = "insertByFields"
-		}
-	}
+	methodName := SnippetMethodName(cfg)
 	simpleModel := lastPart(modelType)
 
 	// ---- Java 代码 ----
@@ -191,13 +436,13 @@ func generateInsertSnippet(cfg *config.SnippetConfig, mapperName, modelType, tab
 		return nil, err
 	}
 
-	return &SnippetResult{JavaCode: javaBuilder.String(), XMLCode: xmlCode, Imports: collectSnippetImports(cfg)}, nil
+	return &SnippetResult{MethodName: methodName, JavaCode: javaBuilder.String(), XMLCode: xmlCode, Imports: collectSnippetImports(cfg)}, nil
 }
 
 func generateDeleteSnippet(cfg *config.SnippetConfig, mapperName, modelType, tableName string) (*SnippetResult, error) {
-	methodName := cfg.MethodName
-	if methodName == "" {
-		methodName = buildDeleteMethodName(cfg)
+	methodName := SnippetMethodName(cfg)
+	if usesParamObject(cfg) {
+		cfg = withParamClassName(cfg, methodName)
 	}
 
 	// ---- Java 代码 ----
@@ -224,7 +469,10 @@ func generateDeleteSnippet(cfg *config.SnippetConfig, mapperName, modelType, tab
 		javaBuilder.WriteString(fmt.Sprintf("    int %s(%s);\n", methodName, params))
 	}
 
-	whereSQL := buildWhereSQL(cfg.WhereFields, cfg.WhereLogic)
+	if !cfg.IsBatch && !hasRequiredCondition(cfg.WhereFields) {
+		return nil, fmt.Errorf("删除片段至少需要一个非可选的WHERE条件")
+	}
+	whereSQL := buildWhereClauseSQL(cfg.WhereFields, cfg.WhereLogic)
 
 	// ---- XML 代码 ----
 	xmlCode, err := renderTemplate("deleteSnippet", deleteSnippetTemplate, map[string]interface{}{
@@ -238,14 +486,15 @@ func generateDeleteSnippet(cfg *config.SnippetConfig, mapperName, modelType, tab
 		return nil, err
 	}
 
-	return &SnippetResult{JavaCode: javaBuilder.String(), XMLCode: xmlCode, Imports: collectSnippetImports(cfg)}, nil
+	result := &SnippetResult{MethodName: methodName, JavaCode: javaBuilder.String(), XMLCode: xmlCode, Imports: collectSnippetImports(cfg)}
+	if err := attachParamClass(result, cfg, modelType); err != nil {
+		return nil, err
+	}
+	return result, nil
 }
 
 func generateUpdateSnippet(cfg *config.SnippetConfig, mapperName, modelType, tableName string) (*SnippetResult, error) {
-	methodName := cfg.MethodName
-	if methodName == "" {
-		methodName = buildUpdateMethodName(cfg)
-	}
+	methodName := SnippetMethodName(cfg)
 	simpleModel := lastPart(modelType)
 
 	// ---- Java 代码 ----
@@ -271,7 +520,10 @@ func generateUpdateSnippet(cfg *config.SnippetConfig, mapperName, modelType, tab
 	if cfg.IsBatch {
 		whereSQL = buildBatchWhereSQL(cfg.WhereFields)
 	} else {
-		whereSQL = buildWhereSQL(cfg.WhereFields, cfg.WhereLogic)
+		if !hasRequiredCondition(cfg.WhereFields) {
+			return nil, fmt.Errorf("更新片段至少需要一个非可选的WHERE条件")
+		}
+		whereSQL = buildWhereClauseSQL(cfg.WhereFields, cfg.WhereLogic)
 	}
 
 	// ---- XML 代码 ----
@@ -287,7 +539,7 @@ func generateUpdateSnippet(cfg *config.SnippetConfig, mapperName, modelType, tab
 		return nil, err
 	}
 
-	return &SnippetResult{JavaCode: javaBuilder.String(), XMLCode: xmlCode, Imports: collectSnippetImports(cfg)}, nil
+	return &SnippetResult{MethodName: methodName, JavaCode: javaBuilder.String(), XMLCode: xmlCode, Imports: collectSnippetImports(cfg)}, nil
 }
 
 // -----------------------------------------------------------------------
@@ -309,6 +561,78 @@ func buildWhereSQL(fields []config.SnippetField, logic string) string {
 	return strings.Join(clauses, "\n        "+logic+" ")
 }
 
+// buildWhereClauseSQL 生成完整的 WHERE 子句（含 WHERE 关键字）
+// 含可选条件时使用 <where> 元素，可选条件包在 <if> 中，参数为空时忽略
+func buildWhereClauseSQL(fields []config.SnippetField, logic string) string {
+	if len(fields) == 0 {
+		return ""
+	}
+	hasOptional := false
+	for _, f := range fields {
+		if isOptionalCondition(f) {
+			hasOptional = true
+			break
+		}
+	}
+	if !hasOptional {
+		return "WHERE " + buildWhereSQL(fields, logic)
+	}
+	if logic == "" {
+		logic = "AND"
+	}
+
+	var b strings.Builder
+	b.WriteString("<where>")
+	for _, f := range fields {
+		clause := buildWhereClause(f, false)
+		if isOptionalCondition(f) {
+			b.WriteString(fmt.Sprintf("\n            <if test=\"%s\">\n                %s %s\n            </if>",
+				optionalConditionTest(f), logic, indentFollowingLines(clause, "        ")))
+		} else {
+			b.WriteString("\n            " + logic + " " + indentFollowingLines(clause, "    "))
+		}
+	}
+	b.WriteString("\n        </where>")
+	return b.String()
+}
+
+// isOptionalCondition 判断条件是否为可选（固定值和空值判断始终生效）
+func isOptionalCondition(f config.SnippetField) bool {
+	return f.Optional && !f.IsFixed && f.Operator != "IS NULL" && f.Operator != "IS NOT NULL"
+}
+
+// hasRequiredCondition 判断是否至少有一个始终生效的条件（避免删除/更新全表）
+func hasRequiredCondition(fields []config.SnippetField) bool {
+	for _, f := range fields {
+		if !isOptionalCondition(f) {
+			return true
+		}
+	}
+	return false
+}
+
+// isCollectionOperator 判断运算符是否需要集合参数
+func isCollectionOperator(op string) bool {
+	return op == "IN" || op == "NOT IN"
+}
+
+// optionalConditionTest 可选条件的 <if> 判断表达式（集合判空，字符串判空串）
+func optionalConditionTest(f config.SnippetField) string {
+	switch {
+	case isCollectionOperator(f.Operator):
+		return fmt.Sprintf("%s != null and %s.size() > 0", f.FieldName, f.FieldName)
+	case f.JavaType == "String":
+		return fmt.Sprintf("%s != null and %s != ''", f.FieldName, f.FieldName)
+	default:
+		return f.FieldName + " != null"
+	}
+}
+
+// indentFollowingLines 为多行片段除首行外的各行增加缩进
+func indentFollowingLines(s, indent string) string {
+	return strings.ReplaceAll(s, "\n", "\n"+indent)
+}
+
 // buildBatchWhereSQL 批量操作的WHERE子句，使用 item. 前缀且运算符固定为 =
 func buildBatchWhereSQL(fields []config.SnippetField) string {
 	if len(fields) == 0 {
@@ -346,8 +670,18 @@ func buildWhereClause(f config.SnippetField, batchMode bool) string {
 		if f.IsFixed {
 			return fmt.Sprintf("%s %s (%s)", f.ColumnName, op, f.FixedValue)
 		}
-		return fmt.Sprintf("%s %s\n        <foreach collection=\"%s\" item=\"item\" open=\"(\" separator=\",\" close=\")\">\n            #{item,jdbcType=%s}\n        </foreach>",
+		inClause := fmt.Sprintf("%s %s\n        <foreach collection=\"%s\" item=\"item\" open=\"(\" separator=\",\" close=\")\">\n            #{item,jdbcType=%s}\n        </foreach>",
 			f.ColumnName, op, f.FieldName, f.JdbcType)
+		if f.Optional {
+			return inClause
+		}
+		// 空集合时 IN 恒为假、NOT IN 恒为真，避免生成 IN () 语法错误
+		otherwise := "1 = 0"
+		if op == "NOT IN" {
+			otherwise = "1 = 1"
+		}
+		return fmt.Sprintf("<choose>\n        <when test=\"%s != null and %s.size() > 0\">\n            %s\n        </when>\n        <otherwise>%s</otherwise>\n        </choose>",
+			f.FieldName, f.FieldName, indentFollowingLines(inClause, "    "), otherwise)
 	default:
 		if f.IsFixed {
 			// 固定值直接内嵌，字符串类型需加引号
@@ -355,13 +689,18 @@ func buildWhereClause(f config.SnippetField, batchMode bool) string {
 			if f.JdbcType == "VARCHAR" || f.JdbcType == "CHAR" || f.JdbcType == "TEXT" {
 				val = "'" + val + "'"
 			}
-			return fmt.Sprintf("%s %s %s", f.ColumnName, op, val)
+			return fmt.Sprintf("%s %s %s", f.ColumnName, xmlOperator(op), val)
 		}
 		return fmt.Sprintf("%s %s #{%s%s,jdbcType=%s}",
-			f.ColumnName, op, prefix, f.FieldName, f.JdbcType)
+			f.ColumnName, xmlOperator(op), prefix, f.FieldName, f.JdbcType)
 	}
 }
 
+// xmlOperator 转义比较运算符中的 <，保证生成的 XML 合法
+func xmlOperator(op string) string {
+	return strings.ReplaceAll(op, "<", "&lt;")
+}
+
 // buildSelectSQL 构建 SELECT 字段部分（处理聚合函数和别名）
 func buildSelectSQL(fields []config.SnippetField) string {
 	if len(fields) == 0 {
@@ -369,10 +708,7 @@ func buildSelectSQL(fields []config.SnippetField) string {
 	}
 	parts := make([]string, len(fields))
 	for i, f := range fields {
-		fieldSQL := f.ColumnName
-		if f.Aggregate != "" {
-			fieldSQL = fmt.Sprintf("%s(%s)", strings.ToUpper(f.Aggregate), fieldSQL)
-		}
+		fieldSQL := aggregateExpr(f.Aggregate, f.ColumnName)
 		if f.Alias != "" {
 			fieldSQL = fmt.Sprintf("%s AS %s", fieldSQL, f.Alias)
 		}
@@ -410,6 +746,26 @@ func firstWhereField(fields []config.SnippetField) *config.SnippetField {
 // -----------------------------------------------------------------------
 
 func buildSelectMethodName(cfg *config.SnippetConfig) string {
+	name := buildSelectByMethodName(cfg)
+	if len(cfg.GroupByFields) == 0 {
+		return name
+	}
+	// 分组查询追加 GroupByXxx
+	if name == "selectByFields" || name == "selectAll" {
+		name = "select"
+	}
+	parts := make([]string, len(cfg.GroupByFields))
+	for i, f := range cfg.GroupByFields {
+		field := f.FieldName
+		if field == "" {
+			field = snakeToCamel(f.ColumnName)
+		}
+		parts[i] = capitalize(field)
+	}
+	return name + "GroupBy" + strings.Join(parts, "And")
+}
+
+func buildSelectByMethodName(cfg *config.SnippetConfig) string {
 	// IS NULL / IS NOT NULL 字段不计入方法名（无参数）
 	effective := effectiveWhereFields(cfg.WhereFields)
 	if len(effective) == 0 {
@@ -500,8 +856,13 @@ func effectiveWhereFields(fields []config.SnippetField) []config.SnippetField {
 // -----------------------------------------------------------------------
 
 func buildJavaParams(cfg *config.SnippetConfig) string {
+	// 参数对象模式：XML 中的 #{xxx} 直接引用对象属性
+	if usesParamObject(cfg) {
+		return cfg.ParamClassName + " query"
+	}
+
 	// 过滤掉 IS NULL / IS NOT NULL（无需Java参数）
-	effective := effectiveWhereFields(cfg.WhereFields)
+	effective := snippetParamFields(cfg)
 	var parts []string
 
 	// 处理 Limit 参数
@@ -523,7 +884,7 @@ func buildJavaParams(cfg *config.SnippetConfig) string {
 	}
 
 	// 如果只有一个 Where 条件且没有 Limit，不使用 @Param
-	if len(effective) == 1 && len(parts) == 1 {
+	if !snippetParamsAnnotated(cfg) && len(parts) == 1 {
 		f := effective[0]
 		javaType := f.JavaType
 		if f.Operator == "IN" || f.Operator == "NOT IN" {
@@ -535,20 +896,55 @@ func buildJavaParams(cfg *config.SnippetConfig) string {
 	return strings.Join(parts, ", ")
 }
 
+// snippetParamsAnnotated 判断方法参数是否使用 @Param
+// 单个参数时省略；但 <if>/<choose> 中按名称引用参数时必须保留
+func snippetParamsAnnotated(cfg *config.SnippetConfig) bool {
+	if usesParamObject(cfg) {
+		return false
+	}
+	effective := snippetParamFields(cfg)
+	if cfg.Operation == config.OperationSelect && cfg.HasLimit && !cfg.IsLimitFixed {
+		return true
+	}
+	if len(effective) != 1 {
+		return len(effective) > 1
+	}
+	f := effective[0]
+	return !f.IsFixed && (f.Optional || isCollectionOperator(f.Operator))
+}
+
+// snippetParamFields 需要 Java 参数的条件字段（WHERE 与 HAVING，空值判断除外）
+func snippetParamFields(cfg *config.SnippetConfig) []config.SnippetField {
+	return append(effectiveWhereFields(cfg.WhereFields), effectiveWhereFields(cfg.HavingFields)...)
+}
+
 // collectSnippetImports 根据片段配置收集需要的 import
 func collectSnippetImports(cfg *config.SnippetConfig) []string {
 	importsMap := make(map[string]bool)
-	effective := effectiveWhereFields(cfg.WhereFields)
+	effective := snippetParamFields(cfg)
+	if usesParamObject(cfg) {
+		effective = nil // 集合参数位于参数类中，Mapper 方法签名不再引用 List
+	}
 
 	switch cfg.Operation {
 	case config.OperationSelect:
-		importsMap["java.util.List"] = true // 返回类型始终是 List
+		switch snippetReturnShape(cfg) {
+		case config.ReturnShapeList:
+			importsMap["java.util.List"] = true
+		case config.ReturnShapeOptional:
+			importsMap["java.util.Optional"] = true
+		case config.ReturnShapeMap:
+			importsMap["java.util.Map"] = true
+		case config.ReturnShapeMapKey:
+			importsMap["java.util.Map"] = true
+			importsMap["org.apache.ibatis.annotations.MapKey"] = true
+		}
 		if cfg.IsBatch {
+			importsMap["java.util.List"] = true
 			importsMap["org.apache.ibatis.annotations.Param"] = true
 		} else {
-			hasLimitParam := cfg.HasLimit && !cfg.IsLimitFixed
-			// @Param: 有 limit 参数 或 effective 字段 > 1
-			if hasLimitParam || len(effective) > 1 {
+			// @Param: 有 limit 参数、多个参数或参数需在动态 SQL 中按名称引用
+			if snippetParamsAnnotated(cfg) {
 				importsMap["org.apache.ibatis.annotations.Param"] = true
 			}
 			for _, f := range effective {
@@ -567,7 +963,7 @@ func collectSnippetImports(cfg *config.SnippetConfig) []string {
 			importsMap["java.util.List"] = true
 			importsMap["org.apache.ibatis.annotations.Param"] = true
 		} else {
-			if len(effective) > 1 {
+			if snippetParamsAnnotated(cfg) {
 				importsMap["org.apache.ibatis.annotations.Param"] = true
 			}
 			for _, f := range effective {
@@ -592,6 +988,25 @@ func collectSnippetImports(cfg *config.SnippetConfig) []string {
 		}
 	}
 
+	// 方法签名引用的参数类型（insert / update 以实体为参数，参数对象模式的类型位于参数类中）
+	var paramTypes []string
+	if cfg.Operation == config.OperationSelect || cfg.Operation == config.OperationDelete {
+		if cfg.IsBatch {
+			if f := firstWhereField(cfg.WhereFields); f != nil {
+				paramTypes = append(paramTypes, f.JavaType)
+			}
+		}
+		for _, f := range effective {
+			paramTypes = append(paramTypes, f.JavaType)
+		}
+	}
+	if cfg.Operation == config.OperationSelect && snippetReturnShape(cfg) == config.ReturnShapeMapKey && cfg.MapKey != nil {
+		paramTypes = append(paramTypes, cfg.MapKey.JavaType)
+	}
+	for _, imp := range javaTypeImports(paramTypes) {
+		importsMap[imp] = true
+	}
+
 	result := make([]string, 0, len(importsMap))
 	for imp := range importsMap {
 		result = append(result, imp)
@@ -614,7 +1029,8 @@ func capitalize(s string) string {
 
 func renderTemplate(name, tmplStr string, data interface{}) (string, error) {
 	funcMap := template.FuncMap{
-		"last": func(i int, arr interface{}) bool { return false }, // placeholder
+		"last":       func(i int, arr interface{}) bool { return false }, // placeholder
+		"capitalize": capitalize,
 	}
 	tmpl, err := template.New(name).Funcs(funcMap).Parse(tmplStr)
 	if err != nil {
@@ -628,11 +1044,15 @@ func renderTemplate(name, tmplStr string, data interface{}) (string, error) {
 }
 
 // -----------------------------------------------------------------------
-// XML 模板（使用预计算的 WhereSQL / OrderBySQL 字符串，避免模板内复杂逻辑）
+// XML 模板（使用预计算的 WhereSQL / OrderBySQL 字符串，避免模板内复杂逻辑；
+// 单条操作的 WhereSQL 含 WHERE 关键字或 <where> 元素，批量更新的不含关键字）
 // -----------------------------------------------------------------------
 
 const selectSnippetTemplate = `    <!-- 自定义查询 - {{.MethodName}} -->
-    <select id="{{.MethodName}}" resultType="{{.ModelType}}">
+    <select id="{{.MethodName}}" {{if .ResultMap}}resultMap="{{.ResultMap}}"{{else}}resultType="{{.ModelType}}"{{end}}>
+{{- if .PagePrefix}}
+        {{.PagePrefix}}
+{{- end}}
         SELECT {{.SelectSQL}}
         FROM {{.TableName}}
 {{- if .IsBatch}}
@@ -641,18 +1061,68 @@ const selectSnippetTemplate = `    <!-- 自定义查询 - {{.MethodName}} -->
             #{item}
         </foreach>
 {{- else if .WhereSQL}}
-        WHERE {{.WhereSQL}}
+        {{.WhereSQL}}
+{{- end}}
+{{- if .GroupBySQL}}
+        {{.GroupBySQL}}
+{{- end}}
+{{- if .HavingSQL}}
+        {{.HavingSQL}}
 {{- end}}
 {{- if .OrderBySQL}}
         {{.OrderBySQL}}
 {{- end}}
-{{- if .HasLimit}}
-    {{- if .IsLimitFixed}}
-        LIMIT {{if .LimitValue}}{{.LimitValue}}{{else}}10{{end}}
-    {{- else}}
-        LIMIT #{{print "{"}}{{if .LimitValue}}{{.LimitValue}}{{else}}limit{{end}}{{print "}"}}
-    {{- end}}
+{{- if .PageSuffix}}
+        {{.PageSuffix}}
+{{- end}}
+    </select>`
+
+const countSnippetTemplate = `    <!-- {{.Comment}} - {{.MethodName}} -->
+    <select id="{{.MethodName}}" resultType="java.lang.Long">
+{{- if or .GroupBySQL .HavingSQL}}
+        SELECT COUNT(*) FROM (
+        SELECT 1
+        FROM {{.TableName}}
+{{- if .WhereSQL}}
+        {{.WhereSQL}}
+{{- end}}
+{{- if .GroupBySQL}}
+        {{.GroupBySQL}}
+{{- end}}
+{{- if .HavingSQL}}
+        {{.HavingSQL}}
+{{- end}}
+        ) g
+{{- else}}
+        SELECT COUNT(*)
+        FROM {{.TableName}}
+{{- if .WhereSQL}}
+        {{.WhereSQL}}
+{{- end}}
+{{- end}}
+    </select>`
+
+const existsSnippetTemplate = `    <!-- 是否存在 - {{.MethodName}} -->
+    <select id="{{.MethodName}}" resultType="java.lang.Boolean">
+        SELECT COUNT(*) FROM (
+{{- if .PagePrefix}}
+        {{.PagePrefix}}
+{{- end}}
+        SELECT 1
+        FROM {{.TableName}}
+{{- if .WhereSQL}}
+        {{.WhereSQL}}
+{{- end}}
+{{- if .GroupBySQL}}
+        {{.GroupBySQL}}
+{{- end}}
+{{- if .HavingSQL}}
+        {{.HavingSQL}}
+{{- end}}
+{{- if .PageSuffix}}
+        {{.PageSuffix}}
 {{- end}}
+        ) e
     </select>`
 
 const insertSnippetTemplate = `    <!-- 自定义插入 - {{.MethodName}} -->
@@ -686,7 +1156,7 @@ const deleteSnippetTemplate = `    <!-- 自定义删除 - {{.MethodName}} -->
             #{item}
         </foreach>
 {{- else if .WhereSQL}}
-        WHERE {{.WhereSQL}}
+        {{.WhereSQL}}
 {{- end}}
     </delete>`
 
@@ -709,6 +1179,6 @@ const updateSnippetTemplate = `    <!-- 自定义更新 - {{.MethodName}} -->
             {{range $i, $f := .SetFields}}{{$f.ColumnName}} = #{{"{"}}{{$f.FieldName}},jdbcType={{$f.JdbcType}}{{"}"}},
             {{end}}
         </set>
-        WHERE {{.WhereSQL}}
+        {{.WhereSQL}}
     </update>
 {{- end}}`
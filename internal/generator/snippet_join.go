@@ -0,0 +1,70 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/yourusername/mybatis-generator-gui-go/internal/config"
+)
+
+// defaultMainAlias 关联查询时主表的默认别名
+const defaultMainAlias = "t"
+
+// snippetMainAlias 获取主表别名
+func snippetMainAlias(cfg *config.SnippetConfig) string {
+	if cfg.TableAlias != "" {
+		return cfg.TableAlias
+	}
+	return defaultMainAlias
+}
+
+// fieldTableAlias 获取字段所属表的别名（空则为主表）
+func fieldTableAlias(cfg *config.SnippetConfig, alias string) string {
+	if alias == "" {
+		return snippetMainAlias(cfg)
+	}
+	return alias
+}
+
+// buildJoinFrom 校验关联配置并生成 FROM 之后的表及 JOIN 子句，同时返回已定义的别名集合
+func buildJoinFrom(cfg *config.SnippetConfig, tableName string) (string, map[string]bool, error) {
+	mainAlias := snippetMainAlias(cfg)
+	aliases := map[string]bool{mainAlias: true}
+
+	var from strings.Builder
+	from.WriteString(tableName + " " + mainAlias)
+	for i, join := range cfg.Joins {
+		if join.TableName == "" || join.Alias == "" {
+			return "", nil, fmt.Errorf("关联表%d缺少表名或别名", i+1)
+		}
+		if aliases[join.Alias] {
+			return "", nil, fmt.Errorf("关联表别名重复: %s", join.Alias)
+		}
+		joinType := strings.ToUpper(strings.TrimSpace(join.JoinType))
+		switch joinType {
+		case "":
+			joinType = "LEFT"
+		case "INNER", "LEFT", "RIGHT":
+		default:
+			return "", nil, fmt.Errorf("不支持的关联类型: %s", join.JoinType)
+		}
+		if len(join.On) == 0 {
+			return "", nil, fmt.Errorf("关联表 %s 缺少关联条件", join.TableName)
+		}
+
+		conditions := make([]string, len(join.On))
+		for j, on := range join.On {
+			left := fieldTableAlias(cfg, on.LeftAlias)
+			if !aliases[left] {
+				return "", nil, fmt.Errorf("关联条件引用了未定义的别名: %s", left)
+			}
+			if on.LeftColumn == "" || on.RightColumn == "" {
+				return "", nil, fmt.Errorf("关联表 %s 的关联条件%d不完整", join.TableName, j+1)
+			}
+			conditions[j] = fmt.Sprintf("%s.%s = %s.%s", left, on.LeftColumn, join.Alias, on.RightColumn)
+		}
+		aliases[join.Alias] = true
+		from.WriteString(fmt.Sprintf("\n        %s JOIN %s %s ON %s", joinType, join.TableName, join.Alias, strings.Join(conditions, " AND ")))
+	}
+	return from.String(), aliases, nil
+}
@@ -0,0 +1,55 @@
+package generator
+
+import (
+	"sort"
+	"strings"
+)
+
+// MergeSnippetsIntoMapper 将片段合并到 Mapper 接口和 XML 内容中，返回合并后的内容
+// 缺失的 import 去重后注入 import 块，方法声明追加到接口体末尾，语句追加到 </mapper> 前
+// merges 需先经 ResolveSnippetConflicts 检测重名
+func MergeSnippetsIntoMapper(javaContent, xmlContent string, merges []*SnippetMerge) (string, string) {
+	if len(merges) == 0 {
+		return javaContent, xmlContent
+	}
+
+	var javaCodes, xmlCodes []string
+	importsSet := make(map[string]bool)
+	for _, merge := range merges {
+		javaCodes = append(javaCodes, merge.Result.JavaCode)
+		xmlCodes = append(xmlCodes, merge.Result.XMLCode)
+		for _, imp := range merge.Result.Imports {
+			importsSet[imp] = true
+		}
+	}
+	var imports []string
+	for imp := range importsSet {
+		imports = append(imports, imp)
+	}
+	sort.Strings(imports)
+
+	// 先注入缺失的 import，再追加方法声明
+	javaContent = AppendImportsToJava(javaContent, imports)
+	javaContent = AppendSnippetToJava(javaContent, strings.Join(javaCodes, "\n"))
+	xmlContent = AppendSnippetToXML(xmlContent, strings.Join(xmlCodes, "\n\n"))
+	return javaContent, xmlContent
+}
+
+// JavaTypeName 返回 Java 源码中第一个顶层类型（类/接口）的名称，未找到返回空串
+func JavaTypeName(javaContent string) string {
+	return locateJava(javaContent).TypeName
+}
+
+// MapperModelType 返回 Mapper XML 中第一个 <resultMap> 的 type（通常为实体类全名），未找到返回空串
+func MapperModelType(xmlContent string) string {
+	root, err := parseMapperXML(xmlContent)
+	if err != nil {
+		return ""
+	}
+	for _, child := range root.Children {
+		if child.Name == "resultMap" {
+			return child.Attrs["type"]
+		}
+	}
+	return ""
+}
@@ -0,0 +1,450 @@
+package generator
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/yourusername/mybatis-generator-gui-go/internal/config"
+	"github.com/yourusername/mybatis-generator-gui-go/internal/database"
+	"github.com/yourusername/mybatis-generator-gui-go/internal/utils"
+)
+
+// methodPrefix 方法名前缀及对应的操作
+type methodPrefix struct {
+	Prefix    string
+	Operation config.SnippetOperation
+	Shape     config.SnippetReturnShape // count / exists 前缀对应的返回形式，其余为空
+}
+
+// methodPrefixes 支持的方法名前缀（按 Spring Data 命名规则）
+var methodPrefixes = []methodPrefix{
+	{"find", config.OperationSelect, ""},
+	{"select", config.OperationSelect, ""},
+	{"get", config.OperationSelect, ""},
+	{"query", config.OperationSelect, ""},
+	{"read", config.OperationSelect, ""},
+	{"list", config.OperationSelect, ""},
+	{"search", config.OperationSelect, ""},
+	{"count", config.OperationSelect, config.ReturnShapeCount},
+	{"exists", config.OperationSelect, config.ReturnShapeExists},
+	{"delete", config.OperationDelete, ""},
+	{"remove", config.OperationDelete, ""},
+}
+
+// methodOperatorKeywords 条件关键字 -> WHERE 运算符
+var methodOperatorKeywords = map[string]string{
+	"":                   "=",
+	"Is":                 "=",
+	"Equals":             "=",
+	"Not":                "!=",
+	"IsNot":              "!=",
+	"GreaterThan":        ">",
+	"IsGreaterThan":      ">",
+	"After":              ">",
+	"IsAfter":            ">",
+	"GreaterThanEqual":   ">=",
+	"IsGreaterThanEqual": ">=",
+	"LessThan":           "<",
+	"IsLessThan":         "<",
+	"Before":             "<",
+	"IsBefore":           "<",
+	"LessThanEqual":      "<=",
+	"IsLessThanEqual":    "<=",
+	"Like":               "LIKE",
+	"IsLike":             "LIKE",
+	"Containing":         "LIKE",
+	"IsContaining":       "LIKE",
+	"Contains":           "LIKE",
+	"In":                 "IN",
+	"IsIn":               "IN",
+	"NotIn":              "NOT IN",
+	"IsNotIn":            "NOT IN",
+	"Null":               "IS NULL",
+	"IsNull":             "IS NULL",
+	"NotNull":            "IS NOT NULL",
+	"IsNotNull":          "IS NOT NULL",
+}
+
+// methodUnsupportedKeywords Spring Data 支持但片段模型无法表达的关键字
+var methodUnsupportedKeywords = []string{
+	"Between", "IsBetween", "StartingWith", "IsStartingWith", "StartsWith",
+	"EndingWith", "IsEndingWith", "EndsWith", "NotLike", "IsNotLike",
+	"NotContaining", "True", "IsTrue", "False", "IsFalse",
+	"IgnoreCase", "IgnoringCase", "AllIgnoreCase", "Regex", "Matches", "Exists",
+}
+
+// methodLimitPattern 匹配紧跟动词、位于 By 之前的 First/Top 限制条数（findFirst10By、findTop3By）
+// 主语中其他位置的 First/Top 不视为关键字（findTopicsBy）
+var methodLimitPattern = regexp.MustCompile(`^(First|Top)(\d*)$`)
+
+// ParseSnippetMethodName 按 Spring Data 命名规则解析方法名，生成片段配置
+// 例如 findByStatusAndCreatedAtGreaterThanOrderByIdDesc、countByUserId、existsByUserName、deleteByExpireTimeLessThan
+// 属性名通过 utils.CamelCaseToDBString 与表的列名匹配（忽略大小写）
+func ParseSnippetMethodName(methodName string, columns []*database.TableColumn) (*config.SnippetConfig, error) {
+	methodName = strings.TrimSpace(methodName)
+	if methodName == "" {
+		return nil, fmt.Errorf("方法名不能为空")
+	}
+
+	var prefix *methodPrefix
+	for i := range methodPrefixes {
+		p := &methodPrefixes[i]
+		if strings.HasPrefix(methodName, p.Prefix) && startsWord(methodName[len(p.Prefix):]) {
+			prefix = p
+			break
+		}
+	}
+	if prefix == nil {
+		return nil, fmt.Errorf("无法识别的方法前缀: %s（支持 find/select/get/query/read/list/search/count/exists/delete/remove）", methodName)
+	}
+
+	p := newMethodNameParser(columns)
+	cfg := &config.SnippetConfig{
+		MethodName:  methodName,
+		Operation:   prefix.Operation,
+		WhereLogic:  "AND",
+		ReturnShape: prefix.Shape,
+	}
+
+	// 拆分主语（By 之前）、条件和排序部分
+	rest := methodName[len(prefix.Prefix):]
+	subject, criteria, orderBy := rest, "", ""
+	hasBy := false
+	if idx := indexWord(rest, "By"); idx >= 0 {
+		if strings.HasSuffix(rest[:idx], "Order") {
+			subject, orderBy = rest[:idx-len("Order")], rest[idx+len("By"):]
+		} else {
+			subject, criteria = rest[:idx], rest[idx+len("By"):]
+			hasBy = true
+		}
+	}
+
+	if err := p.parseSubject(cfg, subject, prefix); err != nil {
+		return nil, err
+	}
+
+	if hasBy {
+		if criteria == "" {
+			return nil, fmt.Errorf("By 之后缺少条件属性")
+		}
+		var err error
+		if criteria, orderBy, err = p.splitOrderBy(criteria); err != nil {
+			return nil, err
+		}
+		if cfg.WhereFields, cfg.WhereLogic, err = p.parseCriteria(criteria); err != nil {
+			return nil, err
+		}
+	}
+
+	if orderBy != "" {
+		if prefix.Operation != config.OperationSelect || prefix.Shape != "" {
+			return nil, fmt.Errorf("%s 方法不支持 OrderBy", prefix.Prefix)
+		}
+		fields, err := p.parseOrderBy(orderBy)
+		if err != nil {
+			return nil, err
+		}
+		cfg.OrderByFields = fields
+	}
+
+	if prefix.Operation == config.OperationDelete && len(cfg.WhereFields) == 0 {
+		return nil, fmt.Errorf("删除方法必须包含 By 条件")
+	}
+	return cfg, nil
+}
+
+// methodNameParser 方法名解析状态
+type methodNameParser struct {
+	columns    map[string]*database.TableColumn // 小写列名 -> 列
+	compact    map[string]*database.TableColumn // 去下划线的小写列名 -> 列
+	paramNames map[string]int                   // 已使用的参数名（重复时追加序号）
+}
+
+func newMethodNameParser(columns []*database.TableColumn) *methodNameParser {
+	p := &methodNameParser{
+		columns:    make(map[string]*database.TableColumn, len(columns)),
+		compact:    make(map[string]*database.TableColumn, len(columns)),
+		paramNames: make(map[string]int),
+	}
+	for _, col := range columns {
+		name := strings.ToLower(col.ColumnName)
+		p.columns[name] = col
+		p.compact[strings.ReplaceAll(name, "_", "")] = col
+	}
+	return p
+}
+
+// lookupColumn 将属性名（如 CreatedAt）解析为表列
+func (p *methodNameParser) lookupColumn(property string) *database.TableColumn {
+	name := strings.ToLower(utils.CamelCaseToDBString(utils.FirstLower(property)))
+	if col, ok := p.columns[name]; ok {
+		return col
+	}
+	return p.compact[strings.ToLower(property)]
+}
+
+// parseSubject 解析主语部分：First/Top 限制条数，不支持 Distinct
+// 不带数字的 First/Top 只取一条，返回单个对象
+func (p *methodNameParser) parseSubject(cfg *config.SnippetConfig, subject string, prefix *methodPrefix) error {
+	if strings.Contains(subject, "Distinct") {
+		return fmt.Errorf("不支持的关键字: Distinct")
+	}
+
+	m := methodLimitPattern.FindStringSubmatch(subject)
+	if m == nil {
+		return nil
+	}
+	if prefix.Operation != config.OperationSelect || prefix.Shape != "" {
+		return fmt.Errorf("%s 方法不支持 %s", prefix.Prefix, m[1])
+	}
+	limit := 1
+	if m[2] != "" {
+		n, err := strconv.Atoi(m[2])
+		if err != nil || n <= 0 {
+			return fmt.Errorf("无效的限制条数: %s%s", m[1], m[2])
+		}
+		limit = n
+	} else {
+		cfg.ReturnShape = config.ReturnShapeOne
+	}
+	cfg.HasLimit = true
+	cfg.IsLimitFixed = true
+	cfg.LimitValue = strconv.Itoa(limit)
+	return nil
+}
+
+// splitOrderBy 拆分条件与排序部分；属性名中可能包含 OrderBy，依次尝试直到两部分都能解析
+func (p *methodNameParser) splitOrderBy(s string) (string, string, error) {
+	var firstErr error
+	for from := 0; ; {
+		idx := indexWord(s[from:], "OrderBy")
+		if idx < 0 {
+			break
+		}
+		idx += from
+		criteria, orderBy := s[:idx], s[idx+len("OrderBy"):]
+		_, _, err := p.parseCriteria(criteria)
+		if err == nil {
+			_, err = p.parseOrderBy(orderBy)
+		}
+		if err == nil {
+			return criteria, orderBy, nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+		from = idx + 1
+	}
+	if _, _, err := p.parseCriteria(s); err != nil && firstErr != nil {
+		return "", "", firstErr
+	}
+	return s, "", nil
+}
+
+// parseCriteria 解析 And/Or 连接的条件列表
+func (p *methodNameParser) parseCriteria(s string) ([]config.SnippetField, string, error) {
+	p.paramNames = make(map[string]int)
+	var fields []config.SnippetField
+	logic := ""
+	for pos := 0; pos < len(s); {
+		field, next, err := p.parseCondition(s, pos)
+		if err != nil {
+			return nil, "", err
+		}
+		fields = append(fields, field)
+		pos = next
+		if pos >= len(s) {
+			break
+		}
+		connector := "And"
+		if strings.HasPrefix(s[pos:], "Or") {
+			connector = "Or"
+		}
+		if logic != "" && !strings.EqualFold(logic, connector) {
+			return nil, "", fmt.Errorf("不支持 And 与 Or 混合使用")
+		}
+		logic = strings.ToUpper(connector)
+		pos += len(connector)
+		if pos >= len(s) {
+			return nil, "", fmt.Errorf("%s 之后缺少条件属性", connector)
+		}
+	}
+	if logic == "" {
+		logic = "AND"
+	}
+	return fields, logic, nil
+}
+
+// parseCondition 从 pos 开始解析一个条件（属性 + 可选关键字），返回条件及下一个连接词的位置
+// 属性按最长匹配，关键字之后必须是结尾或 And/Or
+func (p *methodNameParser) parseCondition(s string, pos int) (config.SnippetField, int, error) {
+	keywords := sortedOperatorKeywords()
+	var matched *database.TableColumn
+	var matchedEnd int
+	for _, end := range wordBoundaries(s, pos) {
+		col := p.lookupColumn(s[pos:end])
+		if col == nil {
+			continue
+		}
+		if matched == nil {
+			matched, matchedEnd = col, end
+		}
+		for _, kw := range keywords {
+			if !strings.HasPrefix(s[end:], kw) || !atConnector(s[end+len(kw):]) {
+				continue
+			}
+			return p.newWhereField(col, methodOperatorKeywords[kw]), end + len(kw), nil
+		}
+	}
+
+	if matched == nil {
+		segment := s[pos:]
+		if idx := nextConnector(segment); idx > 0 {
+			segment = segment[:idx]
+		}
+		return config.SnippetField{}, 0, fmt.Errorf("无法识别的属性: %s（表中不存在列 %s）",
+			segment, utils.CamelCaseToDBString(utils.FirstLower(segment)))
+	}
+	keyword := s[matchedEnd:]
+	if idx := nextConnector(keyword); idx > 0 {
+		keyword = keyword[:idx]
+	}
+	for _, kw := range methodUnsupportedKeywords {
+		if keyword == kw {
+			return config.SnippetField{}, 0, fmt.Errorf("属性 %s 使用了不支持的关键字: %s", s[pos:matchedEnd], kw)
+		}
+	}
+	return config.SnippetField{}, 0, fmt.Errorf("属性 %s 后存在无法识别的关键字: %s", s[pos:matchedEnd], keyword)
+}
+
+// newWhereField 由列生成 WHERE 条件，参数名重复时追加序号（createdAt、createdAt2）
+func (p *methodNameParser) newWhereField(col *database.TableColumn, operator string) config.SnippetField {
+	fieldName := utils.DBStringToCamelCase(col.ColumnName)
+	if operator != "IS NULL" && operator != "IS NOT NULL" {
+		p.paramNames[fieldName]++
+		if n := p.paramNames[fieldName]; n > 1 {
+			fieldName += strconv.Itoa(n)
+		}
+	}
+	return config.SnippetField{
+		ColumnName: col.ColumnName,
+		FieldName:  fieldName,
+		JdbcType:   col.JdbcType,
+		JavaType:   col.JavaType,
+		Operator:   operator,
+	}
+}
+
+// parseOrderBy 解析排序部分（IdDescNameAsc），未指定方向默认 ASC
+func (p *methodNameParser) parseOrderBy(s string) ([]config.OrderByField, error) {
+	if s == "" {
+		return nil, fmt.Errorf("OrderBy 之后缺少排序属性")
+	}
+	var fields []config.OrderByField
+	for pos := 0; pos < len(s); {
+		var col *database.TableColumn
+		end := 0
+		for _, e := range wordBoundaries(s, pos) {
+			if col = p.lookupColumn(s[pos:e]); col != nil {
+				end = e
+				break
+			}
+		}
+		if col == nil {
+			return nil, fmt.Errorf("无法识别的排序属性: %s", s[pos:])
+		}
+		direction := "ASC"
+		switch {
+		case strings.HasPrefix(s[end:], "Desc") && startsWord(s[end+len("Desc"):]):
+			direction = "DESC"
+			end += len("Desc")
+		case strings.HasPrefix(s[end:], "Asc") && startsWord(s[end+len("Asc"):]):
+			end += len("Asc")
+		}
+		fields = append(fields, config.OrderByField{
+			ColumnName: col.ColumnName,
+			FieldName:  utils.DBStringToCamelCase(col.ColumnName),
+			JdbcType:   col.JdbcType,
+			Direction:  direction,
+		})
+		pos = end
+	}
+	return fields, nil
+}
+
+// sortedOperatorKeywords 条件关键字按长度降序（优先最长匹配）
+func sortedOperatorKeywords() []string {
+	keywords := make([]string, 0, len(methodOperatorKeywords))
+	for kw := range methodOperatorKeywords {
+		keywords = append(keywords, kw)
+	}
+	sort.Slice(keywords, func(i, j int) bool {
+		if len(keywords[i]) != len(keywords[j]) {
+			return len(keywords[i]) > len(keywords[j])
+		}
+		return keywords[i] < keywords[j]
+	})
+	return keywords
+}
+
+// wordBoundaries 返回 pos 之后所有单词边界（大写字母或结尾），从远到近
+func wordBoundaries(s string, pos int) []int {
+	var bounds []int
+	for i := pos + 1; i <= len(s); i++ {
+		if i == len(s) || unicode.IsUpper(rune(s[i])) {
+			bounds = append(bounds, i)
+		}
+	}
+	for l, r := 0, len(bounds)-1; l < r; l, r = l+1, r-1 {
+		bounds[l], bounds[r] = bounds[r], bounds[l]
+	}
+	return bounds
+}
+
+// startsWord 判断剩余部分是否为结尾或以新单词开头
+func startsWord(s string) bool {
+	return s == "" || unicode.IsUpper(rune(s[0])) || unicode.IsDigit(rune(s[0]))
+}
+
+// atConnector 判断剩余部分是否为结尾或以 And/Or 连接词开头
+func atConnector(s string) bool {
+	if s == "" {
+		return true
+	}
+	for _, c := range []string{"And", "Or"} {
+		if strings.HasPrefix(s, c) && len(s) > len(c) && unicode.IsUpper(rune(s[len(c)])) {
+			return true
+		}
+	}
+	return false
+}
+
+// nextConnector 返回下一个 And/Or 连接词的位置（不存在返回 -1）
+func nextConnector(s string) int {
+	for i := 1; i < len(s); i++ {
+		if unicode.IsUpper(rune(s[i])) && atConnector(s[i:]) {
+			return i
+		}
+	}
+	return -1
+}
+
+// indexWord 查找以单词边界结尾的关键字位置（其后为结尾或大写字母）
+func indexWord(s, word string) int {
+	for from := 0; from < len(s); {
+		idx := strings.Index(s[from:], word)
+		if idx < 0 {
+			return -1
+		}
+		idx += from
+		if startsWord(s[idx+len(word):]) {
+			return idx
+		}
+		from = idx + 1
+	}
+	return -1
+}
@@ -0,0 +1,158 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yourusername/mybatis-generator-gui-go/internal/config"
+	"github.com/yourusername/mybatis-generator-gui-go/internal/database"
+)
+
+func methodParserColumns() []*database.TableColumn {
+	return []*database.TableColumn{
+		{ColumnName: "id", JavaType: "Long", JdbcType: "BIGINT"},
+		{ColumnName: "user_id", JavaType: "Long", JdbcType: "BIGINT"},
+		{ColumnName: "status", JavaType: "Integer", JdbcType: "INTEGER"},
+		{ColumnName: "user_name", JavaType: "String", JdbcType: "VARCHAR"},
+		{ColumnName: "created_at", JavaType: "Date", JdbcType: "TIMESTAMP"},
+		{ColumnName: "expire_time", JavaType: "Date", JdbcType: "TIMESTAMP"},
+		{ColumnName: "sort_order", JavaType: "Integer", JdbcType: "INTEGER"},
+	}
+}
+
+func TestParseSnippetMethodName_FindWithOrderBy(t *testing.T) {
+	cfg, err := ParseSnippetMethodName("findByStatusAndCreatedAtGreaterThanOrderByIdDesc", methodParserColumns())
+	assert.NoError(t, err)
+	assert.Equal(t, config.OperationSelect, cfg.Operation)
+	assert.Equal(t, "findByStatusAndCreatedAtGreaterThanOrderByIdDesc", cfg.MethodName)
+	assert.Equal(t, "AND", cfg.WhereLogic)
+	assert.Len(t, cfg.WhereFields, 2)
+	assert.Equal(t, "status", cfg.WhereFields[0].ColumnName)
+	assert.Equal(t, "=", cfg.WhereFields[0].Operator)
+	assert.Equal(t, "created_at", cfg.WhereFields[1].ColumnName)
+	assert.Equal(t, "createdAt", cfg.WhereFields[1].FieldName)
+	assert.Equal(t, ">", cfg.WhereFields[1].Operator)
+	assert.Equal(t, []config.OrderByField{{ColumnName: "id", FieldName: "id", JdbcType: "BIGINT", Direction: "DESC"}}, cfg.OrderByFields)
+
+	result, err := GenerateSnippet(cfg, "UserMapper", "com.example.User", "user", NewDialect(nil))
+	assert.NoError(t, err)
+	assert.Equal(t, "findByStatusAndCreatedAtGreaterThanOrderByIdDesc", result.MethodName)
+	assert.Contains(t, result.XMLCode, "created_at > #{createdAt,jdbcType=TIMESTAMP}")
+	assert.Contains(t, result.XMLCode, "ORDER BY id DESC")
+}
+
+func TestParseSnippetMethodName_Keywords(t *testing.T) {
+	cfg, err := ParseSnippetMethodName("findTop5ByUserIdInOrStatusIsNullOrderBySortOrderAscIdDesc", methodParserColumns())
+	assert.NoError(t, err)
+	assert.True(t, cfg.HasLimit)
+	assert.True(t, cfg.IsLimitFixed)
+	assert.Equal(t, "5", cfg.LimitValue)
+	assert.Equal(t, "OR", cfg.WhereLogic)
+	assert.Equal(t, "IN", cfg.WhereFields[0].Operator)
+	assert.Equal(t, "IS NULL", cfg.WhereFields[1].Operator)
+	assert.Len(t, cfg.OrderByFields, 2)
+	assert.Equal(t, "sort_order", cfg.OrderByFields[0].ColumnName)
+	assert.Equal(t, "ASC", cfg.OrderByFields[0].Direction)
+
+	cfg, err = ParseSnippetMethodName("findByCreatedAtAfterAndCreatedAtBefore", methodParserColumns())
+	assert.NoError(t, err)
+	assert.Equal(t, "createdAt", cfg.WhereFields[0].FieldName)
+	assert.Equal(t, "createdAt2", cfg.WhereFields[1].FieldName)
+
+	cfg, err = ParseSnippetMethodName("findAllOrderByUserName", methodParserColumns())
+	assert.NoError(t, err)
+	assert.Empty(t, cfg.WhereFields)
+	assert.Equal(t, "user_name", cfg.OrderByFields[0].ColumnName)
+}
+
+func TestParseSnippetMethodName_CountAndDelete(t *testing.T) {
+	cfg, err := ParseSnippetMethodName("countByUserId", methodParserColumns())
+	assert.NoError(t, err)
+	assert.Equal(t, config.OperationSelect, cfg.Operation)
+	assert.Equal(t, config.ReturnShapeCount, cfg.ReturnShape)
+	assert.Equal(t, "user_id", cfg.WhereFields[0].ColumnName)
+
+	result, err := GenerateSnippet(cfg, "UserMapper", "com.example.User", "user", NewDialect(nil))
+	assert.NoError(t, err)
+	assert.Contains(t, result.JavaCode, "long countByUserId(")
+	assert.Contains(t, result.XMLCode, `<select id="countByUserId" resultType="java.lang.Long">`)
+	assert.Contains(t, result.XMLCode, "COUNT(*)")
+	assert.Nil(t, result.DTO)
+
+	cfg, err = ParseSnippetMethodName("existsByUserName", methodParserColumns())
+	assert.NoError(t, err)
+	assert.Equal(t, config.ReturnShapeExists, cfg.ReturnShape)
+
+	result, err = GenerateSnippet(cfg, "UserMapper", "com.example.User", "user", NewDialect(nil))
+	assert.NoError(t, err)
+	assert.Contains(t, result.JavaCode, "boolean existsByUserName(")
+
+	cfg, err = ParseSnippetMethodName("deleteByExpireTimeLessThan", methodParserColumns())
+	assert.NoError(t, err)
+	assert.Equal(t, config.OperationDelete, cfg.Operation)
+	assert.Equal(t, "<", cfg.WhereFields[0].Operator)
+
+	result, err = GenerateSnippet(cfg, "UserMapper", "com.example.User", "user", NewDialect(nil))
+	assert.NoError(t, err)
+	assert.Contains(t, result.XMLCode, "expire_time &lt; #{expireTime,jdbcType=TIMESTAMP}")
+	// 参数类型需导入，合并后的 Mapper 才能编译
+	assert.Contains(t, result.JavaCode, "int deleteByExpireTimeLessThan(Date expireTime);")
+	assert.Equal(t, []string{"java.util.Date"}, result.Imports)
+
+	columns := append(methodParserColumns(), &database.TableColumn{ColumnName: "amount", JavaType: "BigDecimal", JdbcType: "DECIMAL"})
+	cfg, err = ParseSnippetMethodName("findByAmountGreaterThanAndUserIdIn", columns)
+	assert.NoError(t, err)
+	result, err = GenerateSnippet(cfg, "UserMapper", "com.example.User", "user", NewDialect(nil))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"java.math.BigDecimal", "java.util.List", "org.apache.ibatis.annotations.Param"}, result.Imports)
+}
+
+func TestParseSnippetMethodName_FirstTop(t *testing.T) {
+	// First/Top 仅在紧跟动词时视为限制条数
+	cfg, err := ParseSnippetMethodName("findTopicsByStatus", methodParserColumns())
+	assert.NoError(t, err)
+	assert.False(t, cfg.HasLimit)
+	assert.Empty(t, cfg.ReturnShape)
+
+	// 不带数字只取一条，返回单个对象且不附带 count 方法
+	cfg, err = ParseSnippetMethodName("findFirstByUserIdOrderByCreatedAtDesc", methodParserColumns())
+	assert.NoError(t, err)
+	assert.Equal(t, "1", cfg.LimitValue)
+	assert.Equal(t, config.ReturnShapeOne, cfg.ReturnShape)
+
+	result, err := GenerateSnippet(cfg, "UserMapper", "com.example.User", "user", NewDialect(nil))
+	assert.NoError(t, err)
+	assert.Contains(t, result.JavaCode, "User findFirstByUserIdOrderByCreatedAtDesc(")
+	assert.Contains(t, result.XMLCode, "LIMIT 1")
+	assert.NotContains(t, result.XMLCode, `id="count`)
+
+	cfg, err = ParseSnippetMethodName("findTop10ByStatus", methodParserColumns())
+	assert.NoError(t, err)
+	assert.Equal(t, "10", cfg.LimitValue)
+	assert.Empty(t, cfg.ReturnShape)
+
+	_, err = ParseSnippetMethodName("countFirstByStatus", methodParserColumns())
+	assert.ErrorContains(t, err, "count 方法不支持 First")
+}
+
+func TestParseSnippetMethodName_Errors(t *testing.T) {
+	columns := methodParserColumns()
+
+	_, err := ParseSnippetMethodName("fetchByStatus", columns)
+	assert.ErrorContains(t, err, "无法识别的方法前缀")
+
+	_, err = ParseSnippetMethodName("findByNicknameAndStatus", columns)
+	assert.ErrorContains(t, err, "无法识别的属性: Nickname")
+
+	_, err = ParseSnippetMethodName("findByStatusBetween", columns)
+	assert.ErrorContains(t, err, "不支持的关键字: Between")
+
+	_, err = ParseSnippetMethodName("findByStatusFoo", columns)
+	assert.ErrorContains(t, err, "无法识别的关键字: Foo")
+
+	_, err = ParseSnippetMethodName("findByStatusAndUserIdOrId", columns)
+	assert.ErrorContains(t, err, "混合")
+
+	_, err = ParseSnippetMethodName("deleteAll", columns)
+	assert.ErrorContains(t, err, "删除方法必须包含 By 条件")
+}
@@ -0,0 +1,182 @@
+package generator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/yourusername/mybatis-generator-gui-go/internal/config"
+)
+
+// SnippetParamClass 参数对象模式下生成的查询参数类
+type SnippetParamClass struct {
+	Name       string              // 简单类名
+	Type       string              // 全限定类名（与实体类同包）
+	MethodName string              // 所属方法名（用于类注释）
+	Fields     []snippetParamField // 属性（顺序与原 @Param 参数一致）
+}
+
+// snippetParamField 参数类属性
+type snippetParamField struct {
+	Name     string
+	JavaType string
+}
+
+// usesParamObject 判断片段是否以参数对象作为唯一参数（批量操作的参数为列表，不适用）
+func usesParamObject(cfg *config.SnippetConfig) bool {
+	if !cfg.ParamObject || cfg.IsBatch {
+		return false
+	}
+	return cfg.Operation == config.OperationSelect || cfg.Operation == config.OperationDelete
+}
+
+// checkParamObject 校验参数对象模式是否适用：insert / update 以实体对象为参数，批量操作以列表为参数，均不支持
+func checkParamObject(cfg *config.SnippetConfig) error {
+	if !cfg.ParamObject {
+		return nil
+	}
+	if cfg.IsBatch {
+		return fmt.Errorf("批量操作不支持参数对象模式")
+	}
+	if cfg.Operation != config.OperationSelect && cfg.Operation != config.OperationDelete {
+		return fmt.Errorf("%s 片段以实体对象作为参数，不支持参数对象模式", cfg.Operation)
+	}
+	return nil
+}
+
+// withParamClassName 返回补全参数类名的配置副本，供 buildJavaParams 及 count 方法使用
+func withParamClassName(cfg *config.SnippetConfig, methodName string) *config.SnippetConfig {
+	named := *cfg
+	if named.ParamClassName == "" {
+		named.ParamClassName = capitalize(methodName) + "Query"
+	}
+	return &named
+}
+
+// buildParamClass 按片段的参数生成参数类定义（固定值条件不需要参数，不生成属性）
+func buildParamClass(cfg *config.SnippetConfig, methodName, modelType string) (*SnippetParamClass, error) {
+	if !javaIdentifierPattern.MatchString(cfg.ParamClassName) {
+		return nil, fmt.Errorf("无效的参数类名: %s", cfg.ParamClassName)
+	}
+	class := &SnippetParamClass{Name: cfg.ParamClassName, Type: cfg.ParamClassName, MethodName: methodName}
+	if idx := strings.LastIndex(modelType, "."); idx >= 0 {
+		class.Type = modelType[:idx] + "." + cfg.ParamClassName
+	}
+
+	seen := make(map[string]bool)
+	add := func(name, javaType string) {
+		if !seen[name] {
+			seen[name] = true
+			class.Fields = append(class.Fields, snippetParamField{Name: name, JavaType: javaType})
+		}
+	}
+	if cfg.Operation == config.OperationSelect && cfg.HasLimit && !cfg.IsLimitFixed {
+		limitName := cfg.LimitValue
+		if limitName == "" {
+			limitName = "limit"
+		}
+		add(limitName, "Integer")
+	}
+	for _, f := range snippetParamFields(cfg) {
+		if f.IsFixed {
+			continue
+		}
+		javaType := f.JavaType
+		if isCollectionOperator(f.Operator) {
+			javaType = "List<" + f.JavaType + ">"
+		}
+		add(f.FieldName, javaType)
+	}
+	if len(class.Fields) == 0 {
+		return nil, fmt.Errorf("参数对象模式需要至少一个参数")
+	}
+	return class, nil
+}
+
+// attachParamClass 为使用参数对象的片段附加参数类定义，并在 Mapper 中导入该类
+func attachParamClass(result *SnippetResult, cfg *config.SnippetConfig, modelType string) error {
+	if !usesParamObject(cfg) {
+		return nil
+	}
+	class, err := buildParamClass(cfg, result.MethodName, modelType)
+	if err != nil {
+		return err
+	}
+	result.ParamClass = class
+	if class.Type != class.Name {
+		result.Imports = append(result.Imports, class.Type)
+		sort.Strings(result.Imports)
+	}
+	return nil
+}
+
+// Render 生成参数类源码，Lombok 风格与实体类保持一致（GeneratorConfig.UseLombokPlugin）
+func (p *SnippetParamClass) Render(useLombok bool) (string, error) {
+	pkg := ""
+	if idx := strings.LastIndex(p.Type, "."); idx >= 0 {
+		pkg = p.Type[:idx]
+	}
+	types := make([]string, len(p.Fields))
+	for i, f := range p.Fields {
+		types[i] = f.JavaType
+	}
+
+	return renderTemplate("snippetParamClass", snippetParamClassTemplate, map[string]interface{}{
+		"Package":    pkg,
+		"Imports":    javaTypeImports(types),
+		"ClassName":  p.Name,
+		"MethodName": p.MethodName,
+		"Fields":     p.Fields,
+		"UseLombok":  useLombok,
+	})
+}
+
+// javaTypeImports 收集字段类型需要的 import（java.lang 之外的常用类型）
+func javaTypeImports(javaTypes []string) []string {
+	importSet := make(map[string]bool)
+	for _, javaType := range javaTypes {
+		if strings.HasPrefix(javaType, "List<") {
+			importSet["java.util.List"] = true
+			javaType = strings.TrimSuffix(strings.TrimPrefix(javaType, "List<"), ">")
+		}
+		switch javaType {
+		case "Date":
+			importSet["java.util.Date"] = true
+		case "BigDecimal", "BigInteger":
+			importSet["java.math."+javaType] = true
+		case "LocalDate", "LocalDateTime", "LocalTime":
+			importSet["java.time."+javaType] = true
+		}
+	}
+	imports := make([]string, 0, len(importSet))
+	for imp := range importSet {
+		imports = append(imports, imp)
+	}
+	sort.Strings(imports)
+	return imports
+}
+
+const snippetParamClassTemplate = `{{if .Package}}package {{.Package}};
+
+{{end}}{{if .UseLombok}}import lombok.Data;
+{{end}}import java.io.Serializable;
+{{range .Imports}}import {{.}};
+{{end}}
+/**
+ * 查询参数 - {{.MethodName}}
+ */
+{{if .UseLombok}}@Data
+{{end}}public class {{.ClassName}} implements Serializable {
+    private static final long serialVersionUID = 1L;
+{{range .Fields}}
+    private {{.JavaType}} {{.Name}};
+{{end}}{{if not .UseLombok}}{{range .Fields}}
+    public {{.JavaType}} get{{capitalize .Name}}() {
+        return {{.Name}};
+    }
+
+    public void set{{capitalize .Name}}({{.JavaType}} {{.Name}}) {
+        this.{{.Name}} = {{.Name}};
+    }
+{{end}}{{end}}}
+`
@@ -0,0 +1,90 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yourusername/mybatis-generator-gui-go/internal/config"
+)
+
+func TestGenerateSnippet_ParamObject(t *testing.T) {
+	cfg := evalSnippetConfig()
+	cfg.ParamObject = true
+	cfg.WhereFields = append(cfg.WhereFields, config.SnippetField{ColumnName: "deleted", FieldName: "deleted", JavaType: "Integer", JdbcType: "INTEGER", Operator: "=", IsFixed: true, FixedValue: "0"})
+	result, err := GenerateSnippet(cfg, "UserMapper", "com.example.model.User", "user", NewDialect(nil))
+	assert.NoError(t, err)
+
+	assert.Contains(t, result.JavaCode, "    List<User> search(SearchQuery query);")
+	assert.Contains(t, result.JavaCode, "    long countSearch(SearchQuery query);")
+	assert.Equal(t, []string{"com.example.model.SearchQuery", "java.util.List"}, result.Imports)
+	assert.Equal(t, "com.example.model.SearchQuery", result.ParamClass.Type)
+	assert.Equal(t, []snippetParamField{
+		{Name: "limit", JavaType: "Integer"},
+		{Name: "status", JavaType: "Integer"},
+		{Name: "userName", JavaType: "String"},
+		{Name: "userIds", JavaType: "List<Long>"},
+		{Name: "createdAt", JavaType: "Date"},
+	}, result.ParamClass.Fields)
+
+	// XML 直接引用对象属性，与 @Param 模式一致
+	bound, err := EvaluateStatement(result.XMLCode, "search", map[string]interface{}{"status": 1, "userIds": []interface{}{2}, "limit": 5})
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM user WHERE status = ? AND user_id IN ( ? ) AND deleted = 0 LIMIT ?", bound.SQL)
+
+	plain, err := result.ParamClass.Render(false)
+	assert.NoError(t, err)
+	assert.Contains(t, plain, "package com.example.model;\n\nimport java.io.Serializable;\nimport java.util.Date;\nimport java.util.List;\n")
+	assert.Contains(t, plain, "public class SearchQuery implements Serializable {")
+	assert.Contains(t, plain, "    private List<Long> userIds;\n")
+	assert.Contains(t, plain, "    public void setCreatedAt(Date createdAt) {")
+
+	lombok, err := result.ParamClass.Render(true)
+	assert.NoError(t, err)
+	assert.Contains(t, lombok, "import lombok.Data;\n")
+	assert.Contains(t, lombok, "@Data\npublic class SearchQuery")
+	assert.NotContains(t, lombok, "getStatus")
+}
+
+func TestGenerateSnippet_ParamObjectScope(t *testing.T) {
+	cfg := &config.SnippetConfig{
+		Operation:      config.OperationDelete,
+		ParamObject:    true,
+		ParamClassName: "ExpiredUserQuery",
+		WhereFields: []config.SnippetField{
+			{ColumnName: "status", FieldName: "status", JavaType: "Integer", JdbcType: "INTEGER", Operator: "="},
+			{ColumnName: "created_at", FieldName: "createdAt", JavaType: "Date", JdbcType: "TIMESTAMP", Operator: "<"},
+		},
+	}
+	result, err := GenerateSnippet(cfg, "UserMapper", "com.example.model.User", "user", NewDialect(nil))
+	assert.NoError(t, err)
+	assert.Contains(t, result.JavaCode, "    int deleteByStatusAndCreatedAt(ExpiredUserQuery query);")
+	assert.Equal(t, []string{"com.example.model.ExpiredUserQuery"}, result.Imports)
+
+	// 批量操作参数为列表，insert / update 参数为实体对象，均不支持参数对象模式
+	cfg.IsBatch = true
+	_, err = GenerateSnippet(cfg, "UserMapper", "com.example.model.User", "user", NewDialect(nil))
+	assert.ErrorContains(t, err, "批量操作不支持参数对象模式")
+
+	update := &config.SnippetConfig{
+		Operation:   config.OperationUpdate,
+		ParamObject: true,
+		SetFields:   []config.SnippetField{{ColumnName: "status", FieldName: "status", JavaType: "Integer", JdbcType: "INTEGER"}},
+		WhereFields: []config.SnippetField{{ColumnName: "id", FieldName: "id", JavaType: "Long", JdbcType: "BIGINT", Operator: "="}},
+	}
+	_, err = GenerateSnippet(update, "UserMapper", "com.example.model.User", "user", NewDialect(nil))
+	assert.ErrorContains(t, err, "update 片段以实体对象作为参数，不支持参数对象模式")
+
+	insert := &config.SnippetConfig{Operation: config.OperationInsert, ParamObject: true, InsertFields: update.SetFields}
+	_, err = GenerateSnippet(insert, "UserMapper", "com.example.model.User", "user", NewDialect(nil))
+	assert.ErrorContains(t, err, "insert 片段以实体对象作为参数")
+
+	// 无参数时无法生成参数类
+	cfg = &config.SnippetConfig{Operation: config.OperationSelect, ParamObject: true, ReturnShape: config.ReturnShapeCount}
+	_, err = GenerateSnippet(cfg, "UserMapper", "com.example.model.User", "user", NewDialect(nil))
+	assert.ErrorContains(t, err, "参数对象模式需要至少一个参数")
+
+	cfg.ParamClassName = "Bad-Name"
+	cfg.WhereFields = []config.SnippetField{{ColumnName: "id", FieldName: "id", JavaType: "Long", JdbcType: "BIGINT"}}
+	_, err = GenerateSnippet(cfg, "UserMapper", "com.example.model.User", "user", NewDialect(nil))
+	assert.ErrorContains(t, err, "无效的参数类名")
+}
@@ -0,0 +1,326 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/yourusername/mybatis-generator-gui-go/internal/config"
+)
+
+// selectProjection 查询结果投影（关联、聚合、别名或分组查询时映射到专用 DTO）
+type selectProjection struct {
+	FromSQL    string               // FROM 之后的表及 JOIN 子句
+	Config     config.SnippetConfig // 列名已限定、标签已补全的配置副本
+	GroupBySQL string               // GROUP BY 子句（含关键字）
+	HavingSQL  string               // HAVING 子句（含关键字）
+	Columns    []projectionColumn   // 结果列（用于 resultMap 和 DTO）
+	DTOName    string               // DTO 简单类名
+	DTOType    string               // DTO 全限定类名
+}
+
+// projectionColumn 投影结果列
+type projectionColumn struct {
+	Label    string // SQL 中的列标签
+	Property string // DTO 属性名
+	JavaType string
+	JdbcType string
+}
+
+// needsProjection 判断查询结果是否无法直接映射到实体类
+func needsProjection(cfg *config.SnippetConfig) bool {
+	if len(cfg.Joins) > 0 || len(cfg.GroupByFields) > 0 || len(cfg.HavingFields) > 0 {
+		return true
+	}
+	for _, f := range cfg.SelectFields {
+		if f.Aggregate != "" || f.Alias != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// aggregateExpr 生成聚合表达式（无聚合函数时返回列本身）
+func aggregateExpr(aggregate, column string) string {
+	if aggregate == "" {
+		return column
+	}
+	return fmt.Sprintf("%s(%s)", strings.ToUpper(aggregate), column)
+}
+
+// aggregateJavaType 聚合结果的 Java 类型
+func aggregateJavaType(aggregate, javaType string) string {
+	switch strings.ToUpper(aggregate) {
+	case "COUNT":
+		return "Long"
+	case "SUM", "AVG":
+		return "BigDecimal"
+	}
+	if javaType == "" || javaType == "Object" {
+		return "String"
+	}
+	return javaType
+}
+
+// buildSelectProjection 生成查询投影：限定列名、补全列标签、构建 GROUP BY / HAVING 及 DTO 信息
+func buildSelectProjection(cfg *config.SnippetConfig, tableName, modelType, methodName string) (*selectProjection, error) {
+	fromSQL := tableName
+	var aliases map[string]bool
+	if len(cfg.Joins) > 0 {
+		var err error
+		if fromSQL, aliases, err = buildJoinFrom(cfg, tableName); err != nil {
+			return nil, err
+		}
+	}
+	mainAlias := snippetMainAlias(cfg)
+
+	// qualify 按别名限定列名；无关联表时列名保持原样
+	qualify := func(alias, column string) (string, error) {
+		if column == "*" {
+			return column, nil
+		}
+		if aliases == nil {
+			if alias != "" {
+				return "", fmt.Errorf("列 %s 指定了表别名 %s，但未配置关联表", column, alias)
+			}
+			return column, nil
+		}
+		alias = fieldTableAlias(cfg, alias)
+		if !aliases[alias] {
+			return "", fmt.Errorf("列 %s 引用了未定义的别名: %s", column, alias)
+		}
+		return alias + "." + column, nil
+	}
+
+	if len(cfg.SelectFields) == 0 {
+		return nil, fmt.Errorf("关联或分组查询需要指定查询列")
+	}
+
+	projected := *cfg
+
+	groupBy := make(map[string]bool, len(cfg.GroupByFields))
+	projected.GroupByFields = make([]config.SnippetField, len(cfg.GroupByFields))
+	groupParts := make([]string, len(cfg.GroupByFields))
+	for i, f := range cfg.GroupByFields {
+		column, err := qualify(f.TableAlias, f.ColumnName)
+		if err != nil {
+			return nil, err
+		}
+		groupBy[column] = true
+		groupParts[i] = column
+		f.ColumnName = column
+		projected.GroupByFields[i] = f
+	}
+
+	// SELECT 列：关联表的列默认以 别名_列名、聚合列以 函数_列名 作为标签
+	projected.SelectFields = make([]config.SnippetField, len(cfg.SelectFields))
+	columns := make([]projectionColumn, len(cfg.SelectFields))
+	usedProperties := make(map[string]bool)
+	for i, f := range cfg.SelectFields {
+		alias := fieldTableAlias(cfg, f.TableAlias)
+		column, err := qualify(f.TableAlias, f.ColumnName)
+		if err != nil {
+			return nil, err
+		}
+		if f.ColumnName == "*" && f.Aggregate == "" {
+			return nil, fmt.Errorf("结果映射到 DTO 时不能使用 * 作为查询列，请指定具体列")
+		}
+		if f.Aggregate == "" && len(groupBy) > 0 && !groupBy[column] {
+			return nil, fmt.Errorf("非聚合列 %s 未包含在 GROUP BY 中", column)
+		}
+
+		label := f.Alias
+		if label == "" {
+			label = f.ColumnName
+			if label == "*" {
+				label = "all"
+			}
+			if aliases != nil && alias != mainAlias {
+				label = alias + "_" + label
+			}
+			if f.Aggregate != "" {
+				label = strings.ToLower(f.Aggregate) + "_" + label
+			}
+		}
+		property := f.FieldName
+		if property == "" || f.Aggregate != "" || f.Alias != "" {
+			property = snakeToCamel(label)
+		}
+		if usedProperties[property] {
+			property = alias + capitalize(property)
+		}
+		usedProperties[property] = true
+
+		if aliases != nil || label != f.ColumnName {
+			f.Alias = label
+		}
+		f.ColumnName = column
+		projected.SelectFields[i] = f
+		columns[i] = projectionColumn{
+			Label:    label,
+			Property: property,
+			JavaType: aggregateJavaType(f.Aggregate, f.JavaType),
+			JdbcType: aggregateJdbcType(f.Aggregate, f.JdbcType),
+		}
+	}
+
+	projected.WhereFields = make([]config.SnippetField, len(cfg.WhereFields))
+	for i, f := range cfg.WhereFields {
+		column, err := qualify(f.TableAlias, f.ColumnName)
+		if err != nil {
+			return nil, err
+		}
+		f.ColumnName = column
+		projected.WhereFields[i] = f
+	}
+
+	projected.OrderByFields = make([]config.OrderByField, len(cfg.OrderByFields))
+	for i, f := range cfg.OrderByFields {
+		column, err := qualify(f.TableAlias, f.ColumnName)
+		if err != nil {
+			return nil, err
+		}
+		f.ColumnName = column
+		projected.OrderByFields[i] = f
+	}
+
+	// HAVING 条件：列替换为聚合表达式，未指定参数名时按 函数+列名 生成
+	projected.HavingFields = make([]config.SnippetField, len(cfg.HavingFields))
+	for i, f := range cfg.HavingFields {
+		column, err := qualify(f.TableAlias, f.ColumnName)
+		if err != nil {
+			return nil, err
+		}
+		if f.FieldName == "" {
+			name := f.ColumnName
+			if name == "*" {
+				name = "all"
+			}
+			f.FieldName = snakeToCamel(strings.ToLower(f.Aggregate) + "_" + name)
+		}
+		if f.Aggregate != "" {
+			f.JavaType = aggregateJavaType(f.Aggregate, f.JavaType)
+			f.JdbcType = aggregateJdbcType(f.Aggregate, f.JdbcType)
+		}
+		f.ColumnName = aggregateExpr(f.Aggregate, column)
+		projected.HavingFields[i] = f
+	}
+
+	groupBySQL := ""
+	if len(groupParts) > 0 {
+		groupBySQL = "GROUP BY " + strings.Join(groupParts, ", ")
+	}
+	havingSQL := ""
+	if len(projected.HavingFields) > 0 {
+		havingSQL = "HAVING " + buildWhereSQL(projected.HavingFields, "AND")
+	}
+
+	dtoName := cfg.ResultType
+	if dtoName == "" {
+		dtoName = capitalize(methodName) + "DTO"
+	}
+	dtoType := dtoName
+	if idx := strings.LastIndex(modelType, "."); idx >= 0 {
+		dtoType = modelType[:idx] + "." + dtoName
+	}
+
+	return &selectProjection{
+		FromSQL:    fromSQL,
+		Config:     projected,
+		GroupBySQL: groupBySQL,
+		HavingSQL:  havingSQL,
+		Columns:    columns,
+		DTOName:    dtoName,
+		DTOType:    dtoType,
+	}, nil
+}
+
+// aggregateJdbcType 聚合结果的 JDBC 类型
+func aggregateJdbcType(aggregate, jdbcType string) string {
+	switch strings.ToUpper(aggregate) {
+	case "COUNT":
+		return "BIGINT"
+	case "SUM", "AVG":
+		return "DECIMAL"
+	}
+	return jdbcType
+}
+
+// renderProjectionResultMap 生成投影结果的 resultMap
+func renderProjectionResultMap(proj *selectProjection, resultMapID string) (string, error) {
+	return renderTemplate("projectionResultMap", projectionResultMapTemplate, map[string]interface{}{
+		"ID":      resultMapID,
+		"Type":    proj.DTOType,
+		"Columns": proj.Columns,
+	})
+}
+
+// SnippetDTO 投影查询生成的结果 DTO
+type SnippetDTO struct {
+	Name       string             // 简单类名
+	Type       string             // 全限定类名（与实体类同包）
+	MethodName string             // 所属方法名（用于类注释）
+	Columns    []projectionColumn // 结果列
+}
+
+// Render 生成 DTO 类源码，Lombok 风格与实体类保持一致（GeneratorConfig.UseLombokPlugin）
+func (d *SnippetDTO) Render(useLombok bool) (string, error) {
+	pkg := ""
+	if idx := strings.LastIndex(d.Type, "."); idx >= 0 {
+		pkg = d.Type[:idx]
+	}
+
+	types := make([]string, len(d.Columns))
+	for i, col := range d.Columns {
+		types[i] = col.JavaType
+	}
+
+	return renderTemplate("projectionDTO", projectionDTOTemplate, map[string]interface{}{
+		"Package":    pkg,
+		"Imports":    javaTypeImports(types),
+		"ClassName":  d.Name,
+		"MethodName": d.MethodName,
+		"Columns":    d.Columns,
+		"UseLombok":  useLombok,
+	})
+}
+
+// snakeToCamel 下划线列名转驼峰属性名（user_name -> userName）
+func snakeToCamel(s string) string {
+	parts := strings.Split(strings.ToLower(s), "_")
+	for i := 1; i < len(parts); i++ {
+		parts[i] = capitalize(parts[i])
+	}
+	return strings.Join(parts, "")
+}
+
+const projectionResultMapTemplate = `    <!-- 查询结果映射 -->
+    <resultMap id="{{.ID}}" type="{{.Type}}">
+{{- range .Columns}}
+        <result column="{{.Label}}"{{if .JdbcType}} jdbcType="{{.JdbcType}}"{{end}} property="{{.Property}}" />
+{{- end}}
+    </resultMap>`
+
+const projectionDTOTemplate = `{{if .Package}}package {{.Package}};
+
+{{end}}{{if .UseLombok}}import lombok.Data;
+{{end}}import java.io.Serializable;
+{{range .Imports}}import {{.}};
+{{end}}
+/**
+ * 查询结果 - {{.MethodName}}
+ */
+{{if .UseLombok}}@Data
+{{end}}public class {{.ClassName}} implements Serializable {
+    private static final long serialVersionUID = 1L;
+{{range .Columns}}
+    private {{.JavaType}} {{.Property}};
+{{end}}{{if not .UseLombok}}{{range .Columns}}
+    public {{.JavaType}} get{{capitalize .Property}}() {
+        return {{.Property}};
+    }
+
+    public void set{{capitalize .Property}}({{.JavaType}} {{.Property}}) {
+        this.{{.Property}} = {{.Property}};
+    }
+{{end}}{{end}}}
+`
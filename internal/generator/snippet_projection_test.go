@@ -0,0 +1,152 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yourusername/mybatis-generator-gui-go/internal/config"
+	"github.com/yourusername/mybatis-generator-gui-go/internal/database"
+)
+
+func joinSnippetConfig() *config.SnippetConfig {
+	return &config.SnippetConfig{
+		MethodName: "selectOrdersWithUser",
+		Operation:  config.OperationSelect,
+		Joins: []config.SnippetJoin{{
+			JoinType:  "inner",
+			TableName: "user",
+			Alias:     "u",
+			On:        []config.SnippetJoinOn{{LeftColumn: "user_id", RightColumn: "id"}},
+		}},
+		SelectFields: []config.SnippetField{
+			{ColumnName: "id", FieldName: "id", JavaType: "Long", JdbcType: "BIGINT"},
+			{ColumnName: "id", FieldName: "id", JavaType: "Long", JdbcType: "BIGINT", TableAlias: "u"},
+			{ColumnName: "user_name", FieldName: "userName", JavaType: "String", JdbcType: "VARCHAR", TableAlias: "u"},
+		},
+		WhereFields:   []config.SnippetField{{ColumnName: "status", FieldName: "status", JavaType: "Integer", JdbcType: "INTEGER", Operator: "="}},
+		OrderByFields: []config.OrderByField{{ColumnName: "user_name", Direction: "DESC", TableAlias: "u"}},
+	}
+}
+
+func TestGenerateSnippet_Join(t *testing.T) {
+	result, err := GenerateSnippet(joinSnippetConfig(), "OrderMapper", "com.example.model.Order", "orders", NewDialect(nil))
+	assert.NoError(t, err)
+
+	assert.Contains(t, result.XMLCode, "FROM orders t\n        INNER JOIN user u ON t.user_id = u.id")
+	assert.Contains(t, result.XMLCode, "SELECT t.id AS id, u.id AS u_id, u.user_name AS u_user_name")
+	assert.Contains(t, result.XMLCode, "WHERE t.status = #{status,jdbcType=INTEGER}")
+	assert.Contains(t, result.XMLCode, "ORDER BY u.user_name DESC")
+	assert.Contains(t, result.XMLCode, `<resultMap id="selectOrdersWithUserResultMap" type="com.example.model.SelectOrdersWithUserDTO">`)
+	assert.Contains(t, result.XMLCode, `<result column="u_id" jdbcType="BIGINT" property="uId" />`)
+	assert.Contains(t, result.XMLCode, `resultMap="selectOrdersWithUserResultMap"`)
+
+	assert.Contains(t, result.JavaCode, "List<SelectOrdersWithUserDTO> selectOrdersWithUser(Integer status);")
+	assert.Contains(t, result.Imports, "com.example.model.SelectOrdersWithUserDTO")
+	assert.Equal(t, "SelectOrdersWithUserDTO", result.DTO.Name)
+	dtoCode, err := result.DTO.Render(false)
+	assert.NoError(t, err)
+	assert.Contains(t, dtoCode, "package com.example.model;")
+	assert.Contains(t, dtoCode, "private String userName;")
+	assert.Contains(t, dtoCode, "public Long getUId()")
+
+	// Lombok 风格与实体类一致
+	dtoCode, err = result.DTO.Render(true)
+	assert.NoError(t, err)
+	assert.Contains(t, dtoCode, "import lombok.Data;")
+	assert.Contains(t, dtoCode, "@Data\npublic class SelectOrdersWithUserDTO implements Serializable {")
+	assert.NotContains(t, dtoCode, "getUId()")
+}
+
+func TestGenerateSnippet_JoinInvalid(t *testing.T) {
+	cfg := joinSnippetConfig()
+	cfg.Joins[0].On = nil
+	_, err := GenerateSnippet(cfg, "OrderMapper", "com.example.model.Order", "orders", NewDialect(nil))
+	assert.Error(t, err)
+
+	cfg = joinSnippetConfig()
+	cfg.WhereFields[0].TableAlias = "x"
+	_, err = GenerateSnippet(cfg, "OrderMapper", "com.example.model.Order", "orders", NewDialect(nil))
+	assert.Error(t, err)
+}
+
+func TestValidateSnippet_Join(t *testing.T) {
+	columns := []*database.TableColumn{{ColumnName: "id"}, {ColumnName: "user_id"}, {ColumnName: "status"}}
+	joinColumns := map[string][]*database.TableColumn{"user": {{ColumnName: "id"}, {ColumnName: "user_name"}}}
+	assert.NoError(t, ValidateSnippet(joinSnippetConfig(), columns, joinColumns))
+
+	cfg := joinSnippetConfig()
+	cfg.SelectFields[2].ColumnName = "email"
+	err := ValidateSnippet(cfg, columns, joinColumns)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "user.email")
+}
+
+func TestGenerateSnippet_GroupByHaving(t *testing.T) {
+	cfg := &config.SnippetConfig{
+		Operation: config.OperationSelect,
+		SelectFields: []config.SnippetField{
+			{ColumnName: "status", FieldName: "status", JavaType: "Integer", JdbcType: "INTEGER"},
+			{ColumnName: "*", Aggregate: "count", Alias: "total"},
+			{ColumnName: "amount", FieldName: "amount", JavaType: "BigDecimal", JdbcType: "DECIMAL", Aggregate: "SUM"},
+		},
+		WhereFields:   []config.SnippetField{{ColumnName: "deleted", FieldName: "deleted", JavaType: "Integer", JdbcType: "INTEGER", Operator: "="}},
+		GroupByFields: []config.SnippetField{{ColumnName: "status", FieldName: "status"}},
+		HavingFields:  []config.SnippetField{{ColumnName: "*", Aggregate: "COUNT", Operator: ">", FieldName: "minCount"}},
+		HasLimit:      true,
+	}
+	result, err := GenerateSnippet(cfg, "OrderMapper", "com.example.model.Order", "orders", NewDialect(nil))
+	assert.NoError(t, err)
+
+	assert.Equal(t, "selectByDeletedGroupByStatus", result.MethodName)
+	assert.Contains(t, result.XMLCode, "SELECT status, COUNT(*) AS total, SUM(amount) AS sum_amount")
+	assert.Contains(t, result.XMLCode, "GROUP BY status\n        HAVING COUNT(*) > #{minCount,jdbcType=BIGINT}")
+	assert.Contains(t, result.XMLCode, `<result column="total" jdbcType="BIGINT" property="total" />`)
+	assert.Contains(t, result.XMLCode, `<result column="sum_amount" jdbcType="DECIMAL" property="sumAmount" />`)
+	assert.Contains(t, result.JavaCode, `List<SelectByDeletedGroupByStatusDTO> selectByDeletedGroupByStatus(@Param("limit") Integer limit, @Param("deleted") Integer deleted, @Param("minCount") Long minCount);`)
+	dtoCode, err := result.DTO.Render(false)
+	assert.NoError(t, err)
+	assert.Contains(t, dtoCode, "private Long total;")
+	assert.Contains(t, dtoCode, "import java.math.BigDecimal;")
+
+	// count 方法统计分组数
+	assert.Contains(t, result.XMLCode, "SELECT COUNT(*) FROM (\n        SELECT 1\n        FROM orders")
+}
+
+func TestGenerateSnippet_GroupByRequiresNonAggregateColumns(t *testing.T) {
+	cfg := &config.SnippetConfig{
+		Operation: config.OperationSelect,
+		SelectFields: []config.SnippetField{
+			{ColumnName: "status", FieldName: "status"},
+			{ColumnName: "name", FieldName: "name"},
+			{ColumnName: "id", Aggregate: "COUNT"},
+		},
+		GroupByFields: []config.SnippetField{{ColumnName: "status"}},
+	}
+	_, err := GenerateSnippet(cfg, "OrderMapper", "com.example.model.Order", "orders", NewDialect(nil))
+	assert.Error(t, err)
+}
+
+func TestGenerateSnippet_PlainSelectKeepsModel(t *testing.T) {
+	cfg := &config.SnippetConfig{
+		Operation:    config.OperationSelect,
+		SelectFields: []config.SnippetField{{ColumnName: "id", FieldName: "id"}},
+	}
+	result, err := GenerateSnippet(cfg, "OrderMapper", "com.example.model.Order", "orders", NewDialect(nil))
+	assert.NoError(t, err)
+	assert.Nil(t, result.DTO)
+	assert.Contains(t, result.XMLCode, `resultMap="BaseResultMap"`)
+}
+
+func TestGenerateSnippet_ProjectionRejectsStar(t *testing.T) {
+	cfg := &config.SnippetConfig{
+		Operation:    config.OperationSelect,
+		SelectFields: []config.SnippetField{{ColumnName: "*"}, {ColumnName: "id", Alias: "order_id"}},
+	}
+	_, err := GenerateSnippet(cfg, "OrderMapper", "com.example.model.Order", "orders", NewDialect(nil))
+	assert.ErrorContains(t, err, "不能使用 * 作为查询列")
+
+	cfg = joinSnippetConfig()
+	cfg.SelectFields = append(cfg.SelectFields, config.SnippetField{ColumnName: "*", TableAlias: "u"})
+	_, err = GenerateSnippet(cfg, "OrderMapper", "com.example.model.Order", "orders", NewDialect(nil))
+	assert.ErrorContains(t, err, "不能使用 * 作为查询列")
+}
@@ -0,0 +1,112 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/yourusername/mybatis-generator-gui-go/internal/config"
+)
+
+// snippetReturnShape 查询片段的返回形式（未配置时为 List）
+func snippetReturnShape(cfg *config.SnippetConfig) config.SnippetReturnShape {
+	if cfg.ReturnShape == "" {
+		return config.ReturnShapeList
+	}
+	return cfg.ReturnShape
+}
+
+// checkReturnShape 校验返回形式与其他配置是否兼容
+func checkReturnShape(cfg *config.SnippetConfig) error {
+	shape := snippetReturnShape(cfg)
+	switch shape {
+	case config.ReturnShapeList, config.ReturnShapeOne, config.ReturnShapeOptional, config.ReturnShapeMap:
+	case config.ReturnShapeCount, config.ReturnShapeExists:
+		if cfg.HasLimit {
+			return fmt.Errorf("%s 返回形式不支持 LIMIT", shape)
+		}
+	case config.ReturnShapeMapKey:
+		if cfg.MapKey == nil || cfg.MapKey.ColumnName == "" {
+			return fmt.Errorf("@MapKey 返回形式需要指定键列")
+		}
+	default:
+		return fmt.Errorf("未知返回形式: %s", shape)
+	}
+	if cfg.IsBatch && shape != config.ReturnShapeList && shape != config.ReturnShapeMapKey {
+		return fmt.Errorf("批量查询仅支持 List 或 @MapKey 返回形式")
+	}
+	return nil
+}
+
+// isScalarReturnShape count / exists 不映射查询列，只返回单个值
+func isScalarReturnShape(shape config.SnippetReturnShape) bool {
+	return shape == config.ReturnShapeCount || shape == config.ReturnShapeExists
+}
+
+// buildShapeMethodName 按返回形式调整自动生成的方法名（selectByStatus -> countByStatus / existsByStatus）
+func buildShapeMethodName(methodName string, shape config.SnippetReturnShape) string {
+	switch shape {
+	case config.ReturnShapeCount:
+		return buildCountMethodName(methodName)
+	case config.ReturnShapeExists:
+		rest := trimSelectPrefix(methodName)
+		if rest == "" || rest == "All" || rest == "ByFields" {
+			return "exists"
+		}
+		return "exists" + capitalize(rest)
+	}
+	return methodName
+}
+
+// mapKeyProperty 获取 @MapKey 引用的结果属性名，键列必须出现在查询结果中
+// cfg 为投影前的原始配置；投影查询按键列对应的 DTO 属性取名
+func mapKeyProperty(cfg *config.SnippetConfig, proj *selectProjection) (string, error) {
+	key := cfg.MapKey
+	if proj != nil {
+		keyAlias := fieldTableAlias(cfg, key.TableAlias)
+		for i, f := range cfg.SelectFields {
+			if f.Aggregate == "" && strings.EqualFold(f.ColumnName, key.ColumnName) && fieldTableAlias(cfg, f.TableAlias) == keyAlias {
+				return proj.Columns[i].Property, nil
+			}
+		}
+		return "", fmt.Errorf("@MapKey 键列 %s 不在查询结果中", key.ColumnName)
+	}
+
+	found := len(cfg.SelectFields) == 0
+	for _, f := range cfg.SelectFields {
+		if f.ColumnName == "*" || strings.EqualFold(f.ColumnName, key.ColumnName) {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return "", fmt.Errorf("@MapKey 键列 %s 不在查询结果中", key.ColumnName)
+	}
+	if key.FieldName != "" {
+		return key.FieldName, nil
+	}
+	return snakeToCamel(key.ColumnName), nil
+}
+
+// selectReturnType 按返回形式生成 Java 方法的返回类型（@MapKey 注解由调用方单独输出）
+func selectReturnType(cfg *config.SnippetConfig, simpleModel string) string {
+	switch snippetReturnShape(cfg) {
+	case config.ReturnShapeOne:
+		return simpleModel
+	case config.ReturnShapeOptional:
+		return "Optional<" + simpleModel + ">"
+	case config.ReturnShapeCount:
+		return "long"
+	case config.ReturnShapeExists:
+		return "boolean"
+	case config.ReturnShapeMap:
+		return "Map<String, Object>"
+	case config.ReturnShapeMapKey:
+		keyType := cfg.MapKey.JavaType
+		if keyType == "" {
+			keyType = "Object"
+		}
+		return fmt.Sprintf("Map<%s, %s>", keyType, simpleModel)
+	default:
+		return "List<" + simpleModel + ">"
+	}
+}
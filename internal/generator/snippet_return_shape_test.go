@@ -0,0 +1,133 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yourusername/mybatis-generator-gui-go/internal/config"
+)
+
+func returnShapeConfig(shape config.SnippetReturnShape) *config.SnippetConfig {
+	return &config.SnippetConfig{
+		Operation:   config.OperationSelect,
+		ReturnShape: shape,
+		WhereFields: []config.SnippetField{
+			{ColumnName: "status", FieldName: "status", JavaType: "Integer", JdbcType: "INTEGER", Operator: "="},
+			{ColumnName: "user_name", FieldName: "userName", JavaType: "String", JdbcType: "VARCHAR", Operator: "="},
+		},
+	}
+}
+
+func TestGenerateSnippet_ReturnShapes(t *testing.T) {
+	cases := []struct {
+		shape   config.SnippetReturnShape
+		java    string
+		xml     string
+		imports []string
+	}{
+		{config.ReturnShapeList, "List<User> selectByStatusAndUserName(", `resultMap="BaseResultMap"`,
+			[]string{"java.util.List", "org.apache.ibatis.annotations.Param"}},
+		{config.ReturnShapeOne, "    User selectByStatusAndUserName(", `resultMap="BaseResultMap"`,
+			[]string{"org.apache.ibatis.annotations.Param"}},
+		{config.ReturnShapeOptional, "Optional<User> selectByStatusAndUserName(", `resultMap="BaseResultMap"`,
+			[]string{"java.util.Optional", "org.apache.ibatis.annotations.Param"}},
+		{config.ReturnShapeMap, "Map<String, Object> selectByStatusAndUserName(", `resultType="java.util.Map"`,
+			[]string{"java.util.Map", "org.apache.ibatis.annotations.Param"}},
+		{config.ReturnShapeCount, "long countByStatusAndUserName(", "SELECT COUNT(*)\n        FROM user",
+			[]string{"org.apache.ibatis.annotations.Param"}},
+	}
+	for _, c := range cases {
+		result, err := GenerateSnippet(returnShapeConfig(c.shape), "UserMapper", "com.example.User", "user", NewDialect(nil))
+		assert.NoError(t, err, c.shape)
+		assert.Contains(t, result.JavaCode, c.java, c.shape)
+		assert.Contains(t, result.XMLCode, c.xml, c.shape)
+		assert.Equal(t, c.imports, result.Imports, c.shape)
+	}
+
+	// exists：SELECT 1 ... LIMIT 1 外包 COUNT，无记录时返回 0
+	result, err := GenerateSnippet(returnShapeConfig(config.ReturnShapeExists), "UserMapper", "com.example.User", "user", NewDialect(nil))
+	assert.NoError(t, err)
+	assert.Equal(t, "existsByStatusAndUserName", result.MethodName)
+	assert.Contains(t, result.JavaCode, "boolean existsByStatusAndUserName(@Param(\"status\") Integer status, @Param(\"userName\") String userName);")
+	bound, err := EvaluateStatement(result.XMLCode, result.MethodName, map[string]interface{}{"status": 1, "userName": "tom"})
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT COUNT(*) FROM ( SELECT 1 FROM user WHERE status = ? AND user_name = ? LIMIT 1 ) e", bound.SQL)
+
+	oracle := NewDialect(&config.DatabaseConfig{DbType: config.DbTypeOracle})
+	result, err = GenerateSnippet(returnShapeConfig(config.ReturnShapeExists), "UserMapper", "com.example.User", "user", oracle)
+	assert.NoError(t, err)
+	assert.Contains(t, result.XMLCode, "FETCH FIRST 1 ROWS ONLY")
+
+	// 单个对象形式带 LIMIT 时不附带 count 方法
+	cfg := returnShapeConfig(config.ReturnShapeOne)
+	cfg.HasLimit, cfg.IsLimitFixed, cfg.LimitValue = true, true, "1"
+	result, err = GenerateSnippet(cfg, "UserMapper", "com.example.User", "user", NewDialect(nil))
+	assert.NoError(t, err)
+	assert.NotContains(t, result.JavaCode, "count")
+
+	cfg = returnShapeConfig(config.ReturnShapeCount)
+	cfg.HasLimit = true
+	_, err = GenerateSnippet(cfg, "UserMapper", "com.example.User", "user", NewDialect(nil))
+	assert.ErrorContains(t, err, "不支持 LIMIT")
+}
+
+func TestGenerateSnippet_MapKey(t *testing.T) {
+	cfg := &config.SnippetConfig{
+		Operation:   config.OperationSelect,
+		IsBatch:     true,
+		ReturnShape: config.ReturnShapeMapKey,
+		MapKey:      &config.SnippetField{ColumnName: "id", FieldName: "id", JavaType: "Long"},
+		WhereFields: []config.SnippetField{{ColumnName: "id", FieldName: "id", JavaType: "Long", JdbcType: "BIGINT"}},
+	}
+	result, err := GenerateSnippet(cfg, "UserMapper", "com.example.User", "user", NewDialect(nil))
+	assert.NoError(t, err)
+	assert.Contains(t, result.JavaCode, "    @MapKey(\"id\")\n    Map<Long, User> selectByIdIn(@Param(\"list\") List<Long> list);")
+	assert.Equal(t, []string{"java.util.List", "java.util.Map", "org.apache.ibatis.annotations.MapKey", "org.apache.ibatis.annotations.Param"}, result.Imports)
+	assert.Equal(t, []string{"selectByIdIn"}, JavaMethodNames(result.JavaCode))
+
+	// 键列必须出现在查询结果中
+	cfg.SelectFields = []config.SnippetField{{ColumnName: "user_name", FieldName: "userName"}}
+	_, err = GenerateSnippet(cfg, "UserMapper", "com.example.User", "user", NewDialect(nil))
+	assert.ErrorContains(t, err, "@MapKey 键列 id 不在查询结果中")
+
+	// 关联查询以 DTO 属性为键
+	cfg = &config.SnippetConfig{
+		Operation:   config.OperationSelect,
+		ReturnShape: config.ReturnShapeMapKey,
+		MapKey:      &config.SnippetField{ColumnName: "name", TableAlias: "d", JavaType: "String"},
+		SelectFields: []config.SnippetField{
+			{ColumnName: "id", FieldName: "id", JavaType: "Long", JdbcType: "BIGINT"},
+			{ColumnName: "name", TableAlias: "d", JavaType: "String", JdbcType: "VARCHAR"},
+		},
+		Joins: []config.SnippetJoin{{TableName: "dept", Alias: "d", On: []config.SnippetJoinOn{{LeftColumn: "dept_id", RightColumn: "id"}}}},
+	}
+	result, err = GenerateSnippet(cfg, "UserMapper", "com.example.User", "user", NewDialect(nil))
+	assert.NoError(t, err)
+	assert.Contains(t, result.JavaCode, "@MapKey(\"dName\")\n    Map<String, SelectByFieldsDTO> selectByFields();")
+
+	cfg.MapKey = nil
+	_, err = GenerateSnippet(cfg, "UserMapper", "com.example.User", "user", NewDialect(nil))
+	assert.ErrorContains(t, err, "需要指定键列")
+
+	cfg = returnShapeConfig(config.ReturnShapeOne)
+	cfg.IsBatch = true
+	_, err = GenerateSnippet(cfg, "UserMapper", "com.example.User", "user", NewDialect(nil))
+	assert.ErrorContains(t, err, "批量查询仅支持")
+}
+
+func TestGenerateSnippet_ScalarShapeWithJoin(t *testing.T) {
+	cfg := &config.SnippetConfig{
+		Operation:   config.OperationSelect,
+		MethodName:  "countActiveInDept",
+		ReturnShape: config.ReturnShapeCount,
+		WhereFields: []config.SnippetField{{ColumnName: "name", TableAlias: "d", FieldName: "deptName", JavaType: "String", JdbcType: "VARCHAR"}},
+		Joins:       []config.SnippetJoin{{TableName: "dept", Alias: "d", On: []config.SnippetJoinOn{{LeftColumn: "dept_id", RightColumn: "id"}}}},
+	}
+	result, err := GenerateSnippet(cfg, "UserMapper", "com.example.User", "user", NewDialect(nil))
+	assert.NoError(t, err)
+	assert.Contains(t, result.JavaCode, "long countActiveInDept(String deptName);")
+	assert.Nil(t, result.DTO)
+	bound, err := EvaluateStatement(result.XMLCode, "countActiveInDept", map[string]interface{}{"deptName": "x"})
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT COUNT(*) FROM user t LEFT JOIN dept d ON t.dept_id = d.id WHERE d.name = ?", bound.SQL)
+}
@@ -0,0 +1,859 @@
+package generator
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/yourusername/mybatis-generator-gui-go/internal/config"
+	"github.com/yourusername/mybatis-generator-gui-go/internal/database"
+	"github.com/yourusername/mybatis-generator-gui-go/internal/utils"
+)
+
+// SQLColumnLoader 按表名加载列信息（通常为 Connector.GetTableColumns）
+type SQLColumnLoader func(tableName string) ([]*database.TableColumn, error)
+
+// SQLImportParam 导入 SQL 推断出的方法参数
+type SQLImportParam struct {
+	Name       string `json:"name"`       // 参数名
+	JavaType   string `json:"javaType"`   // Java 类型（集合参数为元素类型）
+	JdbcType   string `json:"jdbcType"`   // JDBC 类型（无法推断时为空）
+	ColumnName string `json:"columnName"` // 推断依据的列名（LIMIT/OFFSET 或无法推断时为空）
+	Collection bool   `json:"collection"` // 是否为 IN (?) 集合参数
+}
+
+// SQLImport 原始 SQL 导入结果
+type SQLImport struct {
+	Result    *SnippetResult
+	Operation config.SnippetOperation
+	Params    []SQLImportParam
+	Warnings  []string // 不影响生成的提示（如参数类型无法推断）
+}
+
+// javaIdentifierPattern Java 方法名
+var javaIdentifierPattern = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// sqlReservedWords 不能作为表别名或列名的关键字
+var sqlReservedWords = map[string]bool{
+	"SELECT": true, "FROM": true, "WHERE": true, "JOIN": true, "LEFT": true, "RIGHT": true,
+	"INNER": true, "OUTER": true, "CROSS": true, "FULL": true, "NATURAL": true, "ON": true,
+	"USING": true, "SET": true, "GROUP": true, "ORDER": true, "BY": true, "LIMIT": true,
+	"OFFSET": true, "HAVING": true, "UNION": true, "VALUES": true, "VALUE": true, "FOR": true,
+	"AS": true, "AND": true, "OR": true, "NOT": true, "INTO": true, "IN": true, "LIKE": true,
+	"BETWEEN": true, "IS": true, "NULL": true, "RETURNING": true, "FETCH": true, "WINDOW": true,
+	"STRAIGHT_JOIN": true, "INTERSECT": true, "EXCEPT": true, "MINUS": true,
+}
+
+// sqlComparators 比较运算符
+var sqlComparators = map[string]bool{"=": true, "!=": true, "<>": true, "<": true, ">": true, "<=": true, ">=": true}
+
+type sqlTokenKind int
+
+const (
+	sqlWord   sqlTokenKind = iota // 标识符或关键字（引号标识符已去除引号）
+	sqlString                     // 字符串字面量
+	sqlNumber                     // 数字字面量
+	sqlParam                      // 占位符 ? 或 :name
+	sqlSymbol                     // 运算符及标点
+)
+
+// sqlToken SQL 词法单元
+type sqlToken struct {
+	Kind  sqlTokenKind
+	Text  string
+	Start int // 在原文中的起始位置
+	End   int // 在原文中的结束位置（不含）
+	Depth int // 所在括号深度（括号本身取外层深度）
+}
+
+// is 判断是否为指定关键字或符号（关键字忽略大小写）
+func (t sqlToken) is(text string) bool {
+	if t.Kind == sqlWord {
+		return strings.EqualFold(t.Text, text)
+	}
+	return t.Kind == sqlSymbol && t.Text == text
+}
+
+// tokenizeSQL 将 SQL 拆分为词法单元，跳过空白和注释
+func tokenizeSQL(s string) ([]sqlToken, error) {
+	var tokens []sqlToken
+	depth := 0
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+			continue
+		case strings.HasPrefix(s[i:], "--") || (c == '#' && !strings.HasPrefix(s[i:], "#{")):
+			end := strings.IndexByte(s[i:], '\n')
+			if end < 0 {
+				end = len(s) - i
+			}
+			i += end
+			continue
+		case strings.HasPrefix(s[i:], "/*"):
+			end := strings.Index(s[i+2:], "*/")
+			if end < 0 {
+				return nil, fmt.Errorf("注释未闭合")
+			}
+			i += end + 4
+			continue
+		}
+
+		tok := sqlToken{Start: i, Depth: depth}
+		switch {
+		case c == '\'':
+			j := i + 1
+			for ; j < len(s); j++ {
+				if s[j] == '\\' {
+					j++
+				} else if s[j] == '\'' {
+					if j+1 < len(s) && s[j+1] == '\'' {
+						j++
+						continue
+					}
+					break
+				}
+			}
+			if j >= len(s) {
+				return nil, fmt.Errorf("字符串未闭合")
+			}
+			tok.Kind, tok.End = sqlString, j+1
+			tok.Text = s[i:tok.End]
+		case c == '"' || c == '`':
+			end := strings.IndexByte(s[i+1:], c)
+			if end < 0 {
+				return nil, fmt.Errorf("标识符引号未闭合")
+			}
+			tok.Kind, tok.End = sqlWord, i+end+2
+			tok.Text = s[i+1 : i+end+1]
+		case c == '?':
+			tok.Kind, tok.End, tok.Text = sqlParam, i+1, "?"
+		case c == ':' && i+1 < len(s) && s[i+1] == ':':
+			tok.Kind, tok.End, tok.Text = sqlSymbol, i+2, "::"
+		case c == ':' && i+1 < len(s) && isSQLWordStart(rune(s[i+1])):
+			j := i + 1
+			for j < len(s) && isSQLWordPart(rune(s[j])) {
+				j++
+			}
+			tok.Kind, tok.End, tok.Text = sqlParam, j, s[i:j]
+		case c >= '0' && c <= '9':
+			j := i
+			for j < len(s) && (s[j] >= '0' && s[j] <= '9' || s[j] == '.') {
+				j++
+			}
+			tok.Kind, tok.End, tok.Text = sqlNumber, j, s[i:j]
+		case isSQLWordStart(rune(c)) || c >= 0x80:
+			j := i
+			for j < len(s) && (isSQLWordPart(rune(s[j])) || s[j] >= 0x80) {
+				j++
+			}
+			tok.Kind, tok.End, tok.Text = sqlWord, j, s[i:j]
+		default:
+			tok.Kind, tok.End = sqlSymbol, i+1
+			for _, op := range []string{"<=", ">=", "<>", "!=", "||"} {
+				if strings.HasPrefix(s[i:], op) {
+					tok.End = i + 2
+					break
+				}
+			}
+			tok.Text = s[i:tok.End]
+			if tok.Text == "(" {
+				depth++
+			} else if tok.Text == ")" {
+				depth--
+				tok.Depth = depth
+			}
+		}
+		tokens = append(tokens, tok)
+		i = tok.End
+	}
+	if depth != 0 {
+		return nil, fmt.Errorf("括号不匹配")
+	}
+	return tokens, nil
+}
+
+func isSQLWordStart(r rune) bool {
+	return unicode.IsLetter(r) || r == '_' || r == '$'
+}
+
+func isSQLWordPart(r rune) bool {
+	return isSQLWordStart(r) || unicode.IsDigit(r)
+}
+
+// sqlTableRef SQL 中引用的表
+type sqlTableRef struct {
+	Name    string // 表名（不含 schema）
+	Alias   string
+	Columns map[string]*database.TableColumn // 小写列名 -> 列
+}
+
+// sqlImporter 原始 SQL 导入状态
+type sqlImporter struct {
+	tokens    []sqlToken
+	operation config.SnippetOperation
+	tables    []*sqlTableRef
+	refs      map[string]*sqlTableRef // 小写别名/表名 -> 表
+	insertCol []string                // INSERT 的列名列表
+	issues    []string                // 不支持的写法
+	warnings  []string
+}
+
+func (im *sqlImporter) unsupported(format string, args ...interface{}) {
+	msg := fmt.Sprintf(format, args...)
+	for _, issue := range im.issues {
+		if issue == msg {
+			return
+		}
+	}
+	im.issues = append(im.issues, msg)
+}
+
+// ImportSnippetSQL 将手写 SQL（? 或 :name 占位符）转换为 Mapper 方法和 XML 语句
+// 参数名和类型按占位符所比较的列推断，不支持的写法（子查询、UNION、多条语句等）汇总后报错
+func ImportSnippetSQL(sqlText, methodName, modelType string, load SQLColumnLoader) (*SQLImport, error) {
+	methodName = strings.TrimSpace(methodName)
+	if !javaIdentifierPattern.MatchString(methodName) {
+		return nil, fmt.Errorf("方法名无效: %q", methodName)
+	}
+	tokens, err := tokenizeSQL(sqlText)
+	if err != nil {
+		return nil, fmt.Errorf("SQL 解析失败: %v", err)
+	}
+	for len(tokens) > 0 && tokens[len(tokens)-1].is(";") {
+		tokens = tokens[:len(tokens)-1]
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("SQL 不能为空")
+	}
+
+	im := &sqlImporter{tokens: tokens, refs: make(map[string]*sqlTableRef)}
+	switch strings.ToUpper(tokens[0].Text) {
+	case "SELECT":
+		im.operation = config.OperationSelect
+	case "INSERT":
+		im.operation = config.OperationInsert
+	case "UPDATE":
+		im.operation = config.OperationUpdate
+	case "DELETE":
+		im.operation = config.OperationDelete
+	case "WITH":
+		return nil, fmt.Errorf("SQL 中存在不支持的写法: 不支持 WITH 公用表表达式")
+	default:
+		return nil, fmt.Errorf("仅支持 SELECT/INSERT/UPDATE/DELETE 语句")
+	}
+
+	im.checkStructure()
+	im.collectTables()
+	if len(im.tables) == 0 {
+		im.unsupported("未找到语句操作的表")
+	}
+	if len(im.issues) > 0 {
+		return nil, fmt.Errorf("SQL 中存在不支持的写法: %s", strings.Join(im.issues, "; "))
+	}
+
+	for _, ref := range im.tables {
+		if ref.Columns != nil {
+			continue
+		}
+		columns, err := load(ref.Name)
+		if err != nil {
+			return nil, fmt.Errorf("获取表 %s 列信息失败: %v", ref.Name, err)
+		}
+		cols := make(map[string]*database.TableColumn, len(columns))
+		for _, col := range columns {
+			cols[strings.ToLower(col.ColumnName)] = col
+		}
+		// 同一张表多次引用时共享列信息
+		for _, other := range im.tables {
+			if strings.EqualFold(other.Name, ref.Name) {
+				other.Columns = cols
+			}
+		}
+	}
+
+	params, bindings := im.bindParams()
+	if len(im.issues) > 0 {
+		return nil, fmt.Errorf("SQL 中存在不支持的写法: %s", strings.Join(im.issues, "; "))
+	}
+
+	returnType, resultType := im.selectResultType(modelType)
+	result := &SnippetResult{
+		MethodName: methodName,
+		JavaCode:   renderImportJava(methodName, im.operation, returnType, params),
+		XMLCode:    renderImportXML(methodName, im.operation, resultType, im.renderSQL(sqlText, bindings)),
+		Imports:    importedSQLImports(returnType, params),
+	}
+	return &SQLImport{Result: result, Operation: im.operation, Params: params, Warnings: im.warnings}, nil
+}
+
+// checkStructure 检查无法转换的语句结构
+func (im *sqlImporter) checkStructure() {
+	for i, tok := range im.tokens {
+		switch {
+		case tok.is(";"):
+			im.unsupported("不支持多条语句")
+		case tok.is("UNION") || tok.is("INTERSECT") || tok.is("EXCEPT") || tok.is("MINUS"):
+			im.unsupported("不支持 %s 组合查询", strings.ToUpper(tok.Text))
+		case i > 0 && tok.is("SELECT"):
+			im.unsupported("不支持子查询")
+		case tok.Kind == sqlSymbol && (tok.Text == "{" || tok.Text == "}"):
+			im.unsupported("不支持 MyBatis 动态标签或 #{}/${} 表达式，请使用 ? 或 :name 占位符")
+		case tok.Kind == sqlSymbol && tok.Text == "<" && i+1 < len(im.tokens) && im.tokens[i+1].Kind == sqlWord &&
+			(im.tokens[i+1].is("if") || im.tokens[i+1].is("where") || im.tokens[i+1].is("foreach")):
+			im.unsupported("不支持 MyBatis 动态标签或 #{}/${} 表达式，请使用 ? 或 :name 占位符")
+		}
+	}
+}
+
+// collectTables 收集 FROM / JOIN / UPDATE / INTO 引用的表及别名
+func (im *sqlImporter) collectTables() {
+	tokens := im.tokens
+	for i := 0; i < len(tokens); i++ {
+		tok := tokens[i]
+		if tok.Depth != 0 || !(tok.is("FROM") || tok.is("JOIN") || tok.is("UPDATE") || tok.is("INTO")) {
+			continue
+		}
+		// DELETE t FROM ... 中 FROM 前的别名不是表
+		j := i + 1
+		for {
+			ref, next := im.parseTableRef(j)
+			if ref == nil {
+				break
+			}
+			im.addTable(ref)
+			j = next
+			// FROM a x, b y 逗号分隔的多表
+			if !tok.is("FROM") || j >= len(tokens) || !tokens[j].is(",") {
+				break
+			}
+			j++
+		}
+		if tok.is("INTO") {
+			im.parseInsertColumns(j)
+		}
+	}
+}
+
+// parseTableRef 解析 pos 处的表引用（[schema.]table [AS] [alias]），返回表及下一个位置
+func (im *sqlImporter) parseTableRef(pos int) (*sqlTableRef, int) {
+	tokens := im.tokens
+	if pos >= len(tokens) {
+		return nil, pos
+	}
+	if tokens[pos].is("(") {
+		im.unsupported("不支持子查询或括号内的表引用")
+		return nil, pos
+	}
+	if tokens[pos].Kind != sqlWord || sqlReservedWords[strings.ToUpper(tokens[pos].Text)] {
+		return nil, pos
+	}
+	ref := &sqlTableRef{Name: tokens[pos].Text}
+	pos++
+	if pos+1 < len(tokens) && tokens[pos].is(".") && tokens[pos+1].Kind == sqlWord {
+		ref.Name = tokens[pos+1].Text
+		pos += 2
+	}
+	if pos < len(tokens) && tokens[pos].is("AS") {
+		pos++
+	}
+	if pos < len(tokens) && tokens[pos].Kind == sqlWord && !sqlReservedWords[strings.ToUpper(tokens[pos].Text)] {
+		ref.Alias = tokens[pos].Text
+		pos++
+	}
+	return ref, pos
+}
+
+func (im *sqlImporter) addTable(ref *sqlTableRef) {
+	im.tables = append(im.tables, ref)
+	if ref.Alias != "" {
+		im.refs[strings.ToLower(ref.Alias)] = ref
+	}
+	if _, ok := im.refs[strings.ToLower(ref.Name)]; !ok {
+		im.refs[strings.ToLower(ref.Name)] = ref
+	}
+}
+
+// parseInsertColumns 解析 INSERT INTO t (a, b) VALUES (...) 的列名列表
+func (im *sqlImporter) parseInsertColumns(pos int) {
+	tokens := im.tokens
+	if pos >= len(tokens) || !tokens[pos].is("(") {
+		if pos < len(tokens) && (tokens[pos].is("VALUES") || tokens[pos].is("VALUE")) {
+			im.unsupported("INSERT 需要显式列出列名")
+		}
+		return
+	}
+	for pos++; pos < len(tokens) && !tokens[pos].is(")"); pos++ {
+		if tokens[pos].Kind == sqlWord {
+			im.insertCol = append(im.insertCol, tokens[pos].Text)
+		}
+	}
+	// 仅支持单行 VALUES
+	tuples := 0
+	for ; pos < len(tokens); pos++ {
+		if tokens[pos].is("(") && tokens[pos].Depth == 0 && pos > 0 &&
+			(tokens[pos-1].is("VALUES") || tokens[pos-1].is("VALUE") || tokens[pos-1].is(",")) {
+			tuples++
+		}
+	}
+	if tuples > 1 {
+		im.unsupported("不支持多行 VALUES，请使用批量插入片段")
+	}
+}
+
+// columnAt 解析 k 处的列引用（[alias.]column）
+func (im *sqlImporter) columnAt(k int) (qualifier, name string, ok bool) {
+	if k < 0 || k >= len(im.tokens) {
+		return "", "", false
+	}
+	tok := im.tokens[k]
+	if tok.Kind != sqlWord || sqlReservedWords[strings.ToUpper(tok.Text)] {
+		return "", "", false
+	}
+	if k >= 2 && im.tokens[k-1].is(".") && im.tokens[k-2].Kind == sqlWord {
+		return im.tokens[k-2].Text, tok.Text, true
+	}
+	return "", tok.Text, true
+}
+
+// resolveColumn 在引用的表中查找列；未限定表时先查主表
+func (im *sqlImporter) resolveColumn(qualifier, name string) *database.TableColumn {
+	if qualifier != "" {
+		ref, ok := im.refs[strings.ToLower(qualifier)]
+		if !ok {
+			im.unsupported("未知的表别名: %s", qualifier)
+			return nil
+		}
+		if col, ok := ref.Columns[strings.ToLower(name)]; ok {
+			return col
+		}
+		im.unsupported("列不存在: %s.%s", ref.Name, name)
+		return nil
+	}
+	for _, ref := range im.tables {
+		if col, ok := ref.Columns[strings.ToLower(name)]; ok {
+			return col
+		}
+	}
+	im.unsupported("列不存在: %s", name)
+	return nil
+}
+
+// paramBinding 占位符替换信息
+type paramBinding struct {
+	Start, End int // 原文中被替换的范围（集合参数包含两侧括号）
+	Param      *SQLImportParam
+}
+
+// paramTarget 占位符推断结果
+type paramTarget struct {
+	Column     *database.TableColumn
+	Suffix     string // 参数名后缀（BETWEEN 的 Start/End）
+	Fixed      string // 固定参数名（limit/offset）
+	Collection bool
+	Start, End int
+}
+
+// bindParams 为每个占位符推断参数名和类型
+func (im *sqlImporter) bindParams() ([]SQLImportParam, []paramBinding) {
+	var params []*SQLImportParam
+	byName := make(map[string]*SQLImportParam)
+	used := make(map[string]int)
+	var bindings []paramBinding
+	positional, named := 0, 0
+
+	for i, tok := range im.tokens {
+		if tok.Kind != sqlParam {
+			continue
+		}
+		target, found := im.inferParam(i)
+		if tok.Text == "?" {
+			positional++
+		} else {
+			named++
+		}
+
+		var param *SQLImportParam
+		if tok.Text != "?" {
+			name := tok.Text[1:]
+			if existing, ok := byName[name]; ok {
+				param = existing
+			} else {
+				param = &SQLImportParam{Name: name, JavaType: "Object"}
+				byName[name] = param
+				params = append(params, param)
+			}
+		} else {
+			if !found {
+				im.unsupported("第%d个占位符无法推断对应的列", positional)
+				continue
+			}
+			name := target.Fixed
+			if name == "" {
+				name = utils.DBStringToCamelCase(target.Column.ColumnName) + target.Suffix
+				if target.Collection {
+					name += "List"
+				}
+			}
+			used[name]++
+			if n := used[name]; n > 1 {
+				name += strconv.Itoa(n)
+			}
+			param = &SQLImportParam{Name: name, JavaType: "Object"}
+			params = append(params, param)
+		}
+
+		if found {
+			switch {
+			case target.Column != nil:
+				param.JavaType, param.JdbcType = target.Column.JavaType, target.Column.JdbcType
+				param.ColumnName = target.Column.ColumnName
+			case target.Fixed != "":
+				param.JavaType, param.JdbcType = "Integer", "INTEGER"
+			}
+			param.Collection = param.Collection || target.Collection
+		}
+		start, end := tok.Start, tok.End
+		if found && target.Collection {
+			start, end = target.Start, target.End
+		}
+		bindings = append(bindings, paramBinding{Start: start, End: end, Param: param})
+	}
+
+	if positional > 0 && named > 0 {
+		im.unsupported("不支持混用 ? 与 :name 占位符")
+	}
+	result := make([]SQLImportParam, len(params))
+	for i, p := range params {
+		if p.JavaType == "Object" && p.ColumnName == "" {
+			im.warnings = append(im.warnings, fmt.Sprintf("参数 %s 无法推断对应的列，类型使用 Object", p.Name))
+		}
+		result[i] = *p
+	}
+	return result, bindings
+}
+
+// inferParam 根据占位符上下文推断对应的列
+func (im *sqlImporter) inferParam(i int) (paramTarget, bool) {
+	tokens := im.tokens
+	at := func(k int) sqlToken {
+		if k < 0 || k >= len(tokens) {
+			return sqlToken{Kind: -1}
+		}
+		return tokens[k]
+	}
+	column := func(k int) *database.TableColumn {
+		qualifier, name, ok := im.columnAt(k)
+		if !ok {
+			return nil
+		}
+		return im.resolveColumn(qualifier, name)
+	}
+	prev, next := at(i-1), at(i+1)
+
+	// LIMIT ? / LIMIT ?, ? / OFFSET ? / FETCH FIRST ? ROWS
+	switch {
+	case prev.is("LIMIT") && next.is(","):
+		return paramTarget{Fixed: "offset"}, true
+	case prev.is("LIMIT") || prev.is("FIRST") || prev.is("NEXT"):
+		return paramTarget{Fixed: "limit"}, true
+	case prev.is(",") && at(i-2).Kind == sqlParam && at(i-3).is("LIMIT"):
+		return paramTarget{Fixed: "limit"}, true
+	case prev.is("OFFSET"):
+		return paramTarget{Fixed: "offset"}, true
+	}
+
+	// BETWEEN ? AND ?
+	if prev.is("BETWEEN") {
+		if col := column(im.skipNot(i - 2)); col != nil {
+			return paramTarget{Column: col, Suffix: "Start"}, true
+		}
+		return paramTarget{}, false
+	}
+	if prev.is("AND") && at(i-3).is("BETWEEN") {
+		if col := column(im.skipNot(i - 4)); col != nil {
+			return paramTarget{Column: col, Suffix: "End"}, true
+		}
+		return paramTarget{}, false
+	}
+
+	// 括号内：IN 列表或 INSERT VALUES
+	if open := im.enclosingParen(i); open >= 0 {
+		before := at(open - 1)
+		if before.is("IN") {
+			col := column(im.skipNot(open - 2))
+			if col == nil {
+				return paramTarget{}, false
+			}
+			if prev.is("(") && next.is(")") {
+				return paramTarget{Column: col, Collection: true, Start: tokens[open].Start, End: next.End}, true
+			}
+			return paramTarget{Column: col}, true
+		}
+		if (before.is("VALUES") || before.is("VALUE")) && (prev.is("(") || prev.is(",")) && (next.is(",") || next.is(")")) {
+			index := 0
+			for k := open + 1; k < i; k++ {
+				if tokens[k].is(",") && tokens[k].Depth == tokens[i].Depth {
+					index++
+				}
+			}
+			if index < len(im.insertCol) {
+				if col := im.resolveColumn("", im.insertCol[index]); col != nil {
+					return paramTarget{Column: col}, true
+				}
+			}
+			return paramTarget{}, false
+		}
+	}
+
+	// column = ? / column LIKE ? / column NOT LIKE ?
+	if prev.Kind == sqlSymbol && sqlComparators[prev.Text] {
+		if col := column(i - 2); col != nil {
+			return paramTarget{Column: col}, true
+		}
+	}
+	if prev.is("LIKE") || prev.is("ILIKE") {
+		if col := column(im.skipNot(i - 2)); col != nil {
+			return paramTarget{Column: col}, true
+		}
+	}
+	// ? = column
+	if next.Kind == sqlSymbol && sqlComparators[next.Text] {
+		k := i + 2
+		if at(i + 3).is(".") {
+			k = i + 4
+		}
+		if col := column(k); col != nil {
+			return paramTarget{Column: col}, true
+		}
+	}
+	return paramTarget{}, false
+}
+
+// skipNot 跳过运算符前的 NOT（column NOT IN / NOT LIKE / NOT BETWEEN）
+func (im *sqlImporter) skipNot(k int) int {
+	if k >= 0 && k < len(im.tokens) && im.tokens[k].is("NOT") {
+		return k - 1
+	}
+	return k
+}
+
+// enclosingParen 查找 i 所在的最内层左括号位置（不存在返回 -1）
+func (im *sqlImporter) enclosingParen(i int) int {
+	depth := im.tokens[i].Depth
+	if depth == 0 {
+		return -1
+	}
+	for k := i - 1; k >= 0; k-- {
+		if im.tokens[k].is("(") && im.tokens[k].Depth == depth-1 {
+			return k
+		}
+	}
+	return -1
+}
+
+// selectResultType 推断查询的返回类型：
+// 单表的普通列返回实体类，单个 COUNT 返回 long，其余（表达式、多表列）返回 Map
+func (im *sqlImporter) selectResultType(modelType string) (returnType, resultType string) {
+	if im.operation != config.OperationSelect {
+		return "int", ""
+	}
+
+	// 拆分 SELECT 与 FROM 之间的列
+	var items [][]sqlToken
+	var current []sqlToken
+	for _, tok := range im.tokens[1:] {
+		if tok.Depth == 0 && tok.is("FROM") {
+			break
+		}
+		if tok.Depth == 0 && tok.is(",") {
+			items = append(items, current)
+			current = nil
+			continue
+		}
+		if len(items) == 0 && len(current) == 0 && tok.is("DISTINCT") {
+			continue
+		}
+		current = append(current, tok)
+	}
+	items = append(items, current)
+
+	if len(items) == 1 && len(items[0]) >= 3 && items[0][0].is("COUNT") && items[0][1].is("(") {
+		return "long", "java.lang.Long"
+	}
+
+	simpleModel := modelType[strings.LastIndex(modelType, ".")+1:]
+	if len(im.tables) == 1 {
+		main := im.tables[0]
+		plain := true
+		for _, item := range items {
+			if !isPlainSelectItem(item, main) {
+				plain = false
+				break
+			}
+		}
+		if plain {
+			return "List<" + simpleModel + ">", modelType
+		}
+	}
+	im.warnings = append(im.warnings, "查询列包含表达式、别名或多表列，返回 List<Map<String, Object>>")
+	return "List<Map<String, Object>>", "map"
+}
+
+// isPlainSelectItem 判断查询列是否为主表的普通列（*、t.*、col、t.col）
+func isPlainSelectItem(item []sqlToken, main *sqlTableRef) bool {
+	qualifierOK := func(tok sqlToken) bool {
+		return strings.EqualFold(tok.Text, main.Alias) || strings.EqualFold(tok.Text, main.Name)
+	}
+	switch len(item) {
+	case 1:
+		return item[0].is("*") || item[0].Kind == sqlWord
+	case 3:
+		return item[0].Kind == sqlWord && qualifierOK(item[0]) && item[1].is(".") &&
+			(item[2].is("*") || item[2].Kind == sqlWord)
+	}
+	return false
+}
+
+// renderSQL 将占位符替换为 #{} 参数（IN 集合参数使用 <foreach>），并转义 XML 特殊字符
+func (im *sqlImporter) renderSQL(sqlText string, bindings []paramBinding) string {
+	end := im.tokens[len(im.tokens)-1].End
+	var b strings.Builder
+	pos := im.tokens[0].Start
+	for _, binding := range bindings {
+		b.WriteString(escapeXMLText(sqlText[pos:binding.Start]))
+		p := binding.Param
+		ref := p.Name
+		if p.Collection {
+			ref = "item"
+		}
+		if p.JdbcType != "" {
+			ref += ",jdbcType=" + p.JdbcType
+		}
+		if p.Collection {
+			b.WriteString(fmt.Sprintf(`<foreach collection="%s" item="item" open="(" separator="," close=")">#{%s}</foreach>`, p.Name, ref))
+		} else {
+			b.WriteString("#{" + ref + "}")
+		}
+		pos = binding.End
+	}
+	b.WriteString(escapeXMLText(sqlText[pos:end]))
+	return indentSQL(b.String(), "        ")
+}
+
+// escapeXMLText 转义 XML 文本中的 & 和 <
+func escapeXMLText(s string) string {
+	s = strings.ReplaceAll(s, "&", "&amp;")
+	return strings.ReplaceAll(s, "<", "&lt;")
+}
+
+// indentSQL 去除公共缩进和空行后统一缩进
+func indentSQL(sql, indent string) string {
+	lines := strings.Split(strings.ReplaceAll(sql, "\r\n", "\n"), "\n")
+	var kept []string
+	common := -1
+	for i, line := range lines {
+		line = strings.TrimRight(line, " \t")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		// 首行从语句开头截取，不参与公共缩进计算
+		if i > 0 {
+			n := len(line) - len(strings.TrimLeft(line, " \t"))
+			if common < 0 || n < common {
+				common = n
+			}
+		}
+		kept = append(kept, line)
+	}
+	for i := range kept {
+		if i > 0 {
+			kept[i] = kept[i][common:]
+		}
+		kept[i] = indent + kept[i]
+	}
+	return strings.Join(kept, "\n")
+}
+
+// renderImportJava 生成导入 SQL 的 Mapper 方法声明
+func renderImportJava(methodName string, op config.SnippetOperation, returnType string, params []SQLImportParam) string {
+	parts := make([]string, len(params))
+	annotated := len(params) > 1 || (len(params) == 1 && params[0].Collection)
+	for i, p := range params {
+		javaType := p.JavaType
+		if p.Collection {
+			javaType = "List<" + javaType + ">"
+		}
+		if annotated {
+			parts[i] = fmt.Sprintf("@Param(\"%s\") %s %s", p.Name, javaType, p.Name)
+		} else {
+			parts[i] = fmt.Sprintf("%s %s", javaType, p.Name)
+		}
+	}
+
+	var javaBuilder strings.Builder
+	javaBuilder.WriteString("    /**\n")
+	javaBuilder.WriteString(fmt.Sprintf("     * 导入SQL%s - %s\n", importOperationLabel(op), methodName))
+	javaBuilder.WriteString("     */\n")
+	javaBuilder.WriteString(fmt.Sprintf("    %s %s(%s);\n", returnType, methodName, strings.Join(parts, ", ")))
+	return javaBuilder.String()
+}
+
+// renderImportXML 生成导入 SQL 的 XML 语句
+func renderImportXML(methodName string, op config.SnippetOperation, resultType, sql string) string {
+	tag := string(op)
+	attrs := fmt.Sprintf(`id="%s"`, methodName)
+	if resultType != "" {
+		attrs += fmt.Sprintf(` resultType="%s"`, resultType)
+	}
+	return fmt.Sprintf("    <!-- 导入SQL%s - %s -->\n    <%s %s>\n%s\n    </%s>",
+		importOperationLabel(op), methodName, tag, attrs, sql, tag)
+}
+
+func importOperationLabel(op config.SnippetOperation) string {
+	switch op {
+	case config.OperationSelect:
+		return "查询"
+	case config.OperationInsert:
+		return "插入"
+	case config.OperationUpdate:
+		return "更新"
+	case config.OperationDelete:
+		return "删除"
+	}
+	return ""
+}
+
+// importedSQLImports 收集导入 SQL 方法需要的 import
+func importedSQLImports(returnType string, params []SQLImportParam) []string {
+	importSet := make(map[string]bool)
+	if strings.HasPrefix(returnType, "List<") {
+		importSet["java.util.List"] = true
+	}
+	if strings.Contains(returnType, "Map<") {
+		importSet["java.util.Map"] = true
+	}
+	if len(params) > 1 || (len(params) == 1 && params[0].Collection) {
+		importSet["org.apache.ibatis.annotations.Param"] = true
+	}
+	paramTypes := make([]string, len(params))
+	for i, p := range params {
+		if p.Collection {
+			importSet["java.util.List"] = true
+		}
+		paramTypes[i] = p.JavaType
+	}
+	for _, imp := range javaTypeImports(paramTypes) {
+		importSet[imp] = true
+	}
+	imports := make([]string, 0, len(importSet))
+	for imp := range importSet {
+		imports = append(imports, imp)
+	}
+	sort.Strings(imports)
+	return imports
+}
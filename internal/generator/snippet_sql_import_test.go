@@ -0,0 +1,106 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yourusername/mybatis-generator-gui-go/internal/config"
+	"github.com/yourusername/mybatis-generator-gui-go/internal/database"
+)
+
+func sqlImportLoader(tableName string) ([]*database.TableColumn, error) {
+	switch strings.ToLower(tableName) {
+	case "user":
+		return methodParserColumns(), nil
+	case "orders":
+		return []*database.TableColumn{
+			{ColumnName: "id", JavaType: "Long", JdbcType: "BIGINT"},
+			{ColumnName: "user_id", JavaType: "Long", JdbcType: "BIGINT"},
+			{ColumnName: "amount", JavaType: "BigDecimal", JdbcType: "DECIMAL"},
+		}, nil
+	}
+	return nil, fmt.Errorf("表不存在: %s", tableName)
+}
+
+func TestImportSnippetSQL_Select(t *testing.T) {
+	sql := `SELECT * FROM user u
+            WHERE u.status = ? AND user_id IN (?) AND created_at BETWEEN ? AND ?
+            ORDER BY id DESC LIMIT ?;`
+	imported, err := ImportSnippetSQL(sql, "findActive", "com.example.User", sqlImportLoader)
+	assert.NoError(t, err)
+	assert.Equal(t, config.OperationSelect, imported.Operation)
+
+	names := make([]string, len(imported.Params))
+	for i, p := range imported.Params {
+		names[i] = p.Name
+	}
+	assert.Equal(t, []string{"status", "userIdList", "createdAtStart", "createdAtEnd", "limit"}, names)
+	assert.True(t, imported.Params[1].Collection)
+
+	result := imported.Result
+	assert.Contains(t, result.JavaCode, `List<User> findActive(@Param("status") Integer status, @Param("userIdList") List<Long> userIdList, @Param("createdAtStart") Date createdAtStart`)
+	assert.Contains(t, result.XMLCode, `<select id="findActive" resultType="com.example.User">`)
+	assert.Contains(t, result.XMLCode, "WHERE u.status = #{status,jdbcType=INTEGER} AND user_id IN <foreach collection=\"userIdList\" item=\"item\" open=\"(\" separator=\",\" close=\")\">#{item,jdbcType=BIGINT}</foreach>")
+	assert.Contains(t, result.XMLCode, "\n        ORDER BY id DESC LIMIT #{limit,jdbcType=INTEGER}\n    </select>")
+	assert.Equal(t, []string{"java.util.Date", "java.util.List", "org.apache.ibatis.annotations.Param"}, result.Imports)
+}
+
+func TestImportSnippetSQL_NamedParamsAndResultShapes(t *testing.T) {
+	imported, err := ImportSnippetSQL("select count(*) from user where status = :status and :status <> 0", "countActive", "com.example.User", sqlImportLoader)
+	assert.NoError(t, err)
+	assert.Len(t, imported.Params, 1)
+	assert.Contains(t, imported.Result.JavaCode, "long countActive(Integer status);")
+	assert.Contains(t, imported.Result.XMLCode, `resultType="java.lang.Long"`)
+	assert.Contains(t, imported.Result.XMLCode, "#{status,jdbcType=INTEGER} &lt;> 0")
+
+	imported, err = ImportSnippetSQL("SELECT u.user_name, SUM(o.amount) AS total FROM user u JOIN orders o ON o.user_id = u.id WHERE o.amount >= :minAmount GROUP BY u.user_name",
+		"sumByUser", "com.example.User", sqlImportLoader)
+	assert.NoError(t, err)
+	assert.Contains(t, imported.Result.JavaCode, "List<Map<String, Object>> sumByUser(BigDecimal minAmount);")
+	assert.Contains(t, imported.Result.XMLCode, `resultType="map"`)
+	assert.Contains(t, imported.Result.Imports, "java.util.Map")
+	assert.NotEmpty(t, imported.Warnings)
+}
+
+func TestImportSnippetSQL_DML(t *testing.T) {
+	imported, err := ImportSnippetSQL("INSERT INTO user (user_id, user_name) VALUES (?, ?)", "insertName", "com.example.User", sqlImportLoader)
+	assert.NoError(t, err)
+	assert.Contains(t, imported.Result.JavaCode, `int insertName(@Param("userId") Long userId, @Param("userName") String userName);`)
+	assert.Contains(t, imported.Result.XMLCode, "VALUES (#{userId,jdbcType=BIGINT}, #{userName,jdbcType=VARCHAR})")
+
+	imported, err = ImportSnippetSQL("UPDATE user SET status = ? WHERE status = ?", "resetStatus", "com.example.User", sqlImportLoader)
+	assert.NoError(t, err)
+	assert.Equal(t, "status", imported.Params[0].Name)
+	assert.Equal(t, "status2", imported.Params[1].Name)
+
+	imported, err = ImportSnippetSQL("DELETE FROM user WHERE expire_time < ?", "purge", "com.example.User", sqlImportLoader)
+	assert.NoError(t, err)
+	assert.Contains(t, imported.Result.JavaCode, "int purge(Date expireTime);")
+	assert.Contains(t, imported.Result.XMLCode, "expire_time &lt; #{expireTime,jdbcType=TIMESTAMP}")
+}
+
+func TestImportSnippetSQL_Unsupported(t *testing.T) {
+	_, err := ImportSnippetSQL("SELECT * FROM user WHERE id IN (SELECT user_id FROM orders) UNION SELECT * FROM user", "bad", "com.example.User", sqlImportLoader)
+	assert.ErrorContains(t, err, "不支持子查询")
+	assert.ErrorContains(t, err, "不支持 UNION 组合查询")
+
+	_, err = ImportSnippetSQL("SELECT * FROM user WHERE DATE(created_at) = ?", "bad", "com.example.User", sqlImportLoader)
+	assert.ErrorContains(t, err, "第1个占位符无法推断对应的列")
+
+	_, err = ImportSnippetSQL("SELECT * FROM user WHERE nickname = ?", "bad", "com.example.User", sqlImportLoader)
+	assert.ErrorContains(t, err, "列不存在: nickname")
+
+	_, err = ImportSnippetSQL("SELECT * FROM user WHERE id = ? AND status = :status", "bad", "com.example.User", sqlImportLoader)
+	assert.ErrorContains(t, err, "不支持混用")
+
+	_, err = ImportSnippetSQL("TRUNCATE user", "bad", "com.example.User", sqlImportLoader)
+	assert.ErrorContains(t, err, "仅支持 SELECT/INSERT/UPDATE/DELETE")
+
+	_, err = ImportSnippetSQL("SELECT * FROM user; DELETE FROM user", "bad", "com.example.User", sqlImportLoader)
+	assert.ErrorContains(t, err, "不支持多条语句")
+
+	_, err = ImportSnippetSQL("SELECT * FROM missing WHERE id = ?", "bad", "com.example.User", sqlImportLoader)
+	assert.ErrorContains(t, err, "获取表 missing 列信息失败")
+}
@@ -0,0 +1,87 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/yourusername/mybatis-generator-gui-go/internal/config"
+	"github.com/yourusername/mybatis-generator-gui-go/internal/database"
+)
+
+// ValidateSnippet 校验片段引用的列都存在于表中（列名忽略大小写）
+// joinColumns 为关联表的列信息（键为小写表名），未提供的关联表不做校验
+// 表别名直接拼入 SQL，需为合法标识符
+func ValidateSnippet(cfg *config.SnippetConfig, columns []*database.TableColumn, joinColumns map[string][]*database.TableColumn) error {
+	if cfg.TableAlias != "" && !javaIdentifierPattern.MatchString(cfg.TableAlias) {
+		return fmt.Errorf("无效的表别名: %s", cfg.TableAlias)
+	}
+	for _, join := range cfg.Joins {
+		if join.Alias != "" && !javaIdentifierPattern.MatchString(join.Alias) {
+			return fmt.Errorf("无效的表别名: %s", join.Alias)
+		}
+	}
+
+	// 别名 -> 列集合（nil 表示不校验）
+	columnSets := map[string]map[string]bool{snippetMainAlias(cfg): columnSet(columns)}
+	aliasTables := map[string]string{}
+	for _, join := range cfg.Joins {
+		aliasTables[join.Alias] = join.TableName
+		if cols, ok := joinColumns[strings.ToLower(join.TableName)]; ok {
+			columnSets[join.Alias] = columnSet(cols)
+		} else {
+			columnSets[join.Alias] = nil
+		}
+	}
+
+	var missing []string
+	check := func(alias, columnName string) {
+		if columnName == "" || columnName == "*" {
+			return
+		}
+		alias = fieldTableAlias(cfg, alias)
+		set, ok := columnSets[alias]
+		if !ok {
+			missing = append(missing, alias+"."+columnName)
+			return
+		}
+		if set == nil || set[strings.ToLower(columnName)] {
+			return
+		}
+		if table, isJoin := aliasTables[alias]; isJoin {
+			missing = append(missing, table+"."+columnName)
+		} else {
+			missing = append(missing, columnName)
+		}
+	}
+	for _, fields := range [][]config.SnippetField{cfg.SelectFields, cfg.WhereFields, cfg.InsertFields, cfg.SetFields, cfg.GroupByFields, cfg.HavingFields} {
+		for _, f := range fields {
+			check(f.TableAlias, f.ColumnName)
+		}
+	}
+	for _, f := range cfg.OrderByFields {
+		check(f.TableAlias, f.ColumnName)
+	}
+	if cfg.MapKey != nil {
+		check(cfg.MapKey.TableAlias, cfg.MapKey.ColumnName)
+	}
+	for _, join := range cfg.Joins {
+		for _, on := range join.On {
+			check(on.LeftAlias, on.LeftColumn)
+			check(join.Alias, on.RightColumn)
+		}
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("列不存在: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// columnSet 将列信息转换为小写列名集合
+func columnSet(columns []*database.TableColumn) map[string]bool {
+	set := make(map[string]bool, len(columns))
+	for _, col := range columns {
+		set[strings.ToLower(col.ColumnName)] = true
+	}
+	return set
+}
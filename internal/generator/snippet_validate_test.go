@@ -0,0 +1,46 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yourusername/mybatis-generator-gui-go/internal/config"
+	"github.com/yourusername/mybatis-generator-gui-go/internal/database"
+)
+
+func TestValidateSnippet(t *testing.T) {
+	columns := []*database.TableColumn{{ColumnName: "id"}, {ColumnName: "USER_NAME"}, {ColumnName: "status"}}
+
+	valid := &config.SnippetConfig{
+		SelectFields:  []config.SnippetField{{ColumnName: "*"}},
+		WhereFields:   []config.SnippetField{{ColumnName: "user_name"}},
+		OrderByFields: []config.OrderByField{{ColumnName: "id"}},
+	}
+	assert.NoError(t, ValidateSnippet(valid, columns, nil))
+
+	invalid := &config.SnippetConfig{
+		WhereFields:   []config.SnippetField{{ColumnName: "email"}},
+		OrderByFields: []config.OrderByField{{ColumnName: "created_at"}},
+	}
+	err := ValidateSnippet(invalid, columns, nil)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "email")
+	assert.Contains(t, err.Error(), "created_at")
+
+	// 别名直接拼入 SQL，不能包含标识符以外的字符
+	injected := &config.SnippetConfig{
+		Joins: []config.SnippetJoin{{TableName: "role", Alias: "r; COMMIT"}},
+	}
+	assert.ErrorContains(t, ValidateSnippet(injected, columns, nil), "无效的表别名: r; COMMIT")
+}
+
+func TestGenerateSnippet_MethodName(t *testing.T) {
+	cfg := &config.SnippetConfig{
+		MethodName:  "findByStatus",
+		Operation:   config.OperationSelect,
+		WhereFields: []config.SnippetField{{ColumnName: "status", FieldName: "status", JavaType: "Integer", Operator: "="}},
+	}
+	result, err := GenerateSnippet(cfg, "UserMapper", "com.example.User", "user", NewDialect(nil))
+	assert.NoError(t, err)
+	assert.Equal(t, "findByStatus", result.MethodName)
+}
@@ -0,0 +1,65 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yourusername/mybatis-generator-gui-go/internal/config"
+)
+
+func TestGenerateSnippet_OptionalWhere(t *testing.T) {
+	cfg := &config.SnippetConfig{
+		MethodName: "search",
+		Operation:  config.OperationSelect,
+		WhereFields: []config.SnippetField{
+			{ColumnName: "deleted", FieldName: "deleted", JavaType: "Integer", JdbcType: "INTEGER", Operator: "=", IsFixed: true, FixedValue: "0"},
+			{ColumnName: "user_name", FieldName: "userName", JavaType: "String", JdbcType: "VARCHAR", Operator: "LIKE", Optional: true},
+			{ColumnName: "status", FieldName: "status", JavaType: "Integer", JdbcType: "INTEGER", Operator: "=", Optional: true},
+			{ColumnName: "dept_id", FieldName: "deptIds", JavaType: "Long", JdbcType: "BIGINT", Operator: "IN", Optional: true},
+		},
+	}
+	result, err := GenerateSnippet(cfg, "UserMapper", "com.example.User", "user", NewDialect(nil))
+	assert.NoError(t, err)
+
+	assert.Contains(t, result.XMLCode, "<where>\n            AND deleted = 0")
+	assert.Contains(t, result.XMLCode, `<if test="userName != null and userName != ''">`)
+	assert.Contains(t, result.XMLCode, `<if test="status != null">`)
+	assert.Contains(t, result.XMLCode, `<if test="deptIds != null and deptIds.size() > 0">`)
+	assert.Contains(t, result.XMLCode, `<foreach collection="deptIds" item="item" open="(" separator="," close=")">`)
+	assert.Contains(t, result.XMLCode, "</where>")
+	assert.NotContains(t, result.XMLCode, "<choose>")
+}
+
+func TestGenerateSnippet_SingleOptionalParamKeepsParamAnnotation(t *testing.T) {
+	cfg := &config.SnippetConfig{
+		MethodName:  "search",
+		Operation:   config.OperationSelect,
+		WhereFields: []config.SnippetField{{ColumnName: "status", FieldName: "status", JavaType: "Integer", JdbcType: "INTEGER", Optional: true}},
+	}
+	result, err := GenerateSnippet(cfg, "UserMapper", "com.example.User", "user", NewDialect(nil))
+	assert.NoError(t, err)
+	assert.Contains(t, result.JavaCode, `search(@Param("status") Integer status);`)
+	assert.Contains(t, result.Imports, "org.apache.ibatis.annotations.Param")
+}
+
+func TestGenerateSnippet_RequiredInGuard(t *testing.T) {
+	cfg := &config.SnippetConfig{
+		MethodName:  "selectByIds",
+		Operation:   config.OperationSelect,
+		WhereFields: []config.SnippetField{{ColumnName: "id", FieldName: "ids", JavaType: "Long", JdbcType: "BIGINT", Operator: "IN"}},
+	}
+	result, err := GenerateSnippet(cfg, "UserMapper", "com.example.User", "user", NewDialect(nil))
+	assert.NoError(t, err)
+	assert.Contains(t, result.XMLCode, `<when test="ids != null and ids.size() > 0">`)
+	assert.Contains(t, result.XMLCode, "<otherwise>1 = 0</otherwise>")
+	assert.Contains(t, result.JavaCode, `selectByIds(@Param("ids") List<Long> ids);`)
+}
+
+func TestGenerateSnippet_DeleteRequiresMandatoryCondition(t *testing.T) {
+	cfg := &config.SnippetConfig{
+		Operation:   config.OperationDelete,
+		WhereFields: []config.SnippetField{{ColumnName: "status", FieldName: "status", JavaType: "Integer", JdbcType: "INTEGER", Optional: true}},
+	}
+	_, err := GenerateSnippet(cfg, "UserMapper", "com.example.User", "user", NewDialect(nil))
+	assert.Error(t, err)
+}
@@ -0,0 +1,52 @@
+package generator
+
+import (
+	"github.com/yourusername/mybatis-generator-gui-go/internal/config"
+)
+
+// ConfigForTable 生成单表配置：按命名规则推导类名与Mapper名，再应用该表的覆盖配置
+func ConfigForTable(base *config.GeneratorConfig, tableName string, namer *Namer) config.GeneratorConfig {
+	tableConfig := *base
+	tableConfig.TableName = tableName
+	tableConfig.DomainObjectName, tableConfig.MapperName = tableClassNames(base, tableName, namer)
+
+	override, ok := base.FindTableOverride(tableName)
+	if !ok {
+		return tableConfig
+	}
+	if override.GenerateKeys != "" {
+		tableConfig.GenerateKeys = override.GenerateKeys
+	}
+	if override.IgnoredColumns != nil {
+		tableConfig.IgnoredColumns = override.IgnoredColumns
+	}
+	if override.ColumnOverrides != nil {
+		tableConfig.ColumnOverrides = override.ColumnOverrides
+	}
+	if override.KeyStrategy != "" {
+		tableConfig.KeyStrategy = override.KeyStrategy
+	}
+	if override.SequenceName != "" {
+		tableConfig.SequenceName = override.SequenceName
+	}
+	return tableConfig
+}
+
+// tableClassNames 获取表的实体类名与Mapper名（优先使用单表覆盖配置）
+func tableClassNames(cfg *config.GeneratorConfig, tableName string, namer *Namer) (domainObjectName, mapperName string) {
+	domainObjectName = namer.DomainObjectName(tableName)
+	mapperName = namer.MapperName(tableName)
+
+	override, ok := cfg.FindTableOverride(tableName)
+	if !ok {
+		return domainObjectName, mapperName
+	}
+	if override.DomainObjectName != "" {
+		domainObjectName = override.DomainObjectName
+		mapperName = namer.MapperNameForDomain(domainObjectName)
+	}
+	if override.MapperName != "" {
+		mapperName = override.MapperName
+	}
+	return domainObjectName, mapperName
+}
@@ -0,0 +1,53 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yourusername/mybatis-generator-gui-go/internal/config"
+)
+
+func TestConfigForTable(t *testing.T) {
+	base := &config.GeneratorConfig{
+		GenerateKeys:   "id",
+		KeyStrategy:    config.KeyStrategyIdentity,
+		IgnoredColumns: []string{"deleted"},
+		Naming:         config.NamingRules{TablePrefixes: []string{"t_"}, ModelSuffix: "Entity"},
+		TableOverrides: map[string]config.TableOverride{
+			"t_user": {
+				DomainObjectName: "AccountEntity",
+				GenerateKeys:     "user_id",
+				KeyStrategy:      config.KeyStrategySequence,
+				SequenceName:     "seq_user",
+				IgnoredColumns:   []string{"password"},
+				ColumnOverrides:  []config.ColumnOverride{{ColumnName: "status", JavaType: "UserStatus"}},
+			},
+			"T_ORDER": {MapperName: "OrderRepository"},
+		},
+	}
+	namer, err := NewNamer(base.Naming)
+	assert.NoError(t, err)
+
+	user := ConfigForTable(base, "t_user", namer)
+	assert.Equal(t, "t_user", user.TableName)
+	assert.Equal(t, "AccountEntity", user.DomainObjectName)
+	assert.Equal(t, "AccountMapper", user.MapperName, "未指定Mapper名时由实体类名推导")
+	assert.Equal(t, "user_id", user.GenerateKeys)
+	assert.Equal(t, config.KeyStrategySequence, user.KeyStrategy)
+	assert.Equal(t, "seq_user", user.SequenceName)
+	assert.Equal(t, []string{"password"}, user.IgnoredColumns)
+	assert.Len(t, user.ColumnOverrides, 1)
+
+	// 表名忽略大小写匹配，未覆盖的项沿用全局配置
+	order := ConfigForTable(base, "t_order", namer)
+	assert.Equal(t, "OrderEntity", order.DomainObjectName)
+	assert.Equal(t, "OrderRepository", order.MapperName)
+	assert.Equal(t, "id", order.GenerateKeys)
+	assert.Equal(t, config.KeyStrategyIdentity, order.KeyStrategy)
+	assert.Equal(t, []string{"deleted"}, order.IgnoredColumns)
+
+	item := ConfigForTable(base, "t_item", namer)
+	assert.Equal(t, "ItemEntity", item.DomainObjectName)
+	assert.Equal(t, "ItemMapper", item.MapperName)
+	assert.Empty(t, base.TableName, "不修改全局配置")
+}
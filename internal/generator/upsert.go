@@ -0,0 +1,108 @@
+package generator
+
+import (
+	"log"
+	"strings"
+
+	"github.com/yourusername/mybatis-generator-gui-go/internal/config"
+)
+
+// Upsert 插入或更新（upsert）语句的生成数据
+type Upsert struct {
+	ConflictName    string           // 冲突判定来源：PRIMARY 或唯一索引名
+	ConflictColumns []*ColumnMapping // 冲突判定列（主键或唯一索引列）
+	InsertColumns   []*ColumnMapping // 插入列（总是包含冲突判定列）
+	UpdateColumns   []*ColumnMapping // 冲突时更新的列（不含冲突列与主键）
+	Batch           bool             // 是否生成批量 upsert
+	SequenceKey     *KeyGeneration   // 序列主键（sequence 策略），插入时未提供主键则取序列下一个值
+}
+
+// IsSequenceKey 判断列是否为序列生成的主键
+func (u *Upsert) IsSequenceKey(col *ColumnMapping) bool {
+	return u.SequenceKey != nil && col.ColumnName == u.SequenceKey.KeyColumn
+}
+
+// buildUpsert 构建 upsert 数据：冲突列取自 UpsertConflictIndex 指定的唯一索引，未指定时使用主键
+func (g *Generator) buildUpsert(mappings, insertColumns []*ColumnMapping) *Upsert {
+	if !g.config.UseUpsert {
+		return nil
+	}
+
+	mappingMap := make(map[string]*ColumnMapping)
+	var pkColumns []*ColumnMapping
+	for _, m := range mappings {
+		mappingMap[m.ColumnName] = m
+		if m.IsPrimaryKey {
+			pkColumns = append(pkColumns, m)
+		}
+	}
+
+	upsert := &Upsert{ConflictName: "PRIMARY", ConflictColumns: pkColumns, Batch: g.config.UseBatchUpsert}
+	if g.keyGen != nil && g.keyGen.Strategy == config.KeyStrategySequence {
+		upsert.SequenceKey = g.keyGen
+	}
+	if indexName := g.config.UpsertConflictIndex; indexName != "" {
+		if columns, ok := g.uniqueIndexColumns(indexName, mappingMap); ok {
+			upsert.ConflictName = indexName
+			upsert.ConflictColumns = columns
+		} else {
+			log.Printf("[Generator] 唯一索引 %s 不存在或包含被忽略的列，upsert 改用主键判定冲突", indexName)
+		}
+	}
+	if len(upsert.ConflictColumns) == 0 {
+		log.Printf("[Generator] 表 %s 没有主键或唯一索引，跳过生成 upsert", g.config.TableName)
+		return nil
+	}
+
+	conflictSet := make(map[string]bool)
+	for _, col := range upsert.ConflictColumns {
+		conflictSet[col.ColumnName] = true
+	}
+
+	// 插入列需包含冲突列，否则无法命中冲突（如 IgnorePKOnInsert 且按主键判定时）
+	inserted := make(map[string]bool)
+	for _, col := range insertColumns {
+		inserted[col.ColumnName] = true
+	}
+	for _, col := range mappings {
+		if inserted[col.ColumnName] || conflictSet[col.ColumnName] {
+			upsert.InsertColumns = append(upsert.InsertColumns, col)
+			if !conflictSet[col.ColumnName] && !col.IsPrimaryKey {
+				upsert.UpdateColumns = append(upsert.UpdateColumns, col)
+			}
+		}
+	}
+
+	return upsert
+}
+
+// uniqueIndexColumns 查找唯一索引对应的列映射
+func (g *Generator) uniqueIndexColumns(indexName string, mappingMap map[string]*ColumnMapping) ([]*ColumnMapping, bool) {
+	for _, index := range g.indexes {
+		if !index.IsUnique || !strings.EqualFold(index.IndexName, indexName) {
+			continue
+		}
+		columns := make([]*ColumnMapping, 0, len(index.Columns))
+		for _, columnName := range index.Columns {
+			mapping, ok := mappingMap[columnName]
+			if !ok {
+				return nil, false
+			}
+			columns = append(columns, mapping)
+		}
+		return columns, true
+	}
+	return nil, false
+}
+
+// renderUpsertXML 按方言渲染 upsert / upsertSelective / upsertBatch 语句
+func renderUpsertXML(dialect Dialect, data *MapperXMLData) (string, error) {
+	tmpl := mysqlUpsertTemplate
+	switch {
+	case dialect.IsOracle():
+		tmpl = oracleUpsertTemplate
+	case dialect.IsPostgreSQL():
+		tmpl = postgresUpsertTemplate
+	}
+	return renderTemplate("upsert", tmpl, data)
+}
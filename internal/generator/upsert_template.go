@@ -0,0 +1,221 @@
+package generator
+
+// mysqlUpsertTemplate MySQL upsert 模板（INSERT ... ON DUPLICATE KEY UPDATE）
+// ON DUPLICATE KEY 无法指定冲突索引，主键或任一唯一索引冲突都会触发更新；
+// UpsertConflictIndex 只决定哪些列不参与更新
+// upsertSelective 先输出冲突列的无效赋值，可更新属性全部为 null 时仍保留 ON DUPLICATE KEY UPDATE
+const mysqlUpsertTemplate = `
+    <!-- 插入或更新（冲突判定: {{.Upsert.ConflictName}}{{if ne .Upsert.ConflictName "PRIMARY"}}，MySQL 任一唯一键冲突均会触发更新{{end}}） -->
+    <insert id="upsert" parameterType="{{.ModelType}}">
+        INSERT INTO {{.TableName}} (
+            {{range $index, $col := .Upsert.InsertColumns}}{{if $index}}, {{end}}{{$col.ColumnName}}{{end}}
+        )
+        VALUES (
+            {{range $index, $col := .Upsert.InsertColumns}}{{if $index}}, {{end}}#{{"{"}}{{$col.FieldName}},jdbcType={{$col.JdbcType}}{{"}"}}{{end}}
+        )
+        ON DUPLICATE KEY UPDATE
+{{- if .Upsert.UpdateColumns}}
+            {{range $index, $col := .Upsert.UpdateColumns}}{{if $index}},
+            {{end}}{{$col.ColumnName}} = VALUES({{$col.ColumnName}}){{end}}
+{{- else}}
+            {{$c := index .Upsert.ConflictColumns 0}}{{$c.ColumnName}} = {{$c.ColumnName}}
+{{- end}}
+    </insert>
+
+    <!-- 选择性插入或更新 -->
+    <insert id="upsertSelective" parameterType="{{.ModelType}}">
+        INSERT INTO {{.TableName}}
+        <trim prefix="(" suffix=")" suffixOverrides=",">
+{{range .Upsert.InsertColumns}}            <if test="{{.FieldName}} != null">
+                {{.ColumnName}},
+            </if>
+{{end}}        </trim>
+        <trim prefix="values (" suffix=")" suffixOverrides=",">
+{{range .Upsert.InsertColumns}}            <if test="{{.FieldName}} != null">
+                #{{"{"}}{{.FieldName}},jdbcType={{.JdbcType}}{{"}"}},
+            </if>
+{{end}}        </trim>
+        <trim prefix="ON DUPLICATE KEY UPDATE" suffixOverrides=",">
+            {{$c := index .Upsert.ConflictColumns 0}}{{$c.ColumnName}} = {{$c.ColumnName}},
+{{range .Upsert.UpdateColumns}}            <if test="{{.FieldName}} != null">
+                {{.ColumnName}} = VALUES({{.ColumnName}}),
+            </if>
+{{end}}        </trim>
+    </insert>
+{{if .Upsert.Batch}}
+    <!-- 批量插入或更新 -->
+    <insert id="upsertBatch" parameterType="java.util.List">
+        INSERT INTO {{.TableName}} (
+            {{range $index, $col := .Upsert.InsertColumns}}{{if $index}}, {{end}}{{$col.ColumnName}}{{end}}
+        )
+        VALUES
+        <foreach collection="list" item="item" separator=",">
+            ({{range $index, $col := .Upsert.InsertColumns}}{{if $index}}, {{end}}#{{"{"}}item.{{$col.FieldName}},jdbcType={{$col.JdbcType}}{{"}"}}{{end}})
+        </foreach>
+        ON DUPLICATE KEY UPDATE
+{{- if .Upsert.UpdateColumns}}
+            {{range $index, $col := .Upsert.UpdateColumns}}{{if $index}},
+            {{end}}{{$col.ColumnName}} = VALUES({{$col.ColumnName}}){{end}}
+{{- else}}
+            {{$c := index .Upsert.ConflictColumns 0}}{{$c.ColumnName}} = {{$c.ColumnName}}
+{{- end}}
+    </insert>
+{{end}}`
+
+// postgresUpsertTemplate PostgreSQL upsert 模板（INSERT ... ON CONFLICT ... DO UPDATE）
+// upsertSelective 的可更新属性全部为 null 时 DO UPDATE 没有赋值，改为 DO NOTHING；
+// 序列主键未提供时取序列下一个值
+const postgresUpsertTemplate = `
+    <!-- 插入或更新（冲突判定: {{.Upsert.ConflictName}}） -->
+    <insert id="upsert" parameterType="{{.ModelType}}">
+        INSERT INTO {{.TableName}} (
+            {{range $index, $col := .Upsert.InsertColumns}}{{if $index}}, {{end}}{{$col.ColumnName}}{{end}}
+        )
+        VALUES (
+            {{range $index, $col := .Upsert.InsertColumns}}{{if $index}}, {{end}}{{if $.Upsert.IsSequenceKey $col}}COALESCE(#{{"{"}}{{$col.FieldName}},jdbcType={{$col.JdbcType}}{{"}"}}, {{$.Upsert.SequenceKey.NextValExpr}}){{else}}#{{"{"}}{{$col.FieldName}},jdbcType={{$col.JdbcType}}{{"}"}}{{end}}{{end}}
+        )
+        ON CONFLICT ({{range $index, $col := .Upsert.ConflictColumns}}{{if $index}}, {{end}}{{$col.ColumnName}}{{end}})
+{{- if .Upsert.UpdateColumns}} DO UPDATE SET
+            {{range $index, $col := .Upsert.UpdateColumns}}{{if $index}},
+            {{end}}{{$col.ColumnName}} = EXCLUDED.{{$col.ColumnName}}{{end}}
+{{- else}} DO NOTHING
+{{- end}}
+    </insert>
+
+    <!-- 选择性插入或更新 -->
+    <insert id="upsertSelective" parameterType="{{.ModelType}}">
+        INSERT INTO {{.TableName}}
+        <trim prefix="(" suffix=")" suffixOverrides=",">
+{{range .Upsert.InsertColumns}}{{if $.Upsert.IsSequenceKey .}}            {{.ColumnName}},
+{{else}}            <if test="{{.FieldName}} != null">
+                {{.ColumnName}},
+            </if>
+{{end}}{{end}}        </trim>
+        <trim prefix="values (" suffix=")" suffixOverrides=",">
+{{range .Upsert.InsertColumns}}{{if $.Upsert.IsSequenceKey .}}            COALESCE(#{{"{"}}{{.FieldName}},jdbcType={{.JdbcType}}{{"}"}}, {{$.Upsert.SequenceKey.NextValExpr}}),
+{{else}}            <if test="{{.FieldName}} != null">
+                #{{"{"}}{{.FieldName}},jdbcType={{.JdbcType}}{{"}"}},
+            </if>
+{{end}}{{end}}        </trim>
+        ON CONFLICT ({{range $index, $col := .Upsert.ConflictColumns}}{{if $index}}, {{end}}{{$col.ColumnName}}{{end}})
+{{- if .Upsert.UpdateColumns}}
+        <choose>
+            <when test="{{range $index, $col := .Upsert.UpdateColumns}}{{if $index}} or {{end}}{{$col.FieldName}} != null{{end}}">
+                DO UPDATE
+                <set>
+{{range .Upsert.UpdateColumns}}                    <if test="{{.FieldName}} != null">
+                        {{.ColumnName}} = EXCLUDED.{{.ColumnName}},
+                    </if>
+{{end}}                </set>
+            </when>
+            <otherwise>
+                DO NOTHING
+            </otherwise>
+        </choose>
+{{- else}} DO NOTHING
+{{- end}}
+    </insert>
+{{if .Upsert.Batch}}
+    <!-- 批量插入或更新 -->
+    <insert id="upsertBatch" parameterType="java.util.List">
+        INSERT INTO {{.TableName}} (
+            {{range $index, $col := .Upsert.InsertColumns}}{{if $index}}, {{end}}{{$col.ColumnName}}{{end}}
+        )
+        VALUES
+        <foreach collection="list" item="item" separator=",">
+            ({{range $index, $col := .Upsert.InsertColumns}}{{if $index}}, {{end}}{{if $.Upsert.IsSequenceKey $col}}COALESCE(#{{"{"}}item.{{$col.FieldName}},jdbcType={{$col.JdbcType}}{{"}"}}, {{$.Upsert.SequenceKey.NextValExpr}}){{else}}#{{"{"}}item.{{$col.FieldName}},jdbcType={{$col.JdbcType}}{{"}"}}{{end}}{{end}})
+        </foreach>
+        ON CONFLICT ({{range $index, $col := .Upsert.ConflictColumns}}{{if $index}}, {{end}}{{$col.ColumnName}}{{end}})
+{{- if .Upsert.UpdateColumns}} DO UPDATE SET
+            {{range $index, $col := .Upsert.UpdateColumns}}{{if $index}},
+            {{end}}{{$col.ColumnName}} = EXCLUDED.{{$col.ColumnName}}{{end}}
+{{- else}} DO NOTHING
+{{- end}}
+    </insert>
+{{end}}`
+
+// oracleUpsertTemplate Oracle upsert 模板（MERGE INTO ... USING DUAL）
+// upsertSelective 的第一个更新列以 NVL 无条件赋值，保证可更新属性全部为 null 时 UPDATE SET 仍然合法；
+// 序列在 USING 子查询中不可用，序列主键在 INSERT 子句中补全
+const oracleUpsertTemplate = `
+    <!-- 插入或更新（冲突判定: {{.Upsert.ConflictName}}） -->
+    <update id="upsert" parameterType="{{.ModelType}}">
+        MERGE INTO {{.TableName}} t
+        USING (
+            SELECT {{range $index, $col := .Upsert.InsertColumns}}{{if $index}},
+                   {{end}}#{{"{"}}{{$col.FieldName}},jdbcType={{$col.JdbcType}}{{"}"}} AS {{$col.ColumnName}}{{end}}
+            FROM DUAL
+        ) s
+        ON ({{range $index, $col := .Upsert.ConflictColumns}}{{if $index}} AND {{end}}t.{{$col.ColumnName}} = s.{{$col.ColumnName}}{{end}})
+{{- if .Upsert.UpdateColumns}}
+        WHEN MATCHED THEN UPDATE SET
+            {{range $index, $col := .Upsert.UpdateColumns}}{{if $index}},
+            {{end}}t.{{$col.ColumnName}} = s.{{$col.ColumnName}}{{end}}
+{{- end}}
+        WHEN NOT MATCHED THEN INSERT (
+            {{range $index, $col := .Upsert.InsertColumns}}{{if $index}}, {{end}}{{$col.ColumnName}}{{end}}
+        )
+        VALUES (
+            {{range $index, $col := .Upsert.InsertColumns}}{{if $index}}, {{end}}{{if $.Upsert.IsSequenceKey $col}}COALESCE(s.{{$col.ColumnName}}, {{$.Upsert.SequenceKey.NextValExpr}}){{else}}s.{{$col.ColumnName}}{{end}}{{end}}
+        )
+    </update>
+
+    <!-- 选择性插入或更新 -->
+    <update id="upsertSelective" parameterType="{{.ModelType}}">
+        MERGE INTO {{.TableName}} t
+        USING (
+            SELECT {{range $index, $col := .Upsert.InsertColumns}}{{if $index}},
+                   {{end}}#{{"{"}}{{$col.FieldName}},jdbcType={{$col.JdbcType}}{{"}"}} AS {{$col.ColumnName}}{{end}}
+            FROM DUAL
+        ) s
+        ON ({{range $index, $col := .Upsert.ConflictColumns}}{{if $index}} AND {{end}}t.{{$col.ColumnName}} = s.{{$col.ColumnName}}{{end}})
+{{- if .Upsert.UpdateColumns}}
+        WHEN MATCHED THEN UPDATE
+        <set>
+{{- with index .Upsert.UpdateColumns 0}}
+            t.{{.ColumnName}} = NVL(s.{{.ColumnName}}, t.{{.ColumnName}}),
+{{- end}}
+{{range slice .Upsert.UpdateColumns 1}}            <if test="{{.FieldName}} != null">
+                t.{{.ColumnName}} = s.{{.ColumnName}},
+            </if>
+{{end}}        </set>
+{{- end}}
+        WHEN NOT MATCHED THEN INSERT
+        <trim prefix="(" suffix=")" suffixOverrides=",">
+{{range .Upsert.InsertColumns}}{{if $.Upsert.IsSequenceKey .}}            {{.ColumnName}},
+{{else}}            <if test="{{.FieldName}} != null">
+                {{.ColumnName}},
+            </if>
+{{end}}{{end}}        </trim>
+        <trim prefix="VALUES (" suffix=")" suffixOverrides=",">
+{{range .Upsert.InsertColumns}}{{if $.Upsert.IsSequenceKey .}}            COALESCE(s.{{.ColumnName}}, {{$.Upsert.SequenceKey.NextValExpr}}),
+{{else}}            <if test="{{.FieldName}} != null">
+                s.{{.ColumnName}},
+            </if>
+{{end}}{{end}}        </trim>
+    </update>
+{{if .Upsert.Batch}}
+    <!-- 批量插入或更新 -->
+    <update id="upsertBatch" parameterType="java.util.List">
+        MERGE INTO {{.TableName}} t
+        USING (
+            <foreach collection="list" item="item" separator=" UNION ALL ">
+            SELECT {{range $index, $col := .Upsert.InsertColumns}}{{if $index}},
+                   {{end}}#{{"{"}}item.{{$col.FieldName}},jdbcType={{$col.JdbcType}}{{"}"}} AS {{$col.ColumnName}}{{end}}
+            FROM DUAL
+            </foreach>
+        ) s
+        ON ({{range $index, $col := .Upsert.ConflictColumns}}{{if $index}} AND {{end}}t.{{$col.ColumnName}} = s.{{$col.ColumnName}}{{end}})
+{{- if .Upsert.UpdateColumns}}
+        WHEN MATCHED THEN UPDATE SET
+            {{range $index, $col := .Upsert.UpdateColumns}}{{if $index}},
+            {{end}}t.{{$col.ColumnName}} = s.{{$col.ColumnName}}{{end}}
+{{- end}}
+        WHEN NOT MATCHED THEN INSERT (
+            {{range $index, $col := .Upsert.InsertColumns}}{{if $index}}, {{end}}{{$col.ColumnName}}{{end}}
+        )
+        VALUES (
+            {{range $index, $col := .Upsert.InsertColumns}}{{if $index}}, {{end}}{{if $.Upsert.IsSequenceKey $col}}COALESCE(s.{{$col.ColumnName}}, {{$.Upsert.SequenceKey.NextValExpr}}){{else}}s.{{$col.ColumnName}}{{end}}{{end}}
+        )
+    </update>
+{{end}}`
@@ -0,0 +1,131 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yourusername/mybatis-generator-gui-go/internal/config"
+	"github.com/yourusername/mybatis-generator-gui-go/internal/database"
+)
+
+func upsertTestMappings() []*ColumnMapping {
+	return []*ColumnMapping{
+		{ColumnName: "id", FieldName: "id", JavaType: "Long", JdbcType: "BIGINT", IsPrimaryKey: true},
+		{ColumnName: "email", FieldName: "email", JavaType: "String", JdbcType: "VARCHAR"},
+		{ColumnName: "name", FieldName: "name", JavaType: "String", JdbcType: "VARCHAR"},
+	}
+}
+
+func TestBuildUpsert(t *testing.T) {
+	mappings := upsertTestMappings()
+	g := &Generator{
+		config:  &config.GeneratorConfig{UseUpsert: true},
+		indexes: []*database.TableIndex{{IndexName: "uk_email", IsUnique: true, Columns: []string{"email"}}},
+	}
+
+	// 默认按主键判定冲突，即使插入时忽略主键也要包含主键列
+	upsert := g.buildUpsert(mappings, mappings[1:])
+	assert.Equal(t, "PRIMARY", upsert.ConflictName)
+	assert.Len(t, upsert.InsertColumns, 3)
+	assert.Len(t, upsert.UpdateColumns, 2)
+
+	// 按唯一索引判定冲突时不更新索引列与主键
+	g.config.UpsertConflictIndex = "uk_email"
+	upsert = g.buildUpsert(mappings, mappings[1:])
+	assert.Equal(t, "uk_email", upsert.ConflictName)
+	assert.Len(t, upsert.InsertColumns, 2)
+	assert.Len(t, upsert.UpdateColumns, 1)
+	assert.Equal(t, "name", upsert.UpdateColumns[0].ColumnName)
+
+	// 不存在的索引回退到主键
+	g.config.UpsertConflictIndex = "uk_missing"
+	assert.Equal(t, "PRIMARY", g.buildUpsert(mappings, mappings).ConflictName)
+
+	g.config.UseUpsert = false
+	assert.Nil(t, g.buildUpsert(mappings, mappings))
+}
+
+func TestRenderUpsertXML(t *testing.T) {
+	mappings := upsertTestMappings()
+	g := &Generator{config: &config.GeneratorConfig{UseUpsert: true, UseBatchUpsert: true}}
+	data := &MapperXMLData{
+		ModelType: "com.example.model.User",
+		TableName: "user",
+		Upsert:    g.buildUpsert(mappings, mappings),
+	}
+
+	mysql, err := renderUpsertXML(NewDialect(nil), data)
+	assert.NoError(t, err)
+	assert.Contains(t, mysql, "ON DUPLICATE KEY UPDATE")
+	assert.Contains(t, mysql, "email = VALUES(email)")
+	assert.Contains(t, mysql, `<insert id="upsertBatch" parameterType="java.util.List">`)
+
+	pg, err := renderUpsertXML(NewDialect(&config.DatabaseConfig{DbType: config.DbTypePostgreSQL}), data)
+	assert.NoError(t, err)
+	assert.Contains(t, pg, "ON CONFLICT (id) DO UPDATE SET")
+	assert.Contains(t, pg, "name = EXCLUDED.name")
+
+	oracle, err := renderUpsertXML(NewDialect(&config.DatabaseConfig{DbType: config.DbTypeOracle}), data)
+	assert.NoError(t, err)
+	assert.Contains(t, oracle, "MERGE INTO user t")
+	assert.Contains(t, oracle, "FROM DUAL")
+	assert.Contains(t, oracle, "ON (t.id = s.id)")
+	assert.Contains(t, oracle, `separator=" UNION ALL "`)
+
+	// upsertSelective 的可更新属性全部为 null 时仍需生成合法SQL
+	bound, err := EvaluateStatement(mysql, "upsertSelective", map[string]interface{}{"id": 1})
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO user ( id ) values ( ? ) ON DUPLICATE KEY UPDATE id = id", bound.SQL)
+	bound, err = EvaluateStatement(mysql, "upsertSelective", map[string]interface{}{"id": 1, "name": "tom"})
+	assert.NoError(t, err)
+	assert.Contains(t, bound.SQL, "ON DUPLICATE KEY UPDATE id = id, name = VALUES(name)")
+	bound, err = EvaluateStatement(pg, "upsertSelective", map[string]interface{}{"id": 1})
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO user ( id ) values ( ? ) ON CONFLICT (id) DO NOTHING", bound.SQL)
+	bound, err = EvaluateStatement(pg, "upsertSelective", map[string]interface{}{"id": 1, "name": "tom"})
+	assert.NoError(t, err)
+	assert.Contains(t, bound.SQL, "ON CONFLICT (id) DO UPDATE SET name = EXCLUDED.name")
+	bound, err = EvaluateStatement(oracle, "upsertSelective", map[string]interface{}{"id": 1})
+	assert.NoError(t, err)
+	assert.Contains(t, bound.SQL, "WHEN MATCHED THEN UPDATE SET t.email = NVL(s.email, t.email) WHEN NOT MATCHED")
+
+	// 没有可更新的列时 PostgreSQL 使用 DO NOTHING
+	data.Upsert.UpdateColumns = nil
+	pg, err = renderUpsertXML(NewDialect(&config.DatabaseConfig{DbType: config.DbTypePostgreSQL}), data)
+	assert.NoError(t, err)
+	assert.Contains(t, pg, "ON CONFLICT (id) DO NOTHING")
+}
+
+func TestRenderUpsertXML_SequenceKey(t *testing.T) {
+	mappings := upsertTestMappings()
+	for _, dbType := range []string{config.DbTypePostgreSQL, config.DbTypeOracle} {
+		dbCfg := &config.DatabaseConfig{DbType: dbType}
+		g := &Generator{
+			config:  &config.GeneratorConfig{UseUpsert: true, UseBatchUpsert: true, KeyStrategy: config.KeyStrategySequence},
+			dialect: NewDialect(dbCfg),
+		}
+		keyGen, err := g.newKeyGeneration(mappings[0], "user_seq")
+		assert.NoError(t, err)
+		g.keyGen = keyGen
+
+		output, err := renderUpsertXML(g.dialect, &MapperXMLData{
+			ModelType: "com.example.model.User",
+			TableName: "user",
+			Upsert:    g.buildUpsert(mappings, mappings),
+		})
+		assert.NoError(t, err)
+
+		// 未提供主键时插入序列值（含 upsertSelective，主键不受 null 判断影响）
+		if dbType == config.DbTypePostgreSQL {
+			assert.Contains(t, output, "COALESCE(#{id,jdbcType=BIGINT}, nextval('user_seq')), #{email")
+			assert.Contains(t, output, "COALESCE(#{item.id,jdbcType=BIGINT}, nextval('user_seq'))")
+			bound, err := EvaluateStatement(output, "upsertSelective", map[string]interface{}{"name": "tom"})
+			assert.NoError(t, err)
+			assert.Contains(t, bound.SQL, "INSERT INTO user ( id, name ) values ( COALESCE(?, nextval('user_seq')), ? )")
+		} else {
+			assert.Equal(t, 3, strings.Count(output, "COALESCE(s.id, user_seq.NEXTVAL)"))
+			assert.NotContains(t, output, "#{id,jdbcType=BIGINT}, user_seq")
+		}
+	}
+}
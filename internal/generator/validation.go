@@ -0,0 +1,87 @@
+package generator
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/yourusername/mybatis-generator-gui-go/internal/database"
+	"github.com/yourusername/mybatis-generator-gui-go/internal/utils"
+)
+
+// validationPackage 获取 Bean Validation 约束注解所在的包
+func validationPackage(jakarta bool) string {
+	if jakarta {
+		return "jakarta.validation.constraints"
+	}
+	return "javax.validation.constraints"
+}
+
+// buildValidationAnnotations 根据列元数据推导字段的 Bean Validation 注解，并登记所需 import
+func buildValidationAnnotations(col *database.TableColumn, javaType string, jakarta bool, imports map[string]bool) []string {
+	var annotations []string
+	pkg := validationPackage(jakarta)
+	add := func(name, annotation string) {
+		imports[pkg+"."+name] = true
+		annotations = append(annotations, annotation)
+	}
+
+	// 非空：主键通常由数据库或业务生成，不做非空校验
+	if isRequiredColumn(col) && col.ColumnKey != "PRI" {
+		add("NotNull", "@NotNull")
+	}
+
+	switch javaType {
+	case "String":
+		// Java 注解的 max 为 int，超出范围（如 longtext）时不生成
+		if col.CharMaxLength > 0 && col.CharMaxLength <= math.MaxInt32 {
+			add("Size", fmt.Sprintf("@Size(max = %d)", col.CharMaxLength))
+		}
+	case "BigDecimal":
+		if col.NumericPrecision > 0 {
+			integer := col.NumericPrecision - col.NumericScale
+			if integer < 0 {
+				integer = 0
+			}
+			add("Digits", fmt.Sprintf("@Digits(integer = %d, fraction = %d)", integer, col.NumericScale))
+		}
+	case "Date", "LocalDate", "LocalDateTime":
+		if name := pastAnnotation(col); name != "" {
+			add(name, "@"+name)
+		}
+	}
+
+	return annotations
+}
+
+// isRequiredColumn 判断列在插入时是否必须赋值（不可为空、无默认值且非自增）
+func isRequiredColumn(col *database.TableColumn) bool {
+	if col.IsNullable || col.HasDefault {
+		return false
+	}
+	return !strings.Contains(strings.ToLower(col.Extra), "auto_increment")
+}
+
+// pastAnnotation 根据列名与默认值推断时间字段应使用 @Past 还是 @PastOrPresent，无法推断时返回空
+func pastAnnotation(col *database.TableColumn) string {
+	name := col.ColumnName
+	if !strings.Contains(name, "_") && name != strings.ToUpper(name) {
+		name = utils.CamelCaseToDBString(name)
+	}
+	for _, token := range strings.Split(strings.ToLower(name), "_") {
+		switch token {
+		case "birth", "birthday", "birthdate":
+			return "Past"
+		case "create", "created", "register", "registered":
+			return "PastOrPresent"
+		}
+	}
+
+	def := strings.ToUpper(col.ColumnDefault)
+	for _, fn := range []string{"CURRENT_TIMESTAMP", "CURRENT_DATE", "NOW()", "SYSDATE", "SYSTIMESTAMP"} {
+		if strings.Contains(def, fn) {
+			return "PastOrPresent"
+		}
+	}
+	return ""
+}
@@ -0,0 +1,47 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yourusername/mybatis-generator-gui-go/internal/database"
+)
+
+func TestBuildValidationAnnotations(t *testing.T) {
+	tests := []struct {
+		name     string
+		col      *database.TableColumn
+		javaType string
+		expected []string
+	}{
+		{"必填字符串", &database.TableColumn{ColumnName: "user_name", CharMaxLength: 64}, "String", []string{"@NotNull", "@Size(max = 64)"}},
+		{"可空字符串", &database.TableColumn{ColumnName: "remark", IsNullable: true, CharMaxLength: 255}, "String", []string{"@Size(max = 255)"}},
+		{"有默认值", &database.TableColumn{ColumnName: "status", HasDefault: true, ColumnDefault: "0"}, "Integer", nil},
+		{"自增主键", &database.TableColumn{ColumnName: "id", ColumnKey: "PRI", Extra: "auto_increment"}, "Long", nil},
+		{"超长文本", &database.TableColumn{ColumnName: "content", IsNullable: true, CharMaxLength: 4294967295}, "String", nil},
+		{"金额", &database.TableColumn{ColumnName: "amount", IsNullable: true, NumericPrecision: 10, NumericScale: 2}, "BigDecimal", []string{"@Digits(integer = 8, fraction = 2)"}},
+		{"生日", &database.TableColumn{ColumnName: "birthday", IsNullable: true}, "LocalDate", []string{"@Past"}},
+		{"创建时间", &database.TableColumn{ColumnName: "CREATE_TIME", HasDefault: true, ColumnDefault: "CURRENT_TIMESTAMP"}, "Date", []string{"@PastOrPresent"}},
+		{"驼峰列名", &database.TableColumn{ColumnName: "createdAt", IsNullable: true}, "LocalDateTime", []string{"@PastOrPresent"}},
+		{"过期时间", &database.TableColumn{ColumnName: "expire_time", IsNullable: true}, "Date", nil},
+	}
+
+	for _, tt := range tests {
+		imports := make(map[string]bool)
+		result := buildValidationAnnotations(tt.col, tt.javaType, false, imports)
+		assert.Equal(t, tt.expected, result, tt.name)
+	}
+}
+
+func TestBuildValidationAnnotations_Imports(t *testing.T) {
+	col := &database.TableColumn{ColumnName: "name", CharMaxLength: 32}
+
+	imports := make(map[string]bool)
+	buildValidationAnnotations(col, "String", true, imports)
+	assert.True(t, imports["jakarta.validation.constraints.NotNull"])
+	assert.True(t, imports["jakarta.validation.constraints.Size"])
+
+	imports = make(map[string]bool)
+	buildValidationAnnotations(col, "String", false, imports)
+	assert.True(t, imports["javax.validation.constraints.NotNull"])
+}
@@ -6,10 +6,19 @@ let selectedTables = [];
 let allTables = [];
 let ignoredColumns = [];
 let columnOverrides = [];
+let tableOverrides = {};      // 单表覆盖配置：tableName -> { domainObjectName, mapperName, generateKeys, keyStrategy, sequenceName, ignoredColumns, columnOverrides }
+let columnModalTable = null;  // 列定制弹窗当前显示的表
 
 // Tab2 自定义片段相关
-let snippetTableColumns = [];   // 当前表的所有列信息
-let snippetList = [];           // 已添加的片段列表
+let snippetTableColumns = [];   // 可选列（主表列 + 关联表列）
+let snippetMainColumns = [];    // 当前表的所有列信息
+let snippetJoins = [];          // 关联表：[{joinType, tableName, alias, on: [{leftColumn, rightColumn}], columns}]
+let snippetResultType = '';     // 关联查询结果DTO类名（空则按方法名生成）
+const SNIPPET_MAIN_ALIAS = 't';  // 关联查询时主表的别名
+let snippetList = [];           // 当前表已添加的片段列表
+let savedSnippets = [];         // 当前表在片段库中保存的片段
+let snippetLists = {};          // 各表的片段列表：tableName -> 片段数组
+let snippetTable = null;        // 片段面板当前显示的表
 let snippetMergeEnabled = false; // 是否启用"并入生成"
 let editingSnippetIndex = null;  // 当前正在编辑的片段索引
 
@@ -19,11 +28,8 @@ let whereLogic = 'AND';         // AND | OR
 let whereRuleCounter = 0;
 
 // Chip / TomSelect 选择状态
-let selectedChips = {
-    selectFields: new Set(),
-    insertFields: new Set(),
-    setFields: new Set(),
-};
+let selectedChips = emptyChipSelections();
+const CHIP_PANELS = ['selectFields', 'insertFields', 'setFields', 'groupByFields'];
 let orderBySelections = new Map(); // colIdx -> direction
 
 // SELECT 字段聚合/别名配置
@@ -31,6 +37,14 @@ let selectFieldConfigs = {};    // colIdx -> { aggregate, alias }
 
 // LIMIT 配置
 let limitConfig = { hasLimit: false, isLimitFixed: false, limitValue: 'limit' };
+// 返回形式配置（mapKeyIdx 为 @MapKey 键列在 snippetTableColumns 中的下标）
+let returnShapeConfig = { shape: 'list', mapKeyIdx: -1 };
+const RETURN_SHAPES = [
+    ['list', 'List<实体>'], ['one', '单个实体'], ['optional', 'Optional<实体>'],
+    ['count', 'long 计数'], ['exists', 'boolean 是否存在'], ['map', 'Map<String, Object>'], ['mapKey', '@MapKey Map<键, 实体>']
+];
+let havingRules = [];           // [{id, aggregate, fieldIdx(-1 表示 *), operator, isFixed, fixedValue, paramName}]
+let havingRuleCounter = 0;
 
 // ============================================================
 // 工具函数
@@ -110,6 +124,9 @@ function computeMethodName(cfg) {
             if (!wherePart) name += (name === 'select' ? 'All' : '');
             else name += wherePart;
             
+            if (cfg.groupByFields && cfg.groupByFields.length > 0) {
+                name += 'GroupBy' + cfg.groupByFields.map(f => capitalize(f.fieldName)).join('And');
+            }
             if (cfg.isBatch) name += 'Batch';
             return name;
         }
@@ -199,6 +216,7 @@ async function selectConnection(id) {
     allTables = [];
     ignoredColumns = [];
     columnOverrides = [];
+    tableOverrides = {};
     document.querySelectorAll('.connection-item').forEach(item => item.classList.remove('active'));
     event.currentTarget.closest('.connection-item').classList.add('active');
     await loadTables();
@@ -278,8 +296,11 @@ function updateDefaultEntityFields() {
     if (selectedTables.length === 1) {
         const t = selectedTables[0];
         tableNameEl.value = t;
-        if (!domainNameEl.dataset.userEdited) domainNameEl.value = toPascalCase(t);
-        if (!mapperNameEl.dataset.userEdited) mapperNameEl.value = toPascalCase(t) + 'Mapper';
+        // 名称尚未按命名规则推导时先请求后端，完成后再刷新
+        if (!namingCache[t]) refreshNaming([t]).then(updateDefaultEntityFields).catch(() => {});
+        const names = namesFor(t);
+        if (!domainNameEl.dataset.userEdited) domainNameEl.value = names.domainObjectName;
+        if (!mapperNameEl.dataset.userEdited) mapperNameEl.value = names.mapperName;
     } else if (selectedTables.length > 1) {
         tableNameEl.value = `(已选 ${selectedTables.length} 张表)`;
         if (!domainNameEl.dataset.userEdited) domainNameEl.value = '';
@@ -291,6 +312,92 @@ function updateDefaultEntityFields() {
     }
 }
 
+// ============================================================
+// 命名规则
+// ============================================================
+let namingCache = {}; // tableName -> { domainObjectName, mapperName, fields }
+
+function splitList(value) {
+    return value.split(',').map(s => s.trim()).filter(Boolean);
+}
+
+// 解析重命名规则：每行 "正则 => 替换"
+function parseRenameRules(text) {
+    return text.split('\n').map(l => l.trim()).filter(Boolean).map(line => {
+        const idx = line.indexOf('=>');
+        if (idx < 0) return { pattern: line, replacement: '' };
+        return { pattern: line.slice(0, idx).trim(), replacement: line.slice(idx + 2).trim() };
+    });
+}
+
+function buildNamingRules() {
+    return {
+        tablePrefixes: splitList(document.getElementById('namingTablePrefixes').value),
+        columnPrefixes: splitList(document.getElementById('namingColumnPrefixes').value),
+        tableRenames: parseRenameRules(document.getElementById('namingTableRenames').value),
+        columnRenames: parseRenameRules(document.getElementById('namingColumnRenames').value),
+        modelSuffix: document.getElementById('namingModelSuffix').value.trim(),
+        mapperSuffix: document.getElementById('namingMapperSuffix').value
+    };
+}
+
+// 获取表对应的类名/Mapper名（未预览时按默认规则推导）
+function namesFor(tableName) {
+    return namingCache[tableName] || {
+        domainObjectName: toPascalCase(tableName),
+        mapperName: toPascalCase(tableName) + 'Mapper'
+    };
+}
+
+// 请求后端按命名规则推导名称；withFields 为 true 时同时获取列属性名（需要连接数据库）
+async function refreshNaming(tableNames, withFields = false) {
+    if (tableNames.length === 0) return [];
+    const body = {
+        databaseId: withFields ? currentDatabaseId : 0,
+        tableNames,
+        config: {
+            naming: buildNamingRules(),
+            useActualColumnNames: document.getElementById('useActualColumnNames').checked
+        }
+    };
+    const response = await fetch('/api/naming/preview', {
+        method: 'POST', headers: { 'Content-Type': 'application/json' }, body: JSON.stringify(body)
+    });
+    const result = await response.json();
+    if (!response.ok) throw new Error(result.error || '命名预览失败');
+    result.results.forEach(r => { namingCache[r.tableName] = r; });
+    return result.results;
+}
+
+// 命名规则变化后重新推导已选表的名称
+async function onNamingRulesChanged() {
+    namingCache = {};
+    const panel = document.getElementById('namingPreview');
+    try {
+        await refreshNaming(selectedTables);
+        panel.innerHTML = '';
+    } catch (error) {
+        panel.textContent = error.message;
+    }
+    updateDefaultEntityFields();
+}
+
+async function previewNaming() {
+    if (selectedTables.length === 0) { showMessage('请先选择表', 'error'); return; }
+    const panel = document.getElementById('namingPreview');
+    try {
+        const results = await refreshNaming(selectedTables, !!currentDatabaseId);
+        panel.innerHTML = '<table>' + results.map(r => {
+            const fields = Object.entries(r.fields || {})
+                .map(([col, field]) => `${escapeHtml(col)} → ${escapeHtml(field)}`).join('<br>');
+            return `<tr><td>${escapeHtml(r.tableName)}</td><td>${escapeHtml(r.domainObjectName)}<br>${escapeHtml(r.mapperName)}</td><td>${fields}</td></tr>`;
+        }).join('') + '</table>';
+        updateDefaultEntityFields();
+    } catch (error) {
+        panel.textContent = error.message;
+    }
+}
+
 // ============================================================
 // 连接管理弹窗
 // ============================================================
@@ -307,6 +414,8 @@ function showConnectionModal(connection = null) {
         document.getElementById('schema').value = connection.schema;
         document.getElementById('username').value = connection.username;
         document.getElementById('password').value = connection.password;
+        document.getElementById('oracleLegacyPaging').checked = !!connection.oracleLegacyPaging;
+        document.getElementById('allowExecution').checked = !!connection.allowExecution;
     } else {
         title.textContent = '新建数据库连接';
         document.getElementById('connectionForm').reset();
@@ -314,9 +423,16 @@ function showConnectionModal(connection = null) {
         document.getElementById('port').value = '3306';
         document.getElementById('host').value = 'localhost';
     }
+    toggleOracleOptions();
     modal.style.display = 'block';
 }
 
+// Oracle 专属选项仅在选择 Oracle 时显示
+function toggleOracleOptions() {
+    const isOracle = document.getElementById('dbType').value === 'Oracle';
+    document.getElementById('oracleLegacyPagingGroup').style.display = isOracle ? 'block' : 'none';
+}
+
 function hideConnectionModal() {
     document.getElementById('connectionModal').style.display = 'none';
 }
@@ -352,6 +468,8 @@ async function saveConnection() {
         schema: document.getElementById('schema').value,
         username: document.getElementById('username').value,
         password: document.getElementById('password').value,
+        oracleLegacyPaging: document.getElementById('oracleLegacyPaging').checked,
+        allowExecution: document.getElementById('allowExecution').checked,
         encoding: 'utf8mb4'
     };
     if (!config.name || !config.host || !config.port || !config.schema || !config.username) {
@@ -413,9 +531,6 @@ async function deleteConnection(id) {
 async function generateCode() {
     if (!currentDatabaseId) { showMessage('请先选择数据库连接', 'error'); return; }
     if (selectedTables.length === 0) { showMessage('请先选择表', 'error'); return; }
-    if (snippetMergeEnabled && selectedTables.length > 1) {
-        showMessage('使用自定义片段时仅支持单张表，请取消多余的表勾选', 'error'); return;
-    }
     const config = {
         modelPackage: document.getElementById('modelPackage').value,
         modelPackageTargetFolder: document.getElementById('modelTargetFolder').value,
@@ -433,21 +548,34 @@ async function generateCode() {
         needConstructors: document.getElementById('needConstructors').checked,
         useJsonProperty: document.getElementById('useJsonProperty').checked,
         jsonPropertyUpperCase: document.getElementById('jsonPropertyUpperCase').checked,
+        useBeanValidation: document.getElementById('useBeanValidation').checked,
+        useJakartaValidation: document.getElementById('useJakartaValidation').checked,
+        apiDocStyle: document.getElementById('apiDocStyle').value,
+        keyStrategy: document.getElementById('keyStrategy').value,
+        sequenceName: document.getElementById('sequenceName').value.trim(),
         useBatchInsert: document.getElementById('useBatchInsert').checked,
         useBatchUpdate: document.getElementById('useBatchUpdate').checked,
         ignorePKOnInsert: document.getElementById('ignorePKOnInsert').checked,
+        useAssociations: document.getElementById('useAssociations').checked,
+        useIndexFinders: document.getElementById('useIndexFinders').checked,
+        useUpsert: document.getElementById('useUpsert').checked,
+        useBatchUpsert: document.getElementById('useBatchUpsert').checked,
+        upsertConflictIndex: document.getElementById('upsertConflictIndex').value.trim(),
         needForUpdate: document.getElementById('needForUpdate').checked,
         useTableNameAlias: document.getElementById('useTableNameAlias').checked,
         useActualColumnNames: document.getElementById('useActualColumnNames').checked,
+        naming: buildNamingRules(),
         ignoredColumns, columnOverrides
     };
-    const requestBody = { databaseId: currentDatabaseId, tableNames: selectedTables, config };
-    if (snippetMergeEnabled && snippetList.length > 0) {
-        requestBody.snippetConfigs = snippetList;
+    const requestBody = { databaseId: currentDatabaseId, tableNames: selectedTables, config, tableOverrides: buildTableOverrides() };
+    const snippetCount = snippetMergeEnabled ? totalSnippetCount() : 0;
+    if (snippetCount > 0) {
+        requestBody.tableSnippetConfigs = buildTableSnippetConfigs();
+        requestBody.conflictStrategy = document.getElementById('snippetConflictStrategy').value;
     }
     try {
-        const hint = snippetMergeEnabled && snippetList.length > 0
-            ? `正在生成代码并追加 ${snippetList.length} 个自定义片段...`
+        const hint = snippetCount > 0
+            ? `正在生成代码并追加 ${snippetCount} 个自定义片段...`
             : `正在生成 ${selectedTables.length} 张表的代码...`;
         showMessage(hint, 'info');
         const response = await fetch('/api/generate', {
@@ -462,7 +590,21 @@ async function generateCode() {
             document.body.appendChild(a);
             a.click();
             document.body.removeChild(a);
-            setTimeout(() => showMessage(`已生成 ${result.tableCount} 张表, 共 ${result.files.length} 个文件`, 'info'), 1000);
+            let summary = `已生成 ${result.tableCount} 张表, 共 ${result.files.length} 个文件`;
+            if (result.snippets && result.snippets.length > 0) {
+                summary += '；片段：' + result.snippets.map(p => p.originalName
+                    ? `${p.mapperName}.${p.methodName}（由 ${p.originalName} 重命名）`
+                    : `${p.mapperName}.${p.methodName}`).join('，');
+            }
+            if (result.skipped && result.skipped.length > 0) {
+                summary += '；' + result.skipped.join('；');
+            }
+            setTimeout(() => showMessage(summary, 'info'), 1000);
+        } else if (result.snippets) {
+            // 片段重名：列出各片段的冲突
+            const conflicts = result.snippets.filter(p => p.conflicts && p.conflicts.length > 0)
+                .map(p => `${p.methodName}：${p.conflicts.join('，')}`);
+            showMessage('代码生成失败: ' + result.error + '；' + conflicts.join('；') + '（可在 Tab2 选择"重名时自动重命名"）', 'error');
         } else {
             showMessage('代码生成失败: ' + result.error, 'error');
         }
@@ -493,12 +635,24 @@ async function saveConfig() {
         needConstructors: document.getElementById('needConstructors').checked,
         useJsonProperty: document.getElementById('useJsonProperty').checked,
         jsonPropertyUpperCase: document.getElementById('jsonPropertyUpperCase').checked,
+        useBeanValidation: document.getElementById('useBeanValidation').checked,
+        useJakartaValidation: document.getElementById('useJakartaValidation').checked,
+        apiDocStyle: document.getElementById('apiDocStyle').value,
+        keyStrategy: document.getElementById('keyStrategy').value,
+        sequenceName: document.getElementById('sequenceName').value.trim(),
         useBatchInsert: document.getElementById('useBatchInsert').checked,
         useBatchUpdate: document.getElementById('useBatchUpdate').checked,
         ignorePKOnInsert: document.getElementById('ignorePKOnInsert').checked,
+        useAssociations: document.getElementById('useAssociations').checked,
+        useIndexFinders: document.getElementById('useIndexFinders').checked,
+        useUpsert: document.getElementById('useUpsert').checked,
+        useBatchUpsert: document.getElementById('useBatchUpsert').checked,
+        upsertConflictIndex: document.getElementById('upsertConflictIndex').value.trim(),
         needForUpdate: document.getElementById('needForUpdate').checked,
         useTableNameAlias: document.getElementById('useTableNameAlias').checked,
-        useActualColumnNames: document.getElementById('useActualColumnNames').checked
+        useActualColumnNames: document.getElementById('useActualColumnNames').checked,
+        naming: buildNamingRules(),
+        tableOverrides: buildTableOverrides()
     };
     try {
         const response = await fetch('/api/generator-configs', {
@@ -529,8 +683,36 @@ async function showColumnModal() {
     document.getElementById('columnModal').style.display = 'block';
 }
 
+// 获取表生效的列覆盖配置（单表配置优先）
+function columnOverridesFor(tableName) {
+    const scoped = tableOverrides[tableName];
+    return scoped && scoped.columnOverrides ? scoped.columnOverrides : columnOverrides;
+}
+
+function toggleTableOverrideFields() {
+    const scoped = document.getElementById('columnScopeTable').checked;
+    document.getElementById('tableOverrideFields').style.display = scoped ? 'flex' : 'none';
+}
+
+// 切换表前保存当前表的设置
+async function switchColumnTable(tableName) {
+    if (columnModalTable) saveColumnSettings(columnModalTable);
+    await loadColumnsForTable(tableName);
+}
+
 async function loadColumnsForTable(tableName) {
     if (!tableName) return;
+    columnModalTable = tableName;
+    const scoped = tableOverrides[tableName];
+    const tableIgnored = scoped ? scoped.ignoredColumns : ignoredColumns;
+    const tableColumnOverrides = scoped ? scoped.columnOverrides : columnOverrides;
+    document.getElementById('columnScopeTable').checked = !!scoped;
+    document.getElementById('overrideDomainObjectName').value = scoped ? scoped.domainObjectName || '' : '';
+    document.getElementById('overrideMapperName').value = scoped ? scoped.mapperName || '' : '';
+    document.getElementById('overrideGenerateKeys').value = scoped ? scoped.generateKeys || '' : '';
+    document.getElementById('overrideKeyStrategy').value = scoped ? scoped.keyStrategy || '' : '';
+    document.getElementById('overrideSequenceName').value = scoped ? scoped.sequenceName || '' : '';
+    toggleTableOverrideFields();
     try {
         const response = await fetch('/api/columns', {
             method: 'POST', headers: { 'Content-Type': 'application/json' },
@@ -540,8 +722,8 @@ async function loadColumnsForTable(tableName) {
         const tbody = document.getElementById('columnTableBody');
         tbody.innerHTML = '';
         columns.forEach(col => {
-            const isIgnored = ignoredColumns.includes(col.columnName);
-            const override = columnOverrides.find(o => o.columnName === col.columnName) || {};
+            const isIgnored = tableIgnored.includes(col.columnName);
+            const override = tableColumnOverrides.find(o => o.columnName === col.columnName) || {};
             const row = document.createElement('tr');
             row.innerHTML = `
                 <td class="text-center"><input type="checkbox" class="col-ignore" data-column="${col.columnName}" ${isIgnored ? 'checked' : ''}></td>
@@ -571,20 +753,59 @@ function hideColumnModal() {
     document.getElementById('columnModal').style.display = 'none';
 }
 
-function applyColumnSettings() {
-    ignoredColumns = [];
-    columnOverrides = [];
-    document.querySelectorAll('.col-ignore:checked').forEach(cb => ignoredColumns.push(cb.dataset.column));
+// 保存弹窗中的列设置：勾选"仅应用于当前表"时写入单表配置，否则写入全局配置
+function saveColumnSettings(tableName) {
+    const ignored = [];
+    const overrides = [];
+    document.querySelectorAll('.col-ignore:checked').forEach(cb => ignored.push(cb.dataset.column));
     document.querySelectorAll('.col-property').forEach(input => {
         const propertyName = input.value.trim();
         const javaTypeInput = document.querySelector(`.col-javatype[data-column="${input.dataset.column}"]`);
         const javaType = javaTypeInput ? javaTypeInput.value.trim() : '';
-        if (propertyName || javaType) columnOverrides.push({ columnName: input.dataset.column, propertyName, javaType });
+        if (propertyName || javaType) overrides.push({ columnName: input.dataset.column, propertyName, javaType });
     });
+    if (document.getElementById('columnScopeTable').checked) {
+        tableOverrides[tableName] = {
+            domainObjectName: document.getElementById('overrideDomainObjectName').value.trim(),
+            mapperName: document.getElementById('overrideMapperName').value.trim(),
+            generateKeys: document.getElementById('overrideGenerateKeys').value.trim(),
+            keyStrategy: document.getElementById('overrideKeyStrategy').value,
+            sequenceName: document.getElementById('overrideSequenceName').value.trim(),
+            ignoredColumns: ignored,
+            columnOverrides: overrides
+        };
+    } else {
+        delete tableOverrides[tableName];
+        ignoredColumns = ignored;
+        columnOverrides = overrides;
+    }
+    return { ignored, overrides };
+}
+
+// 生成请求中的单表覆盖配置；单表时带上手动修改过的实体类名/Mapper名
+function buildTableOverrides() {
+    const result = {};
+    selectedTables.forEach(t => { if (tableOverrides[t]) result[t] = { ...tableOverrides[t] }; });
+    if (selectedTables.length === 1) {
+        const t = selectedTables[0];
+        const domainNameEl = document.getElementById('domainObjectName');
+        const mapperNameEl = document.getElementById('mapperName');
+        const entry = result[t] || {};
+        if (domainNameEl.dataset.userEdited && domainNameEl.value.trim()) entry.domainObjectName = domainNameEl.value.trim();
+        if (mapperNameEl.dataset.userEdited && mapperNameEl.value.trim()) entry.mapperName = mapperNameEl.value.trim();
+        if (Object.keys(entry).length > 0) result[t] = entry;
+    }
+    return result;
+}
+
+function applyColumnSettings() {
+    const { ignored, overrides } = saveColumnSettings(columnModalTable);
+    const scoped = document.getElementById('columnScopeTable').checked;
     hideColumnModal();
     let parts = [];
-    if (ignoredColumns.length > 0) parts.push(`${ignoredColumns.length} 个字段将被忽略`);
-    if (columnOverrides.length > 0) parts.push(`${columnOverrides.length} 个字段已自定义`);
+    if (scoped) parts.push(`仅应用于表 ${columnModalTable}`);
+    if (ignored.length > 0) parts.push(`${ignored.length} 个字段将被忽略`);
+    if (overrides.length > 0) parts.push(`${overrides.length} 个字段已自定义`);
     showMessage(parts.length > 0 ? `列设置已保存：${parts.join('，')}` : '列设置已保存，未做任何修改', 'success');
 }
 
@@ -592,29 +813,63 @@ function applyColumnSettings() {
 // Tab2：刷新面板状态
 // ============================================================
 function refreshSnippetPanelState() {
-    const warningMulti = document.getElementById('snippetMultiTableWarning');
     const warningNone = document.getElementById('snippetNoTableWarning');
     const panel = document.getElementById('snippetPanel');
-    const countEl = document.getElementById('snippetSelectedCount');
 
     if (selectedTables.length === 0) {
-        warningMulti.style.display = 'none';
         warningNone.style.display = 'block';
         panel.style.display = 'none';
-    } else if (selectedTables.length > 1) {
-        countEl.textContent = selectedTables.length;
-        warningMulti.style.display = 'block';
-        warningNone.style.display = 'none';
-        panel.style.display = 'none';
-    } else {
-        warningMulti.style.display = 'none';
-        warningNone.style.display = 'none';
-        panel.style.display = 'block';
-        const tableName = selectedTables[0];
-        document.getElementById('snippetCurrentTable').textContent = tableName;
-        document.getElementById('snippetCurrentModel').textContent = toPascalCase(tableName);
-        loadSnippetTableColumns(tableName);
+        return;
     }
+    warningNone.style.display = 'none';
+    panel.style.display = 'block';
+    renderSnippetTableSelector();
+    switchSnippetTable(currentSnippetTable());
+}
+
+// 片段面板当前表（未选择或已取消勾选时回退到第一张表）
+function currentSnippetTable() {
+    return selectedTables.includes(snippetTable) ? snippetTable : selectedTables[0];
+}
+
+function renderSnippetTableSelector() {
+    const selector = document.getElementById('snippetTableSelector');
+    const current = currentSnippetTable();
+    selector.innerHTML = '';
+    selectedTables.forEach(tableName => {
+        const option = document.createElement('option');
+        const count = (snippetLists[tableName] || []).length;
+        option.value = tableName;
+        option.textContent = count > 0 ? `${tableName}（${count} 个片段）` : tableName;
+        option.selected = tableName === current;
+        selector.appendChild(option);
+    });
+}
+
+function switchSnippetTable(tableName) {
+    if (!tableName) return;
+    const changed = tableName !== snippetTable;
+    snippetTable = tableName;
+    if (!snippetLists[tableName]) snippetLists[tableName] = [];
+    snippetList = snippetLists[tableName];
+    if (changed) editingSnippetIndex = null;
+    document.getElementById('snippetCurrentModel').textContent = namesFor(tableName).domainObjectName;
+    renderSnippetList();
+    loadSnippetTableColumns(tableName);
+    loadSavedSnippets();
+}
+
+// 已勾选表的片段配置（仅包含有片段的表）
+function buildTableSnippetConfigs() {
+    const result = {};
+    selectedTables.forEach(t => {
+        if (snippetLists[t] && snippetLists[t].length > 0) result[t] = snippetLists[t];
+    });
+    return result;
+}
+
+function totalSnippetCount() {
+    return Object.values(buildTableSnippetConfigs()).reduce((sum, list) => sum + list.length, 0);
 }
 
 async function loadSnippetTableColumns(tableName) {
@@ -624,7 +879,17 @@ async function loadSnippetTableColumns(tableName) {
             method: 'POST', headers: { 'Content-Type': 'application/json' },
             body: JSON.stringify({ databaseId: currentDatabaseId, tableName })
         });
-        snippetTableColumns = await response.json();
+        snippetMainColumns = await response.json();
+        // 按命名规则推导属性名与类名
+        try {
+            const [names] = await refreshNaming([tableName], true);
+            snippetMainColumns.forEach(col => {
+                if (names.fields && names.fields[col.columnName]) col.fieldName = names.fields[col.columnName];
+            });
+            document.getElementById('snippetCurrentModel').textContent = names.domainObjectName;
+        } catch (error) {
+            showMessage('命名规则推导失败: ' + error.message, 'error');
+        }
         // Reset chip / where state when table changes
         resetSnippetFieldState();
         renderSnippetFieldPanel();
@@ -634,13 +899,23 @@ async function loadSnippetTableColumns(tableName) {
 }
 
 function resetSnippetFieldState() {
+    snippetJoins = [];
+    snippetResultType = '';
+    snippetTableColumns = snippetMainColumns.slice();
     whereRules = [];
     whereRuleCounter = 0;
     whereLogic = 'AND';
-    selectedChips = { selectFields: new Set(), insertFields: new Set(), setFields: new Set() };
+    selectedChips = emptyChipSelections();
     orderBySelections = new Map();
     selectFieldConfigs = {};
     limitConfig = { hasLimit: false, isLimitFixed: false, limitValue: 'limit' };
+    returnShapeConfig = { shape: 'list', mapKeyIdx: -1 };
+    havingRules = [];
+    havingRuleCounter = 0;
+}
+
+function emptyChipSelections() {
+    return { selectFields: new Set(), insertFields: new Set(), setFields: new Set(), groupByFields: new Set() };
 }
 
 // ============================================================
@@ -651,6 +926,12 @@ function renderSnippetFieldPanel() {
     const isBatch = document.getElementById('snippetIsBatch').checked;
     const container = document.getElementById('snippetFieldPanels');
     const batchHintEl = document.getElementById('snippetBatchHint');
+    // 参数对象仅适用于非批量的 select / delete
+    const paramObjectEl = document.getElementById('snippetParamObject');
+    paramObjectEl.disabled = isBatch || (operation !== 'select' && operation !== 'delete');
+    if (paramObjectEl.disabled) paramObjectEl.checked = false;
+    const paramObject = paramObjectEl.checked;
+    document.getElementById('snippetParamClassName').style.display = paramObject ? '' : 'none';
 
     const batchHints = {
         select: 'IN 查询：WHERE 条件取第一个字段作为 IN 条件，返回列表',
@@ -667,10 +948,14 @@ function renderSnippetFieldPanel() {
 
     let html = '';
     if (operation === 'select') {
+        html += buildJoinPanel();
         html += buildChipPanel('selectFields', '📤 SELECT 返回字段', '在下拉框中搜索或选择字段，已选字段将出现在 SELECT 列表中，可配置聚合函数和别名');
         html += buildQueryBuilderPanel();
+        html += buildChipPanel('groupByFields', '🧮 GROUP BY 分组字段', '选择分组字段后，SELECT 中的非聚合字段必须包含在分组字段中；结果将映射到生成的 DTO');
+        html += buildHavingPanel();
         html += buildOrderByChipPanel();
         html += buildLimitPanel();
+        html += buildReturnShapePanel();
     } else if (operation === 'insert') {
         html += buildChipPanel('insertFields', '📥 INSERT 字段', '在下拉框中搜索或选择字段，已选字段将出现在 INSERT 语句中');
     } else if (operation === 'delete') {
@@ -683,7 +968,7 @@ function renderSnippetFieldPanel() {
     container.innerHTML = html;
     
     // 初始化 Tom Select 并恢复状态
-    ['selectFields', 'insertFields', 'setFields'].forEach(panelId => {
+    CHIP_PANELS.forEach(panelId => {
         const selectEl = document.getElementById(panelId + 'Select');
         if (selectEl) {
             new TomSelect(selectEl, {
@@ -734,13 +1019,14 @@ function renderSnippetFieldPanel() {
     renderSelectFieldDetails();
     renderOrderByFieldDetails();
     renderWhereRules();
+    renderHavingRules();
     syncCombinatorButtons();
     updateMethodNamePlaceholder();
 }
 
 function restoreChipStates() {
     // 恢复 selectFields / insertFields / setFields TomSelect
-    ['selectFields', 'insertFields', 'setFields'].forEach(panelId => {
+    CHIP_PANELS.forEach(panelId => {
         const selectEl = document.getElementById(panelId + 'Select');
         if (selectEl && selectEl.tomselect) {
             const values = Array.from(selectedChips[panelId]).map(v => v.toString());
@@ -796,7 +1082,7 @@ function selectAllFields(panelId) {
 
 function buildChipPanel(panelId, title, hint) {
     const options = snippetTableColumns.map((col, idx) => {
-        return `<option value="${idx}">${col.columnName} (${col.dataType})</option>`;
+        return `<option value="${idx}">${snippetColumnLabel(col)} (${col.dataType})</option>`;
     }).join('');
     const detailsContainer = panelId === 'selectFields' ? `<div id="selectFieldDetailsList" class="select-details-list"></div>` : '';
     return `
@@ -847,7 +1133,7 @@ function renderSelectFieldDetails() {
         
         return `
             <div class="select-detail-row">
-                <span class="select-detail-colname">${col.columnName}</span>
+                <span class="select-detail-colname">${snippetColumnLabel(col)}</span>
                 <select class="qb-op-select" onchange="updateSelectFieldConfig(${idx}, 'aggregate', this.value)">
                     ${aggOptions}
                 </select>
@@ -869,7 +1155,7 @@ function updateSelectFieldConfig(idx, key, val) {
 function buildOrderByChipPanel() {
     const panelId = 'orderByFields';
     const options = snippetTableColumns.map((col, idx) => {
-        return `<option value="${idx}">${col.columnName} (${col.dataType})</option>`;
+        return `<option value="${idx}">${snippetColumnLabel(col)} (${col.dataType})</option>`;
     }).join('');
     
     return `
@@ -898,7 +1184,7 @@ function renderOrderByFieldDetails() {
         if (!col) return '';
         return `
             <div class="select-detail-row">
-                <span class="select-detail-colname">${col.columnName}</span>
+                <span class="select-detail-colname">${snippetColumnLabel(col)}</span>
                 <select class="qb-op-select" onchange="orderBySelections.set(${idx}, this.value)">
                     <option value="ASC" ${dir === 'ASC' ? 'selected' : ''}>ASC ↑</option>
                     <option value="DESC" ${dir === 'DESC' ? 'selected' : ''}>DESC ↓</option>
@@ -939,6 +1225,34 @@ function buildLimitPanel() {
     `;
 }
 
+function buildReturnShapePanel() {
+    const shape = returnShapeConfig.shape;
+    const shapeOptions = RETURN_SHAPES.map(([value, label]) =>
+        `<option value="${value}" ${shape === value ? 'selected' : ''}>${escapeHtml(label)}</option>`).join('');
+    const keyOptions = ['<option value="-1">选择键列</option>'].concat(snippetTableColumns.map((c, i) =>
+        `<option value="${i}" ${returnShapeConfig.mapKeyIdx === i ? 'selected' : ''}>${escapeHtml((c.tableAlias ? c.tableAlias + '.' : '') + c.columnName)}</option>`
+    )).join('');
+    return `
+        <div class="snippet-field-panel">
+            <div class="snippet-field-panel-title">↩️ 返回形式</div>
+            <div class="snippet-field-panel-hint">count / exists 忽略查询列与排序且不支持 LIMIT；批量查询仅支持 List 或 @MapKey</div>
+            <div class="select-details-list" style="margin-top: 8px;">
+                <div class="select-detail-row">
+                    <select class="qb-op-select" onchange="changeReturnShape(this.value)">${shapeOptions}</select>
+                    <select id="mapKeySelect" class="qb-op-select" style="display:${shape === 'mapKey' ? '' : 'none'};"
+                        onchange="returnShapeConfig.mapKeyIdx = parseInt(this.value, 10)">${keyOptions}</select>
+                </div>
+            </div>
+        </div>
+    `;
+}
+
+function changeReturnShape(shape) {
+    returnShapeConfig.shape = shape;
+    const keySelect = document.getElementById('mapKeySelect');
+    if (keySelect) keySelect.style.display = shape === 'mapKey' ? '' : 'none';
+}
+
 function toggleLimitModeSelect() {
     const select = document.getElementById('limitModeSelect');
     if (!select) return;
@@ -995,9 +1309,10 @@ function collectChipFields(panelId) {
     selectedChips[panelId].forEach(colIdx => {
         const col = snippetTableColumns[colIdx];
         if (col) {
-            const override = columnOverrides.find(o => o.columnName === col.columnName) || {};
+            const override = snippetColumnOverride(col);
             const fieldObj = {
                 columnName: col.columnName,
+                tableAlias: col.tableAlias || '',
                 fieldName: override.propertyName || col.fieldName || snakeToCamel(col.columnName),
                 jdbcType: override.jdbcType || col.jdbcType || col.dataType.toUpperCase(),
                 javaType: override.javaType || col.javaType || 'Object'
@@ -1017,9 +1332,10 @@ function collectOrderByFields() {
     orderBySelections.forEach((direction, colIdx) => {
         const col = snippetTableColumns[colIdx];
         if (col) {
-            const override = columnOverrides.find(o => o.columnName === col.columnName) || {};
+            const override = snippetColumnOverride(col);
             result.push({
                 columnName: col.columnName,
+                tableAlias: col.tableAlias || '',
                 fieldName: override.propertyName || col.fieldName || snakeToCamel(col.columnName),
                 jdbcType: override.jdbcType || col.jdbcType || col.dataType.toUpperCase(),
                 direction
@@ -1029,6 +1345,175 @@ function collectOrderByFields() {
     return result;
 }
 
+// -----------------------------------------------------------------------
+// 关联表（JOIN）构建器
+// -----------------------------------------------------------------------
+function snippetColumnLabel(col) {
+    return col.tableAlias ? `${col.tableAlias}.${col.columnName}` : col.columnName;
+}
+
+function findSnippetColumnIdx(f) {
+    return snippetTableColumns.findIndex(c =>
+        c.columnName === f.columnName && (c.tableAlias || '') === (f.tableAlias || ''));
+}
+
+function buildJoinPanel() {
+    const mainTable = currentSnippetTable();
+    const joinTypes = ['LEFT', 'INNER', 'RIGHT'];
+    const rows = snippetJoins.map((join, i) => {
+        const tableOptions = ['<option value="">选择关联表</option>'].concat(allTables.map(t =>
+            `<option value="${escapeHtml(t)}" ${join.tableName === t ? 'selected' : ''}>${escapeHtml(t)}</option>`
+        )).join('');
+        const typeOptions = joinTypes.map(t =>
+            `<option value="${t}" ${join.joinType === t ? 'selected' : ''}>${t} JOIN</option>`
+        ).join('');
+        const onRows = join.on.map((on, j) => {
+            const leftOptions = snippetMainColumns.map(c =>
+                `<option value="${c.columnName}" ${on.leftColumn === c.columnName ? 'selected' : ''}>${SNIPPET_MAIN_ALIAS}.${c.columnName}</option>`
+            ).join('');
+            const rightOptions = join.columns.map(c =>
+                `<option value="${c.columnName}" ${on.rightColumn === c.columnName ? 'selected' : ''}>${escapeHtml(join.alias)}.${c.columnName}</option>`
+            ).join('');
+            return `
+                <div class="join-on-row">
+                    <span class="join-on-label">${j === 0 ? 'ON' : 'AND'}</span>
+                    <select class="qb-field-select" onchange="updateSnippetJoinOn(${i}, ${j}, 'leftColumn', this.value)">
+                        <option value="">主表列</option>${leftOptions}
+                    </select>
+                    <span>=</span>
+                    <select class="qb-field-select" onchange="updateSnippetJoinOn(${i}, ${j}, 'rightColumn', this.value)">
+                        <option value="">关联表列</option>${rightOptions}
+                    </select>
+                    ${join.on.length > 1 ? `<button class="qb-remove-btn" onclick="removeSnippetJoinOn(${i}, ${j})" title="删除此条件">✕</button>` : ''}
+                </div>`;
+        }).join('');
+        return `
+            <div class="join-row">
+                <div class="join-row-header">
+                    <select class="qb-op-select" onchange="updateSnippetJoin(${i}, 'joinType', this.value)">${typeOptions}</select>
+                    <select class="qb-field-select" onchange="updateSnippetJoin(${i}, 'tableName', this.value)">${tableOptions}</select>
+                    <input type="text" class="qb-fixed-value-input join-alias-input" placeholder="别名" value="${escapeHtml(join.alias)}"
+                        onchange="updateSnippetJoin(${i}, 'alias', this.value)">
+                    <button class="qb-add-rule-btn" onclick="addSnippetJoinOn(${i})">＋ 条件</button>
+                    <button class="qb-remove-btn" onclick="removeSnippetJoin(${i})" title="删除此关联表">✕</button>
+                </div>
+                ${onRows}
+            </div>`;
+    }).join('');
+    const resultTypeInput = snippetJoins.length > 0 ? `
+        <div class="join-result-type">
+            <label>结果DTO类名</label>
+            <input type="text" class="qb-fixed-value-input" placeholder="留空按方法名生成（如 SelectXxxDTO）"
+                value="${escapeHtml(snippetResultType)}" oninput="snippetResultType = this.value">
+        </div>` : '';
+    return `
+        <div class="snippet-field-panel">
+            <div class="snippet-field-panel-title">🔗 关联表（JOIN）</div>
+            <div class="snippet-field-panel-hint">主表 ${escapeHtml(mainTable || '')} 的别名为 ${SNIPPET_MAIN_ALIAS}；添加关联表后可在下方选择关联表的列，结果将映射到生成的 DTO。修改关联表会清空已选字段</div>
+            <div class="join-list">
+                ${rows || '<div class="qb-empty">暂无关联表</div>'}
+                <button class="qb-add-rule-btn" onclick="addSnippetJoin()">＋ 添加关联表</button>
+                ${resultTypeInput}
+            </div>
+        </div>`;
+}
+
+async function loadJoinColumns(join) {
+    if (!join.tableName) { join.columns = []; return; }
+    try {
+        const response = await fetch('/api/columns', {
+            method: 'POST', headers: { 'Content-Type': 'application/json' },
+            body: JSON.stringify({ databaseId: currentDatabaseId, tableName: join.tableName })
+        });
+        const columns = await response.json();
+        if (!response.ok) throw new Error(columns.error);
+        join.columns = columns;
+    } catch (error) {
+        join.columns = [];
+        showMessage(`加载关联表 ${join.tableName} 的列失败: ${error.message}`, 'error');
+    }
+}
+
+// 主表列在前，关联表列在后（带别名）
+function rebuildSnippetColumns() {
+    snippetTableColumns = snippetMainColumns.slice();
+    snippetJoins.forEach(join => {
+        join.columns.forEach(col => {
+            snippetTableColumns.push({ ...col, tableAlias: join.alias, fieldName: snakeToCamel(col.columnName) });
+        });
+    });
+}
+
+// 关联表变化后列索引会改变，清空已选字段后重新渲染
+function onSnippetJoinsChanged() {
+    whereRules = [];
+    selectedChips = emptyChipSelections();
+    orderBySelections = new Map();
+    selectFieldConfigs = {};
+    havingRules = [];
+    rebuildSnippetColumns();
+    renderSnippetFieldPanel();
+}
+
+function nextJoinAlias() {
+    let n = 1;
+    while (snippetJoins.some(j => j.alias === 'j' + n)) n++;
+    return 'j' + n;
+}
+
+function addSnippetJoin() {
+    snippetJoins.push({ joinType: 'LEFT', tableName: '', alias: nextJoinAlias(), on: [{ leftColumn: '', rightColumn: '' }], columns: [] });
+    onSnippetJoinsChanged();
+}
+
+function removeSnippetJoin(i) {
+    snippetJoins.splice(i, 1);
+    onSnippetJoinsChanged();
+}
+
+async function updateSnippetJoin(i, key, value) {
+    const join = snippetJoins[i];
+    if (!join) return;
+    if (key === 'alias') {
+        value = value.trim();
+        if (!value || value === SNIPPET_MAIN_ALIAS || snippetJoins.some((j, idx) => idx !== i && j.alias === value)) {
+            showMessage('别名不能为空且不能重复', 'error');
+            renderSnippetFieldPanel();
+            return;
+        }
+    }
+    join[key] = value;
+    if (key === 'tableName') {
+        join.on = [{ leftColumn: '', rightColumn: '' }];
+        await loadJoinColumns(join);
+    }
+    if (key === 'joinType') {
+        renderSnippetFieldPanel();
+    } else {
+        onSnippetJoinsChanged();
+    }
+}
+
+function updateSnippetJoinOn(i, j, key, value) {
+    if (snippetJoins[i] && snippetJoins[i].on[j]) snippetJoins[i].on[j][key] = value;
+}
+
+function addSnippetJoinOn(i) {
+    snippetJoins[i].on.push({ leftColumn: '', rightColumn: '' });
+    renderSnippetFieldPanel();
+}
+
+function removeSnippetJoinOn(i, j) {
+    snippetJoins[i].on.splice(j, 1);
+    renderSnippetFieldPanel();
+}
+
+// 列定制仅作用于主表列
+function snippetColumnOverride(col) {
+    if (col.tableAlias) return {};
+    return columnOverridesFor(currentSnippetTable()).find(o => o.columnName === col.columnName) || {};
+}
+
 function snakeToCamel(s) {
     return s.replace(/_([a-z])/g, (_, c) => c.toUpperCase());
 }
@@ -1198,13 +1683,15 @@ function renderWhereRules() {
     const badgeText = isOr ? 'OR' : 'AND';
     const parts = whereRules.map((rule, idx) => {
         const fieldOptions = snippetTableColumns.map((col, i) =>
-            `<option value="${i}" ${rule.fieldIdx === i ? 'selected' : ''}>${col.columnName}  (${col.dataType})</option>`
+            `<option value="${i}" ${rule.fieldIdx === i ? 'selected' : ''}>${snippetColumnLabel(col)}  (${col.dataType})</option>`
         ).join('');
         // IS NULL/NOT NULL 不需要固定值切换
         const noValueOps = ['IS NULL', 'IS NOT NULL'];
         const showValueToggle = !noValueOps.includes(rule.operator);
         const isFixed = !!rule.isFixed;
         const currentColumn = snippetTableColumns[rule.fieldIdx] || {};
+        // 固定值与空值判断始终生效，不提供可选开关
+        const showOptional = showValueToggle && !isFixed;
         const fixedValInput = (isFixed && showValueToggle)
             ? getInputHtmlForDataType(rule, currentColumn) : '';
         const toggleBtn = showValueToggle
@@ -1233,6 +1720,9 @@ function renderWhereRules() {
                 </select>
                 ${toggleBtn}
                 ${fixedValInput}
+                ${showOptional ? `<label class="qb-optional-toggle" title="参数为空时忽略该条件">
+                    <input type="checkbox" ${rule.optional ? 'checked' : ''}
+                        onchange="updateWhereRule(${rule.id}, 'optional', this.checked)"> 可选</label>` : ''}
                 ${conflictTip}
                 <button class="qb-remove-btn" onclick="removeWhereRule(${rule.id})" title="删除此条件">✕</button>
             </div>`;
@@ -1244,14 +1734,110 @@ function collectWhereConditions() {
     return whereRules.map(rule => {
         const col = snippetTableColumns[rule.fieldIdx];
         if (!col) return null;
-        const override = columnOverrides.find(o => o.columnName === col.columnName) || {};
+        const override = snippetColumnOverride(col);
+        // 关联表的参数名带上别名前缀，避免与主表同名参数冲突
+        const fieldName = override.propertyName || col.fieldName || snakeToCamel(col.columnName);
         return {
             columnName: col.columnName,
-            fieldName: override.propertyName || col.fieldName || snakeToCamel(col.columnName),
+            tableAlias: col.tableAlias || '',
+            fieldName: col.tableAlias ? col.tableAlias + fieldName.charAt(0).toUpperCase() + fieldName.slice(1) : fieldName,
             jdbcType: override.jdbcType || col.jdbcType || col.dataType.toUpperCase(),
             javaType: override.javaType || col.javaType || 'Object',
             operator: rule.operator || '=',
             isFixed: !!rule.isFixed,
+            fixedValue: rule.fixedValue || '',
+            optional: !!rule.optional && !rule.isFixed
+        };
+    }).filter(Boolean);
+}
+
+// -----------------------------------------------------------------------
+// HAVING 条件构建器
+// -----------------------------------------------------------------------
+const HAVING_AGGREGATES = ['COUNT', 'SUM', 'MAX', 'MIN', 'AVG'];
+const HAVING_OPERATORS = ['=', '!=', '>', '<', '>=', '<='];
+
+function buildHavingPanel() {
+    return `
+        <div class="snippet-field-panel">
+            <div class="snippet-field-panel-title">📊 HAVING 分组条件</div>
+            <div class="snippet-field-panel-hint">对聚合结果过滤，多个条件之间为 AND；变量模式下参数名留空将按 函数+列名 生成</div>
+            <div class="qb-container">
+                <div class="qb-group">
+                    <div class="qb-header">
+                        <span></span>
+                        <button class="qb-add-rule-btn" onclick="addHavingRule()">＋ 添加条件</button>
+                    </div>
+                    <div class="qb-rules-list" id="havingRulesList"></div>
+                </div>
+            </div>
+        </div>`;
+}
+
+function addHavingRule() {
+    havingRules.push({ id: havingRuleCounter++, aggregate: 'COUNT', fieldIdx: -1, operator: '>', isFixed: false, fixedValue: '', paramName: '' });
+    renderHavingRules();
+}
+
+function removeHavingRule(id) {
+    havingRules = havingRules.filter(r => r.id !== id);
+    renderHavingRules();
+}
+
+function updateHavingRule(id, key, value) {
+    const rule = havingRules.find(r => r.id === id);
+    if (!rule) return;
+    rule[key] = key === 'fieldIdx' ? parseInt(value) : value;
+    if (key === 'isFixed') renderHavingRules();
+}
+
+function renderHavingRules() {
+    const container = document.getElementById('havingRulesList');
+    if (!container) return;
+    if (havingRules.length === 0) {
+        container.innerHTML = '<div class="qb-empty">暂无条件，点击上方"添加条件"按钮</div>';
+        return;
+    }
+    container.innerHTML = havingRules.map((rule, idx) => {
+        const aggOptions = HAVING_AGGREGATES.map(a =>
+            `<option value="${a}" ${rule.aggregate === a ? 'selected' : ''}>${a}()</option>`).join('');
+        const fieldOptions = `<option value="-1" ${rule.fieldIdx === -1 ? 'selected' : ''}>*</option>` +
+            snippetTableColumns.map((col, i) =>
+                `<option value="${i}" ${rule.fieldIdx === i ? 'selected' : ''}>${snippetColumnLabel(col)}</option>`).join('');
+        const opOptions = HAVING_OPERATORS.map(op =>
+            `<option value="${op}" ${rule.operator === op ? 'selected' : ''}>${op}</option>`).join('');
+        const valueInput = rule.isFixed
+            ? `<input type="text" class="qb-fixed-value-input" placeholder="固定值" value="${escapeHtml(rule.fixedValue)}"
+                oninput="updateHavingRule(${rule.id}, 'fixedValue', this.value)">`
+            : `<input type="text" class="qb-fixed-value-input" placeholder="参数名（可选）" value="${escapeHtml(rule.paramName)}"
+                oninput="updateHavingRule(${rule.id}, 'paramName', this.value.trim())">`;
+        return `
+            <div class="qb-rule">
+                <span class="qb-rule-number">${idx + 1}.</span>
+                <select class="qb-op-select" onchange="updateHavingRule(${rule.id}, 'aggregate', this.value)">${aggOptions}</select>
+                <select class="qb-field-select" onchange="updateHavingRule(${rule.id}, 'fieldIdx', this.value)">${fieldOptions}</select>
+                <select class="qb-op-select" onchange="updateHavingRule(${rule.id}, 'operator', this.value)">${opOptions}</select>
+                <button class="qb-mode-toggle ${rule.isFixed ? 'fixed' : 'var'}" title="切换变量/固定值模式"
+                    onclick="updateHavingRule(${rule.id}, 'isFixed', ${!rule.isFixed})">${rule.isFixed ? '🔒 固定值' : '🔑 变量'}</button>
+                ${valueInput}
+                <button class="qb-remove-btn" onclick="removeHavingRule(${rule.id})" title="删除此条件">✕</button>
+            </div>`;
+    }).join('');
+}
+
+function collectHavingConditions() {
+    return havingRules.map(rule => {
+        const col = rule.fieldIdx >= 0 ? snippetTableColumns[rule.fieldIdx] : null;
+        if (rule.fieldIdx >= 0 && !col) return null;
+        return {
+            columnName: col ? col.columnName : '*',
+            tableAlias: col ? (col.tableAlias || '') : '',
+            fieldName: rule.paramName || '',
+            jdbcType: col ? (col.jdbcType || col.dataType.toUpperCase()) : '',
+            javaType: col ? (col.javaType || 'Object') : '',
+            aggregate: rule.aggregate,
+            operator: rule.operator || '=',
+            isFixed: !!rule.isFixed,
             fixedValue: rule.fixedValue || ''
         };
     }).filter(Boolean);
@@ -1264,8 +1850,10 @@ function buildCurrentSnippetConfig() {
     const operation = document.getElementById('snippetOperation').value;
     const isBatch = document.getElementById('snippetIsBatch').checked;
     const methodName = document.getElementById('snippetMethodName').value.trim();
+    const paramObject = document.getElementById('snippetParamObject').checked;
+    const paramClassName = document.getElementById('snippetParamClassName').value.trim();
     const cfg = {
-        operation, isBatch, methodName,
+        operation, isBatch, methodName, paramObject, paramClassName: paramObject ? paramClassName : '',
         whereLogic,
         selectFields: [], whereFields: [], orderByFields: [], insertFields: [], setFields: []
     };
@@ -1274,9 +1862,32 @@ function buildCurrentSnippetConfig() {
         cfg.selectFields = collectChipFields('selectFields');
         cfg.whereFields = whereConditions;
         cfg.orderByFields = collectOrderByFields();
+        cfg.groupByFields = collectChipFields('groupByFields');
+        cfg.havingFields = collectHavingConditions();
         cfg.hasLimit = limitConfig.hasLimit;
         cfg.isLimitFixed = limitConfig.isLimitFixed;
         cfg.limitValue = limitConfig.limitValue;
+        if (returnShapeConfig.shape !== 'list') cfg.returnShape = returnShapeConfig.shape;
+        if (returnShapeConfig.shape === 'mapKey') {
+            const col = snippetTableColumns[returnShapeConfig.mapKeyIdx];
+            if (col) {
+                const override = snippetColumnOverride(col);
+                cfg.mapKey = {
+                    columnName: col.columnName,
+                    tableAlias: col.tableAlias || '',
+                    fieldName: override.propertyName || col.fieldName || snakeToCamel(col.columnName),
+                    javaType: override.javaType || col.javaType || 'Object'
+                };
+            }
+        }
+        if (snippetJoins.length > 0) {
+            cfg.tableAlias = SNIPPET_MAIN_ALIAS;
+            cfg.joins = snippetJoins.map(j => ({
+                joinType: j.joinType, tableName: j.tableName, alias: j.alias,
+                on: j.on.filter(o => o.leftColumn && o.rightColumn)
+            }));
+            if (snippetResultType.trim()) cfg.resultType = snippetResultType.trim();
+        }
     } else if (operation === 'insert') {
         cfg.insertFields = collectChipFields('insertFields');
     } else if (operation === 'delete') {
@@ -1296,6 +1907,20 @@ function addSnippet() {
         cfg.insertFields.length > 0 || cfg.setFields.length > 0 || cfg.orderByFields.length > 0;
     if (!hasFields && cfg.operation !== 'select') { showMessage('请至少配置一个字段或条件', 'error'); return; }
 
+    if (cfg.joins) {
+        if (cfg.selectFields.length === 0) { showMessage('关联查询需要选择 SELECT 返回字段', 'error'); return; }
+        if (cfg.joins.some(j => !j.tableName || j.on.length === 0)) {
+            showMessage('请为每个关联表选择表并配置关联条件', 'error'); return;
+        }
+    }
+
+    const noValueOps = ['IS NULL', 'IS NOT NULL'];
+    if (!cfg.isBatch && (cfg.operation === 'delete' || cfg.operation === 'update') &&
+        !cfg.whereFields.some(f => !f.optional || noValueOps.includes(f.operator))) {
+        showMessage('删除/更新至少需要一个非可选的WHERE条件', 'error');
+        return;
+    }
+
     if (cfg.isBatch && cfg.operation !== 'insert' && cfg.whereFields.length === 0) {
         showMessage('批量操作需要至少一个WHERE条件', 'error');
         return;
@@ -1335,6 +1960,187 @@ function addSnippet() {
     renderSnippetList();
 }
 
+// 按 Spring Data 命名规则解析方法名，直接添加为片段
+async function parseSnippetMethodName() {
+    if (!currentDatabaseId) { showMessage('请先选择数据库连接', 'error'); return; }
+    const input = document.getElementById('snippetMethodParseInput');
+    const methodName = input.value.trim();
+    if (!methodName) { showMessage('请输入方法名', 'error'); return; }
+    if (snippetList.some(s => s.methodName === methodName)) {
+        showMessage(`方法名 '${methodName}' 已存在，请使用其他名称！`, 'error');
+        return;
+    }
+
+    const tableName = currentSnippetTable();
+    const modelPackage = document.getElementById('modelPackage').value || 'com.example.model';
+    try {
+        const response = await fetch('/api/snippet/parse-method', {
+            method: 'POST', headers: { 'Content-Type': 'application/json' },
+            body: JSON.stringify({
+                databaseId: currentDatabaseId, tableName, methodName,
+                mapperName: namesFor(tableName).mapperName,
+                modelType: modelPackage + '.' + namesFor(tableName).domainObjectName,
+                useLombok: useLombokForSnippets()
+            })
+        });
+        const result = await response.json();
+        if (!response.ok || !result.success) {
+            showMessage('解析失败: ' + result.error, 'error');
+            return;
+        }
+        snippetList.push(result.snippetConfig);
+        renderSnippetList();
+        input.value = '';
+        showMessage(`片段已添加（共 ${snippetList.length} 个）`, 'success');
+        document.getElementById('snippetJavaCode').textContent = snippetJavaPreview(result);
+        document.getElementById('snippetXmlCode').textContent = result.xmlCode;
+        document.getElementById('snippetPreviewModal').style.display = 'block';
+    } catch (error) {
+        showMessage('解析失败: ' + error.message, 'error');
+    }
+}
+
+// 导入手写 SQL：转换为 Mapper 方法和 XML 语句后预览
+async function importSnippetSQL() {
+    if (!currentDatabaseId) { showMessage('请先选择数据库连接', 'error'); return; }
+    const methodName = document.getElementById('sqlImportMethodName').value.trim();
+    const sql = document.getElementById('sqlImportText').value.trim();
+    if (!methodName || !sql) { showMessage('请输入方法名和 SQL', 'error'); return; }
+
+    const tableName = currentSnippetTable();
+    const modelPackage = document.getElementById('modelPackage').value || 'com.example.model';
+    const warningsEl = document.getElementById('sqlImportWarnings');
+    warningsEl.style.display = 'none';
+    try {
+        const response = await fetch('/api/snippet/import-sql', {
+            method: 'POST', headers: { 'Content-Type': 'application/json' },
+            body: JSON.stringify({
+                databaseId: currentDatabaseId, methodName, sql,
+                modelType: modelPackage + '.' + namesFor(tableName).domainObjectName
+            })
+        });
+        const result = await response.json();
+        if (!response.ok || !result.success) {
+            showMessage('导入失败: ' + result.error, 'error');
+            return;
+        }
+        if (result.warnings && result.warnings.length > 0) {
+            warningsEl.textContent = '⚠️ ' + result.warnings.join('；');
+            warningsEl.style.display = 'block';
+        }
+        const importLines = (result.imports || []).map(imp => 'import ' + imp + ';').join('\n');
+        document.getElementById('snippetJavaCode').textContent =
+            (importLines ? '// 如需单独使用，请确认添加以下 import:\n' + importLines + '\n\n' : '') + result.javaCode;
+        document.getElementById('snippetXmlCode').textContent = result.xmlCode;
+        document.getElementById('snippetPreviewModal').style.display = 'block';
+    } catch (error) {
+        showMessage('导入失败: ' + error.message, 'error');
+    }
+}
+
+// 将当前表的片段合并到上传的已有 Mapper 接口和 XML，下载修改后的文件
+async function mergeIntoExistingMapper() {
+    if (snippetList.length === 0) { showMessage('请先添加至少一个自定义片段', 'error'); return; }
+    const javaInput = document.getElementById('mergeMapperJava');
+    const xmlInput = document.getElementById('mergeMapperXml');
+    if (!javaInput.files[0] || !xmlInput.files[0]) { showMessage('请选择 Mapper 接口和 Mapper.xml 文件', 'error'); return; }
+
+    const tableName = currentSnippetTable();
+    const modelPackage = document.getElementById('modelPackage').value || 'com.example.model';
+    try {
+        const response = await fetch('/api/snippet/merge-mapper', {
+            method: 'POST', headers: { 'Content-Type': 'application/json' },
+            body: JSON.stringify({
+                databaseId: currentDatabaseId, tableName,
+                modelType: modelPackage + '.' + namesFor(tableName).domainObjectName,
+                javaFileName: javaInput.files[0].name, javaContent: await javaInput.files[0].text(),
+                xmlFileName: xmlInput.files[0].name, xmlContent: await xmlInput.files[0].text(),
+                snippetConfigs: snippetList,
+                conflictStrategy: document.getElementById('snippetConflictStrategy').value,
+                useLombok: useLombokForSnippets()
+            })
+        });
+        const result = await response.json();
+        if (!response.ok || !result.success) {
+            const conflicts = (result.snippets || []).filter(p => p.conflicts && p.conflicts.length > 0)
+                .map(p => `${p.methodName}：${p.conflicts.join('，')}`);
+            showMessage('合并失败: ' + result.error + (conflicts.length > 0 ? '；' + conflicts.join('；') : ''), 'error');
+            return;
+        }
+        [result.javaFile, result.xmlFile, ...result.dtoFiles].forEach(f => downloadTextFile(f.name, f.content));
+        const renamed = result.snippets.filter(p => p.originalName).map(p => `${p.originalName} → ${p.methodName}`);
+        showMessage(`已合并 ${result.snippets.length} 个片段` + (renamed.length > 0 ? '；重命名：' + renamed.join('，') : ''), 'success');
+    } catch (error) {
+        showMessage('合并失败: ' + error.message, 'error');
+    }
+}
+
+// 解析上传的 Mapper XML，将可表达的语句加入当前表的片段列表，其余语句原样列出
+async function importExistingMapperXML() {
+    const input = document.getElementById('importMapperXml');
+    if (!input.files[0]) { showMessage('请选择 Mapper.xml 文件', 'error'); return; }
+
+    const tableName = currentSnippetTable();
+    const modelPackage = document.getElementById('modelPackage').value || 'com.example.model';
+    const rawDiv = document.getElementById('mapperXmlImportRaw');
+    try {
+        const response = await fetch('/api/snippet/import-mapper-xml', {
+            method: 'POST', headers: { 'Content-Type': 'application/json' },
+            body: JSON.stringify({
+                databaseId: currentDatabaseId, tableName,
+                modelType: modelPackage + '.' + namesFor(tableName).domainObjectName,
+                xml: await input.files[0].text()
+            })
+        });
+        const result = await response.json();
+        if (!response.ok || !result.success) {
+            showMessage('导入失败: ' + result.error, 'error');
+            return;
+        }
+
+        const skipped = [];
+        (result.snippets || []).forEach(s => {
+            if (snippetList.some(existing => existing.methodName === s.config.methodName)) {
+                skipped.push(s.statementId);
+                return;
+            }
+            snippetList.push(s.config);
+        });
+        renderSnippetList();
+
+        const raw = result.raw || [];
+        if (raw.length > 0) {
+            rawDiv.innerHTML = '⚠️ 以下语句无法转换为片段，保留原样：<br>' +
+                raw.map(r => `${escapeHtml(r.statementId)}（${escapeHtml(r.kind)}）：${escapeHtml(r.reason)}`).join('<br>');
+            rawDiv.style.display = 'block';
+        } else {
+            rawDiv.style.display = 'none';
+        }
+        const imported = (result.snippets || []).length - skipped.length;
+        showMessage(`已导入 ${imported} 个片段` +
+            (skipped.length > 0 ? `；方法名已存在而跳过：${skipped.join('，')}` : '') +
+            (raw.length > 0 ? `；${raw.length} 条语句未识别` : ''), 'success');
+    } catch (error) {
+        showMessage('导入失败: ' + error.message, 'error');
+    }
+}
+
+// 参数对象类的 Lombok 风格与 Tab1 的实体类配置一致
+function useLombokForSnippets() {
+    const el = document.getElementById('useLombokPlugin');
+    return !!(el && el.checked);
+}
+
+function downloadTextFile(name, content) {
+    const a = document.createElement('a');
+    a.href = URL.createObjectURL(new Blob([content], { type: 'text/plain;charset=utf-8' }));
+    a.download = name;
+    document.body.appendChild(a);
+    a.click();
+    document.body.removeChild(a);
+    URL.revokeObjectURL(a.href);
+}
+
 function updateSnippetMethodName(idx, val) {
     if (snippetList[idx]) {
         const newName = val.trim();
@@ -1387,44 +2193,69 @@ function cancelEditSnippet() {
     }
     // reset form by re-rendering
     document.getElementById('snippetMethodName').value = '';
+    document.getElementById('snippetParamObject').checked = false;
+    document.getElementById('snippetParamClassName').value = '';
     resetSnippetFieldState();
     renderSnippetFieldPanel();
 }
 
-function loadSnippetIntoForm(cfg) {
+async function loadSnippetIntoForm(cfg) {
+    // 先恢复关联表（需要加载关联表的列）
+    snippetJoins = (cfg.joins || []).map(j => ({
+        joinType: j.joinType || 'LEFT', tableName: j.tableName, alias: j.alias,
+        on: (j.on || []).map(o => ({ leftColumn: o.leftColumn, rightColumn: o.rightColumn })), columns: []
+    }));
+    snippetResultType = cfg.resultType || '';
+    await Promise.all(snippetJoins.map(loadJoinColumns));
+    rebuildSnippetColumns();
+
     document.getElementById('snippetOperation').value = cfg.operation;
     document.getElementById('snippetIsBatch').checked = cfg.isBatch;
     document.getElementById('snippetMethodName').value = cfg.methodName || '';
+    document.getElementById('snippetParamObject').checked = !!cfg.paramObject;
+    document.getElementById('snippetParamClassName').value = cfg.paramClassName || '';
     // 恢复 WHERE 状态
     whereRules = [];
     whereRuleCounter = 0;
     whereLogic = cfg.whereLogic || 'AND';
     // 恢复 chip 状态
-    selectedChips = { selectFields: new Set(), insertFields: new Set(), setFields: new Set() };
+    selectedChips = emptyChipSelections();
     orderBySelections = new Map();
     // 恢复 WHERE rules
     (cfg.whereFields || []).forEach(f => {
-        const colIdx = snippetTableColumns.findIndex(c => c.columnName === f.columnName);
+        const colIdx = findSnippetColumnIdx(f);
         if (colIdx >= 0) {
-            whereRules.push({ id: whereRuleCounter++, fieldIdx: colIdx, operator: f.operator || '=' });
+            whereRules.push({ id: whereRuleCounter++, fieldIdx: colIdx, operator: f.operator || '=', optional: !!f.optional });
         }
     });
     // 恢复 chip 面板
-    const chipMap = { selectFields: 'selectFields', insertFields: 'insertFields', setFields: 'setFields' };
+    const chipMap = { selectFields: 'selectFields', insertFields: 'insertFields', setFields: 'setFields', groupByFields: 'groupByFields' };
     Object.entries(chipMap).forEach(([cfgKey, panelId]) => {
         (cfg[cfgKey] || []).forEach(f => {
-            const colIdx = snippetTableColumns.findIndex(c => c.columnName === f.columnName);
+            const colIdx = findSnippetColumnIdx(f);
             if (colIdx >= 0) selectedChips[panelId].add(colIdx);
         });
     });
+    // 恢复 HAVING
+    havingRules = [];
+    havingRuleCounter = 0;
+    (cfg.havingFields || []).forEach(f => {
+        const colIdx = f.columnName === '*' ? -1 : findSnippetColumnIdx(f);
+        if (f.columnName === '*' || colIdx >= 0) {
+            havingRules.push({
+                id: havingRuleCounter++, aggregate: f.aggregate || 'COUNT', fieldIdx: colIdx, operator: f.operator || '=',
+                isFixed: !!f.isFixed, fixedValue: f.fixedValue || '', paramName: f.fieldName || ''
+            });
+        }
+    });
     // 恢复 ORDER BY
     (cfg.orderByFields || []).forEach(f => {
-        const colIdx = snippetTableColumns.findIndex(c => c.columnName === f.columnName);
+        const colIdx = findSnippetColumnIdx(f);
         if (colIdx >= 0) orderBySelections.set(colIdx, f.direction || 'ASC');
     });
     // 恢复 SELECT 的聚合和别名
     (cfg.selectFields || []).forEach(f => {
-        const colIdx = snippetTableColumns.findIndex(c => c.columnName === f.columnName);
+        const colIdx = findSnippetColumnIdx(f);
         if (colIdx >= 0 && (f.aggregate || f.alias)) {
             selectFieldConfigs[colIdx] = { aggregate: f.aggregate || '', alias: f.alias || '' };
         }
@@ -1434,6 +2265,10 @@ function loadSnippetIntoForm(cfg) {
         limitConfig.hasLimit = !!cfg.hasLimit;
         limitConfig.isLimitFixed = !!cfg.isLimitFixed;
         limitConfig.limitValue = cfg.limitValue || '';
+        returnShapeConfig = {
+            shape: cfg.returnShape || 'list',
+            mapKeyIdx: cfg.mapKey ? findSnippetColumnIdx(cfg.mapKey) : -1
+        };
     }
     renderSnippetFieldPanel();
 }
@@ -1443,6 +2278,7 @@ function renderSnippetList() {
     const container = document.getElementById('snippetItems');
     const countEl = document.getElementById('snippetCount');
     if (countEl) countEl.textContent = snippetList.length + ' 个';
+    renderSnippetTableSelector();
     if (snippetList.length === 0) {
         container.innerHTML = '<div class="snippet-empty">暂未添加任何片段</div>';
         return;
@@ -1477,6 +2313,7 @@ function renderSnippetList() {
                     <div class="snippet-item-actions">
                         <button class="btn btn-sm btn-secondary" onclick="showSnippetPreviewModal(${i})" title="弹窗预览">👁️ 预览</button>
                         <button class="btn btn-sm btn-info" onclick="editSnippet(${i})" title="加载到编辑区修改">✏️ 编辑</button>
+                        <button class="btn btn-sm btn-secondary" onclick="saveSnippetToLibrary(${i})" title="保存到片段库">💾</button>
                         <button class="btn btn-sm btn-danger" onclick="removeSnippet(${i})">🗑️</button>
                     </div>
                 </div>
@@ -1486,6 +2323,99 @@ function renderSnippetList() {
 
 
 
+// -----------------------------------------------------------------------
+// 片段库（按连接和表保存）
+// -----------------------------------------------------------------------
+async function loadSavedSnippets() {
+    const tableName = currentSnippetTable();
+    savedSnippets = [];
+    if (currentDatabaseId && tableName) {
+        try {
+            const params = new URLSearchParams({ databaseId: currentDatabaseId, tableName });
+            const response = await fetch('/api/snippets?' + params);
+            if (response.ok) savedSnippets = await response.json();
+        } catch (error) {
+            console.error('加载片段库失败:', error);
+        }
+    }
+    renderSavedSnippets();
+}
+
+function renderSavedSnippets() {
+    const container = document.getElementById('savedSnippetItems');
+    const countEl = document.getElementById('savedSnippetCount');
+    if (!container) return;
+    if (countEl) countEl.textContent = savedSnippets.length + ' 个';
+    if (savedSnippets.length === 0) {
+        container.innerHTML = '<div class="snippet-empty">片段库为空，点击片段的 💾 按钮保存</div>';
+        return;
+    }
+    container.innerHTML = savedSnippets.map((s, i) => `
+        <div class="snippet-item">
+            <div style="display:flex; flex-direction:column; gap:3px; flex:1; min-width:0;">
+                <strong>${escapeHtml(s.name)}</strong>
+                <span class="snippet-item-meta">${escapeHtml(s.description || '无描述')} · ${s.updatedAt}</span>
+            </div>
+            <div class="snippet-item-actions">
+                <button class="btn btn-sm btn-info" onclick="useSavedSnippet(${i})" title="加入当前片段列表以便编辑">➕ 加入列表</button>
+                <button class="btn btn-sm btn-danger" onclick="deleteSavedSnippet(${i})">🗑️</button>
+            </div>
+        </div>`).join('');
+}
+
+async function saveSnippetToLibrary(idx) {
+    const cfg = snippetList[idx];
+    if (!cfg) return;
+    if (!currentDatabaseId) { showMessage('请先选择数据库连接', 'error'); return; }
+    const methodName = cfg.methodName || computeMethodName(cfg);
+    const existing = savedSnippets.find(s => s.name === methodName);
+    const description = prompt('片段描述（可选）:', existing ? existing.description : '');
+    if (description === null) return;
+
+    const body = {
+        databaseId: currentDatabaseId, tableName: currentSnippetTable(), name: methodName, description,
+        config: { ...cfg, methodName }
+    };
+    const url = existing ? `/api/snippets/${existing.id}` : '/api/snippets';
+    try {
+        const response = await fetch(url, {
+            method: existing ? 'PUT' : 'POST', headers: { 'Content-Type': 'application/json' }, body: JSON.stringify(body)
+        });
+        const result = await response.json();
+        if (!response.ok) { showMessage('保存失败: ' + result.error, 'error'); return; }
+        showMessage(existing ? `片段库中的 ${methodName} 已更新` : `已保存到片段库：${methodName}`, 'success');
+        loadSavedSnippets();
+    } catch (error) {
+        showMessage('保存失败: ' + error.message, 'error');
+    }
+}
+
+function useSavedSnippet(i) {
+    const saved = savedSnippets[i];
+    if (!saved) return;
+    if (snippetList.some(s => s.methodName === saved.config.methodName)) {
+        showMessage(`方法名 '${saved.config.methodName}' 已在片段列表中`, 'error');
+        return;
+    }
+    snippetList.push(JSON.parse(JSON.stringify(saved.config)));
+    renderSnippetList();
+    showMessage(`片段已添加（共 ${snippetList.length} 个）`, 'success');
+}
+
+async function deleteSavedSnippet(i) {
+    const saved = savedSnippets[i];
+    if (!saved || !confirm(`确定从片段库删除 ${saved.name} 吗？`)) return;
+    try {
+        const response = await fetch(`/api/snippets/${saved.id}`, { method: 'DELETE' });
+        const result = await response.json();
+        if (!response.ok) { showMessage('删除失败: ' + result.error, 'error'); return; }
+        showMessage('删除成功', 'success');
+        loadSavedSnippets();
+    } catch (error) {
+        showMessage('删除失败: ' + error.message, 'error');
+    }
+}
+
 function removeSnippet(idx) {
     snippetList.splice(idx, 1);
     renderSnippetList();
@@ -1494,14 +2424,14 @@ function removeSnippet(idx) {
 function clearSnippets() {
     if (snippetList.length === 0) return;
     if (!confirm(`确定清空全部 ${snippetList.length} 个自定义片段吗？`)) return;
-    snippetList = [];
+    snippetList.splice(0, snippetList.length);
     renderSnippetList();
-    if (snippetMergeEnabled) toggleSnippetMerge();
+    if (snippetMergeEnabled && totalSnippetCount() === 0) toggleSnippetMerge();
     showMessage('已清空所有片段', 'success');
 }
 
 function toggleSnippetMerge() {
-    if (snippetList.length === 0 && !snippetMergeEnabled) {
+    if (totalSnippetCount() === 0 && !snippetMergeEnabled) {
         showMessage('请先添加至少一个自定义片段', 'error'); return;
     }
     snippetMergeEnabled = !snippetMergeEnabled;
@@ -1520,22 +2450,27 @@ function toggleSnippetMerge() {
     }
 }
 
+// 预览中的 Java 代码（关联查询附带 DTO 源码）
+function snippetJavaPreview(result) {
+    return result.dtoCode ? result.javaCode + '\n' + result.dtoCode : result.javaCode;
+}
+
 async function showSnippetPreviewModal(idx) {
-    if (selectedTables.length !== 1) { showMessage('请先选择一张表', 'error'); return; }
-    const tableName = selectedTables[0];
-    const mapperName = toPascalCase(tableName) + 'Mapper';
+    if (selectedTables.length === 0) { showMessage('请先选择表', 'error'); return; }
+    const tableName = currentSnippetTable();
+    const mapperName = namesFor(tableName).mapperName;
     const modelPackage = document.getElementById('modelPackage').value || 'com.example.model';
-    const modelType = modelPackage + '.' + toPascalCase(tableName);
+    const modelType = modelPackage + '.' + namesFor(tableName).domainObjectName;
     
     showMessage('正在生成预览...', 'info');
     try {
         const response = await fetch('/api/snippet/preview', {
             method: 'POST', headers: { 'Content-Type': 'application/json' },
-            body: JSON.stringify({ tableName, mapperName, modelType, snippetConfigs: [snippetList[idx]] })
+            body: JSON.stringify({ databaseId: currentDatabaseId, tableName, mapperName, modelType, snippetConfigs: [snippetList[idx]], useLombok: useLombokForSnippets() })
         });
         const result = await response.json();
         if (response.ok && result.success) {
-            document.getElementById('snippetJavaCode').textContent = result.javaCode;
+            document.getElementById('snippetJavaCode').textContent = snippetJavaPreview(result);
             document.getElementById('snippetXmlCode').textContent = result.xmlCode;
             document.getElementById('snippetPreviewModal').style.display = 'block';
         } else {
@@ -1550,6 +2485,39 @@ function hideSnippetPreviewModal() {
     document.getElementById('snippetPreviewModal').style.display = 'none';
 }
 
+// 按示例参数对预览中的 XML（或粘贴的 Mapper XML）求值，显示最终 SQL 与绑定参数
+async function evaluateSnippetSQL() {
+    const resultEl = document.getElementById('sqlEvalResult');
+    const xml = document.getElementById('sqlEvalXml').value.trim() || document.getElementById('snippetXmlCode').textContent;
+    const paramsText = document.getElementById('sqlEvalParams').value.trim();
+    let params = {};
+    if (paramsText) {
+        try {
+            params = JSON.parse(paramsText);
+        } catch (e) {
+            showMessage('示例参数不是有效的 JSON: ' + e.message, 'error');
+            return;
+        }
+    }
+    try {
+        const response = await fetch('/api/snippet/evaluate', {
+            method: 'POST', headers: { 'Content-Type': 'application/json' },
+            body: JSON.stringify({ xml, statementId: document.getElementById('sqlEvalStatementId').value.trim(), params })
+        });
+        const result = await response.json();
+        if (!response.ok || !result.success) {
+            showMessage('求值失败: ' + result.error, 'error');
+            return;
+        }
+        const paramLines = result.params.map((p, i) =>
+            `  ${i + 1}. ${p.name} = ${JSON.stringify(p.value)}${p.jdbcType ? ' (' + p.jdbcType + ')' : ''}`);
+        resultEl.textContent = `-- ${result.statementId}\n${result.sql}\n\n-- 绑定参数\n${paramLines.join('\n') || '  (无)'}`;
+        resultEl.style.display = 'block';
+    } catch (error) {
+        showMessage('求值失败: ' + error.message, 'error');
+    }
+}
+
 // 单独片段的内联预览
 async function toggleSnippetInlinePreview(idx) {
     const wrapper = document.getElementById(`snippet-item-wrapper-${idx}`);
@@ -1573,14 +2541,14 @@ async function toggleSnippetInlinePreview(idx) {
 
     try {
         const snippet = snippetList[idx];
-        const tableName = selectedTables[0] || 'unknown_table';
-        const mapperName = toPascalCase(tableName) + 'Mapper';
+        const tableName = currentSnippetTable() || 'unknown_table';
+        const mapperName = namesFor(tableName).mapperName;
         const modelPackage = document.getElementById('modelPackage').value || 'com.example.model';
-        const modelType = modelPackage + '.' + toPascalCase(tableName);
+        const modelType = modelPackage + '.' + namesFor(tableName).domainObjectName;
 
         const response = await fetch('/api/snippet/preview', {
             method: 'POST', headers: { 'Content-Type': 'application/json' },
-            body: JSON.stringify({ tableName, mapperName, modelType, snippetConfigs: [snippet] })
+            body: JSON.stringify({ databaseId: currentDatabaseId, tableName, mapperName, modelType, snippetConfigs: [snippet], useLombok: useLombokForSnippets() })
         });
         const result = await response.json();
         if (response.ok && result.success) {
@@ -1589,7 +2557,7 @@ async function toggleSnippetInlinePreview(idx) {
                     <div class="snippet-preview-title">
                         <span>📄 Java 接口方法</span>
                     </div>
-                    <pre class="snippet-code-block">${escapeHtml(result.javaCode)}</pre>
+                    <pre class="snippet-code-block">${escapeHtml(snippetJavaPreview(result))}</pre>
                 </div>
                 <div class="snippet-preview-section" style="margin-top: 10px;">
                     <div class="snippet-preview-title">
@@ -1607,11 +2575,11 @@ async function toggleSnippetInlinePreview(idx) {
 }
 
 async function previewCurrentSnippet() {
-    if (selectedTables.length !== 1) { showMessage('请先选择一张表', 'error'); return; }
-    const tableName = selectedTables[0];
-    const mapperName = toPascalCase(tableName) + 'Mapper';
+    if (selectedTables.length === 0) { showMessage('请先选择表', 'error'); return; }
+    const tableName = currentSnippetTable();
+    const mapperName = namesFor(tableName).mapperName;
     const modelPackage = document.getElementById('modelPackage').value || 'com.example.model';
-    const modelType = modelPackage + '.' + toPascalCase(tableName);
+    const modelType = modelPackage + '.' + namesFor(tableName).domainObjectName;
     
     const currentCfg = buildCurrentSnippetConfig();
     const hasFields =
@@ -1633,11 +2601,11 @@ async function previewCurrentSnippet() {
     try {
         const response = await fetch('/api/snippet/preview', {
             method: 'POST', headers: { 'Content-Type': 'application/json' },
-            body: JSON.stringify({ tableName, mapperName, modelType, snippetConfigs: [currentCfg] })
+            body: JSON.stringify({ databaseId: currentDatabaseId, tableName, mapperName, modelType, snippetConfigs: [currentCfg], useLombok: useLombokForSnippets() })
         });
         const result = await response.json();
         if (response.ok && result.success) {
-            document.getElementById('snippetJavaCode').textContent = result.javaCode;
+            document.getElementById('snippetJavaCode').textContent = snippetJavaPreview(result);
             document.getElementById('snippetXmlCode').textContent = result.xmlCode;
             document.getElementById('snippetPreviewModal').style.display = 'block';
         } else {
@@ -1648,13 +2616,145 @@ async function previewCurrentSnippet() {
     }
 }
 
+// 以示例参数渲染当前配置的 SQL，在数据库上执行 EXPLAIN 校验语法并提示全表扫描
+async function explainCurrentSnippet() {
+    if (selectedTables.length === 0) { showMessage('请先选择表', 'error'); return; }
+    const tableName = currentSnippetTable();
+    const modelPackage = document.getElementById('modelPackage').value || 'com.example.model';
+    const cfg = buildCurrentSnippetConfig();
+    if (!cfg.methodName) cfg.methodName = computeMethodName(cfg);
+    const params = readSnippetSampleParams();
+    if (params === undefined) return;
+
+    const resultEl = document.getElementById('snippetExplainResult');
+    resultEl.className = 'snippet-explain-result';
+    resultEl.innerHTML = '<div style="color: #666;">⏳ 正在获取执行计划...</div>';
+    resultEl.style.display = 'block';
+    try {
+        const response = await fetch('/api/snippet/explain', {
+            method: 'POST', headers: { 'Content-Type': 'application/json' },
+            body: JSON.stringify({
+                databaseId: currentDatabaseId, tableName,
+                mapperName: namesFor(tableName).mapperName,
+                modelType: modelPackage + '.' + namesFor(tableName).domainObjectName,
+                snippetConfig: cfg, params
+            })
+        });
+        const result = await response.json();
+        if (!response.ok || !result.success) {
+            resultEl.classList.add('snippet-explain-error');
+            resultEl.innerHTML = `❌ 执行计划获取失败: ${escapeHtml(result.error || '未知错误')}`;
+            return;
+        }
+
+        const sqlBlock = `<pre class="snippet-code-block">${escapeHtml(result.sql)}</pre>`;
+        if (!result.valid) {
+            resultEl.classList.add('snippet-explain-error');
+            resultEl.innerHTML = `<div>❌ 数据库拒绝了该语句: ${escapeHtml(result.sqlError)}</div>${sqlBlock}`;
+            return;
+        }
+
+        let summary = '<div>✅ 语句有效</div>';
+        if (result.fullScans.length > 0) {
+            resultEl.classList.add('snippet-explain-warning');
+            summary = `<div>⚠️ 全表扫描: ${escapeHtml(result.fullScans.join(', '))}（检查条件列是否有索引）</div>`;
+        }
+        resultEl.innerHTML = summary + sqlBlock + snippetResultTable(result.columns, result.rows);
+    } catch (error) {
+        resultEl.classList.add('snippet-explain-error');
+        resultEl.innerHTML = `❌ 执行计划获取失败: ${escapeHtml(error.message)}`;
+    }
+}
+
+// 读取执行计划/试运行的示例参数，留空返回 null（服务端自动生成），JSON 无效时返回 undefined
+function readSnippetSampleParams() {
+    const text = document.getElementById('snippetSampleParams').value.trim();
+    if (!text) return null;
+    try {
+        return JSON.parse(text);
+    } catch (e) {
+        showMessage('示例参数不是有效的 JSON: ' + e.message, 'error');
+        return undefined;
+    }
+}
+
+function snippetResultTable(columns, rows) {
+    const header = columns.map(c => `<th>${escapeHtml(c)}</th>`).join('');
+    const body = rows.map(r => `<tr>${r.map(v => `<td>${escapeHtml(v)}</td>`).join('')}</tr>`).join('');
+    return `<table class="snippet-explain-table"><thead><tr>${header}</tr></thead><tbody>${body}</tbody></table>`;
+}
+
+// 在沙箱中试运行当前配置：查询显示前 N 行及列与属性的对照，增删改显示影响行数（总是回滚）
+async function executeCurrentSnippet() {
+    if (selectedTables.length === 0) { showMessage('请先选择表', 'error'); return; }
+    const tableName = currentSnippetTable();
+    const modelPackage = document.getElementById('modelPackage').value || 'com.example.model';
+    const cfg = buildCurrentSnippetConfig();
+    if (!cfg.methodName) cfg.methodName = computeMethodName(cfg);
+    const params = readSnippetSampleParams();
+    if (params === undefined) return;
+    const scoped = tableOverrides[tableName];
+
+    const resultEl = document.getElementById('snippetExplainResult');
+    resultEl.className = 'snippet-explain-result';
+    resultEl.innerHTML = '<div style="color: #666;">⏳ 正在试运行...</div>';
+    resultEl.style.display = 'block';
+    try {
+        const response = await fetch('/api/snippet/execute', {
+            method: 'POST', headers: { 'Content-Type': 'application/json' },
+            body: JSON.stringify({
+                databaseId: currentDatabaseId, tableName,
+                mapperName: namesFor(tableName).mapperName,
+                modelType: modelPackage + '.' + namesFor(tableName).domainObjectName,
+                snippetConfig: cfg, params,
+                // 命名规则和列覆盖用于推导实体属性，与生成的实体类一致
+                config: {
+                    naming: buildNamingRules(),
+                    useActualColumnNames: document.getElementById('useActualColumnNames').checked,
+                    ignoredColumns: scoped ? scoped.ignoredColumns : ignoredColumns,
+                    columnOverrides: columnOverridesFor(tableName)
+                }
+            })
+        });
+        const result = await response.json();
+        if (!response.ok || !result.success) {
+            resultEl.classList.add('snippet-explain-error');
+            resultEl.innerHTML = `<div>❌ ${escapeHtml(result.error || '试运行失败')}</div>` +
+                (result.sql ? `<pre class="snippet-code-block">${escapeHtml(result.sql)}</pre>` : '');
+            return;
+        }
+
+        const sqlBlock = `<pre class="snippet-code-block">${escapeHtml(result.sql)}</pre>`;
+        if (cfg.operation !== 'select') {
+            resultEl.innerHTML = `<div>✅ 影响 ${result.rowsAffected} 行（事务已回滚，数据未修改）</div>${sqlBlock}`;
+            return;
+        }
+
+        let html = `<div>✅ 返回 ${result.rows.length} 行${result.truncated ? '（仅显示前 ' + result.rows.length + ' 行）' : ''}</div>${sqlBlock}`;
+        html += snippetResultTable(result.columns, result.rows);
+        if (result.mapping) {
+            const unmapped = result.mapping.filter(m => m.returned && !m.property).map(m => m.column);
+            const missing = result.mapping.filter(m => !m.returned).map(m => m.property);
+            if (unmapped.length > 0 || missing.length > 0) resultEl.classList.add('snippet-explain-warning');
+            html += '<div style="margin-top: 8px;">🔗 列与属性对照: ' + result.mapping.filter(m => m.returned && m.property)
+                .map(m => `${escapeHtml(m.column)} → ${escapeHtml(m.property)}`).join('，') + '</div>';
+            if (unmapped.length > 0) html += `<div>⚠️ 未映射到属性的列: ${escapeHtml(unmapped.join(', '))}</div>`;
+            if (missing.length > 0) html += `<div>⚠️ 结果集中缺少的属性: ${escapeHtml(missing.join(', '))}</div>`;
+        }
+        resultEl.innerHTML = html;
+    } catch (error) {
+        resultEl.classList.add('snippet-explain-error');
+        resultEl.innerHTML = `❌ 试运行失败: ${escapeHtml(error.message)}`;
+    }
+}
+
 async function previewSnippet() {
     if (snippetList.length === 0) { showMessage('请先添加至少一个自定义片段', 'error'); return; }
-    if (selectedTables.length !== 1) { showMessage('请先选择一张表', 'error'); return; }
-    const tableName = selectedTables[0];
-    const mapperName = toPascalCase(tableName) + 'Mapper';
+    if (selectedTables.length === 0) { showMessage('请先选择表', 'error'); return; }
+    const tableName = currentSnippetTable();
+    const mapperName = namesFor(tableName).mapperName;
     const modelPackage = document.getElementById('modelPackage').value || 'com.example.model';
-    const modelType = modelPackage + '.' + toPascalCase(tableName);
+    const modelType = modelPackage + '.' + namesFor(tableName).domainObjectName;
     
     let globalPreview = document.getElementById('globalSnippetPreview');
     if (!globalPreview) {
@@ -1679,7 +2779,7 @@ async function previewSnippet() {
     try {
         const response = await fetch('/api/snippet/preview', {
             method: 'POST', headers: { 'Content-Type': 'application/json' },
-            body: JSON.stringify({ tableName, mapperName, modelType, snippetConfigs: snippetList })
+            body: JSON.stringify({ databaseId: currentDatabaseId, tableName, mapperName, modelType, snippetConfigs: snippetList, useLombok: useLombokForSnippets() })
         });
         const result = await response.json();
         if (response.ok && result.success) {
@@ -1688,7 +2788,7 @@ async function previewSnippet() {
                     <div class="snippet-preview-title">
                         <span>📄 全局 Mapper 接口方法</span>
                     </div>
-                    <pre class="snippet-code-block">${escapeHtml(result.javaCode)}</pre>
+                    <pre class="snippet-code-block">${escapeHtml(snippetJavaPreview(result))}</pre>
                 </div>
                 <div class="snippet-preview-section" style="margin-top: 10px;">
                     <div class="snippet-preview-title">
@@ -1732,12 +2832,31 @@ document.addEventListener('DOMContentLoaded', function () {
     document.getElementById('dbType').onchange = e => {
         const ports = { 'MySQL': '3306', 'PostgreSQL': '5432', 'Oracle': '1521' };
         document.getElementById('port').value = ports[e.target.value] || '3306';
+        toggleOracleOptions();
     };
     document.getElementById('useJsonProperty').onchange = e => {
         const lbl = document.getElementById('jsonPropertyOptionsLabel');
         lbl.style.display = e.target.checked ? 'flex' : 'none';
         if (!e.target.checked) document.getElementById('jsonPropertyUpperCase').checked = false;
     };
+    document.getElementById('btnPreviewNaming').onclick = previewNaming;
+    ['namingTablePrefixes', 'namingColumnPrefixes', 'namingModelSuffix', 'namingMapperSuffix',
+        'namingTableRenames', 'namingColumnRenames'].forEach(id => {
+        document.getElementById(id).onchange = onNamingRulesChanged;
+    });
+    document.getElementById('keyStrategy').onchange = e => {
+        document.getElementById('sequenceNameGroup').style.display = e.target.value === 'sequence' ? 'block' : 'none';
+    };
+    document.getElementById('useUpsert').onchange = e => {
+        document.getElementById('batchUpsertLabel').style.display = e.target.checked ? 'flex' : 'none';
+        document.getElementById('upsertConflictIndexGroup').style.display = e.target.checked ? 'block' : 'none';
+        if (!e.target.checked) document.getElementById('useBatchUpsert').checked = false;
+    };
+    document.getElementById('useBeanValidation').onchange = e => {
+        const lbl = document.getElementById('jakartaValidationLabel');
+        lbl.style.display = e.target.checked ? 'flex' : 'none';
+        if (!e.target.checked) document.getElementById('useJakartaValidation').checked = false;
+    };
     document.addEventListener('keydown', e => {
         if (e.key === 'Escape') { hideConnectionModal(); hideColumnModal(); hideSnippetPreviewModal(); hideAccountModal(); }
     });